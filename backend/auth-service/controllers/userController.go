@@ -262,6 +262,8 @@ func RegisterIntoUni(user *models.User) error {
 		url = "http://university-service:8088/students/create"
 	case models.ProfessorType:
 		url = "http://university-service:8088/professors/create"
+	case models.AssistantType:
+		url = "http://university-service:8088/assistants/create"
 	case models.AdministratorType:
 		url = "http://university-service:8088/administrators/create"
 	case models.StudentServiceType:
@@ -349,6 +351,8 @@ func DeleteFromUni(user *models.User) error {
 		url = "http://university-service:8088/students/" + user.User_id
 	case models.ProfessorType:
 		url = "http://university-service:8088/professors/" + user.User_id
+	case models.AssistantType:
+		url = "http://university-service:8088/assistants/" + user.User_id
 	case models.AdministratorType:
 		url = "http://university-service:8088/administrators/" + user.User_id
 	case models.StudentServiceType:
@@ -5,6 +5,7 @@ type UserType string
 const (
 	StudentType        UserType = "STUDENT"
 	ProfessorType      UserType = "PROFESSOR"
+	AssistantType      UserType = "ASSISTANT"
 	AdministratorType  UserType = "ADMINISTRATOR"
 	EmployerType       UserType = "EMPLOYER"
 	CandidateType      UserType = "CANDIDATE"
@@ -21,6 +22,7 @@ func ValidUserTypes() []UserType {
 	return []UserType{
 		StudentType,
 		ProfessorType,
+		AssistantType,
 		AdministratorType,
 		EmployerType,
 		CandidateType,
@@ -45,6 +47,7 @@ func IsValidUserType(userType string) bool {
 func IsAcademicUser(userType UserType) bool {
 	return userType == StudentType ||
 		userType == ProfessorType ||
+		userType == AssistantType ||
 		userType == AdministratorType ||
 		userType == StudentServiceType
 }
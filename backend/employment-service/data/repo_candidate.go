@@ -195,6 +195,35 @@ func (er *EmploymentRepo) UpdateCandidate(candidateId string, candidate *models.
 	return nil
 }
 
+// SetCandidateDegree records the candidate's degree together with the final GPA and ESPB.
+func (er *EmploymentRepo) SetCandidateDegree(candidateId string, degree *models.Degree) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+
+	candidateCollection := OpenCollection(er.cli, "candidates")
+	objectId, err := primitive.ObjectIDFromHex(candidateId)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %v", err)
+	}
+
+	result, err := candidateCollection.UpdateOne(ctx, bson.M{"_id": objectId}, bson.M{
+		"$set": bson.M{
+			"degree": degree,
+			"gpa":    degree.GPA,
+			"esbp":   degree.ESPB,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not update degree for candidate with id: %s, error: %v", candidateId, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no candidate found with id: %s", candidateId)
+	}
+
+	er.logger.Printf("Recorded degree %s for candidate with id: %s", degree.DiplomaNumber, candidateId)
+	return nil
+}
+
 func (er *EmploymentRepo) DeleteCandidate(candidateId string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
 	defer cancel()
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type TranscriptEntry struct {
+	SubjectName string    `json:"subject_name"`
+	Year        int       `json:"year"`
+	Semester    int       `json:"semester"`
+	ESPB        int       `json:"espb"`
+	Grade       int       `json:"grade"`
+	PassedAt    time.Time `json:"passed_at"`
+}
+
+// TranscriptVerification is the university-service answer for a transcript verification code.
+type TranscriptVerification struct {
+	Valid            bool              `json:"valid"`
+	VerificationCode string            `json:"verification_code"`
+	StudentName      string            `json:"student_name,omitempty"`
+	MajorName        string            `json:"major_name,omitempty"`
+	IssuedAt         time.Time         `json:"issued_at,omitempty"`
+	GPA              float64           `json:"gpa,omitempty"`
+	TotalESPB        int               `json:"total_espb,omitempty"`
+	PassedCount      int               `json:"passed_count,omitempty"`
+	Entries          []TranscriptEntry `json:"entries,omitempty"`
+}
+
+// VerifyTranscript asks university-service whether a transcript verification code was issued by it.
+// An unknown code is not an error: the result simply has Valid set to false.
+func VerifyTranscript(code string, logger *log.Logger) (*TranscriptVerification, error) {
+	verifyURL := fmt.Sprintf("%s/transcripts/verify/%s", universityServiceURL, url.PathEscape(code))
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Get(verifyURL)
+	if err != nil {
+		logger.Printf("Failed to reach university service for transcript verification: %v", err)
+		return nil, fmt.Errorf("failed to verify transcript: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		logger.Printf("University service returned status %d for transcript %s", resp.StatusCode, code)
+		return nil, fmt.Errorf("university service returned status %d", resp.StatusCode)
+	}
+
+	var verification TranscriptVerification
+	if err := json.NewDecoder(resp.Body).Decode(&verification); err != nil {
+		return nil, fmt.Errorf("failed to decode transcript verification: %v", err)
+	}
+	verification.VerificationCode = code
+	return &verification, nil
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 
+	helper "employment-service/helpers"
 	"employment-service/models"
 	"employment-service/internal/services"
 
@@ -99,6 +100,30 @@ func (h *CandidateHandler) UpdateCandidate() gin.HandlerFunc {
 	}
 }
 
+// UpdateCandidateDegree is called by university-service when the candidate's diploma is issued.
+func (h *CandidateHandler) UpdateCandidateDegree() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		candidateId := c.Param("id")
+		var degree models.Degree
+		if err := c.ShouldBindJSON(&degree); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		err := h.service.SetCandidateDegree(candidateId, &degree)
+		if err != nil {
+			if isNotFoundError(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Candidate degree updated successfully"})
+	}
+}
+
 func (h *CandidateHandler) DeleteCandidate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		candidateId := c.Param("id")
@@ -114,3 +139,15 @@ func (h *CandidateHandler) DeleteCandidate() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "Candidate deleted successfully"})
 	}
 }
+
+// VerifyTranscript lets an employer confirm that a candidate's transcript was issued by the university.
+func (h *CandidateHandler) VerifyTranscript() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verification, err := helper.VerifyTranscript(c.Param("code"), h.logger)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, verification)
+	}
+}
@@ -65,7 +65,9 @@ func setupProtectedRoutes(router *gin.Engine, h *handlers.Handlers) {
 		protected.GET("/candidates/:id", h.Candidate.GetCandidate())
 		protected.GET("/candidates/user/:user_id", h.Candidate.GetCandidateByUserID())
 		protected.PUT("/candidates/:id", h.Candidate.UpdateCandidate())
+		protected.PUT("/candidates/:id/degree", middleware.AuthorizeRoles([]string{"ADMIN"}), h.Candidate.UpdateCandidateDegree())
 		protected.DELETE("/candidates/:id", h.Candidate.DeleteCandidate())
+		protected.GET("/candidates/transcripts/verify/:code", middleware.AuthorizeRoles([]string{"ADMIN", "EMPLOYER"}), h.Candidate.VerifyTranscript())
 
 		protected.POST("/job-listings", middleware.AuthorizeRoles([]string{"ADMIN", "EMPLOYER"}), h.Job.CreateJobListing())
 		protected.PUT("/job-listings/:id", middleware.AuthorizeRoles([]string{"ADMIN", "EMPLOYER"}), h.Job.UpdateJobListing())
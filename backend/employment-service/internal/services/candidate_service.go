@@ -41,6 +41,10 @@ func (s *CandidateService) UpdateCandidate(candidateID string, candidate *models
 	return s.repo.UpdateCandidate(candidateID, candidate)
 }
 
+func (s *CandidateService) SetCandidateDegree(candidateID string, degree *models.Degree) error {
+	return s.repo.SetCandidateDegree(candidateID, degree)
+}
+
 func (s *CandidateService) DeleteCandidate(candidateID string) error {
 	return s.repo.DeleteCandidate(candidateID)
 }
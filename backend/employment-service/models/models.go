@@ -94,6 +94,17 @@ type Candidate struct {
 	CVBase64      string             `bson:"cv_base64,omitempty" json:"cv_base64,omitempty"`
 	Skills        []string           `bson:"skills,omitempty" json:"skills,omitempty"`
 	ProfilePicBase64 string          `bson:"profile_pic_base64,omitempty" json:"profile_pic_base64,omitempty"`
+	Degree        *Degree            `bson:"degree,omitempty" json:"degree,omitempty"`
+}
+
+// Degree is the completed university degree, reported by university-service when the diploma is issued.
+type Degree struct {
+	DiplomaNumber string    `bson:"diploma_number" json:"diploma_number" binding:"required"`
+	Major         string    `bson:"major" json:"major"`
+	GraduatedAt   time.Time `bson:"graduated_at" json:"graduated_at"`
+	GPA           float64   `bson:"gpa" json:"gpa"`
+	ESPB          int       `bson:"espb" json:"espb"`
+	ThesisTopic   string    `bson:"thesis_topic,omitempty" json:"thesis_topic,omitempty"`
 }
 
 type JobListing struct {
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// academicYearErrorStatus maps academic calendar errors to HTTP status codes.
+func academicYearErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrAcademicYearNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrAcademicYearExists):
+		return http.StatusConflict
+	case errors.Is(err, repositories.ErrInvalidAcademicYear):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func academicYearFromRequest(req repositories.AcademicYearRequest) *repositories.AcademicYear {
+	return &repositories.AcademicYear{
+		Year:           req.Year,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Semesters:      req.Semesters,
+		Holidays:       req.Holidays,
+		NonWorkingDays: req.NonWorkingDays,
+	}
+}
+
+func (ctrl *Controllers) CreateAcademicYear(c *gin.Context) {
+	var req repositories.AcademicYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	year := academicYearFromRequest(req)
+	if err := ctrl.Repo.CreateAcademicYear(year); err != nil {
+		c.JSON(academicYearErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, year)
+}
+
+func (ctrl *Controllers) GetAcademicYears(c *gin.Context) {
+	years, err := ctrl.Repo.GetAcademicYears()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if years == nil {
+		years = []repositories.AcademicYear{}
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+// GetCurrentAcademicYear returns the academic year containing today, or the one containing ?date=YYYY-MM-DD.
+func (ctrl *Controllers) GetCurrentAcademicYear(c *gin.Context) {
+	date := time.Now()
+	if d := c.Query("date"); d != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", d, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+			return
+		}
+		date = parsed
+	}
+	year, err := ctrl.Repo.GetAcademicYearForDate(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if year == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No academic year is defined for this date"})
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+func (ctrl *Controllers) GetAcademicYearByID(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	year, err := ctrl.Repo.GetAcademicYearByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if year == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Academic year not found"})
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+func (ctrl *Controllers) UpdateAcademicYear(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	var req repositories.AcademicYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	year := academicYearFromRequest(req)
+	year.ID = id
+	if err := ctrl.Repo.UpdateAcademicYear(year); err != nil {
+		c.JSON(academicYearErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	updated, err := ctrl.Repo.GetAcademicYearByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (ctrl *Controllers) DeleteAcademicYear(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	if err := ctrl.Repo.DeleteAcademicYear(id); err != nil {
+		c.JSON(academicYearErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Academic year deleted successfully"})
+}
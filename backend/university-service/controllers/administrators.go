@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"net/http"
-	"time"
 	repositories "university-service/repository"
 
 	"github.com/gin-gonic/gin"
@@ -134,10 +133,13 @@ func (ctrl *Controllers) GetGraduationRequestsByStudentID(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, requests)
 }
+
+// UpdateGraduationRequest is the older review endpoint taking a Status of Approved or Rejected.
+// Approval no longer graduates the student; it starts the thesis workflow.
 func (ctrl *Controllers) UpdateGraduationRequest(c *gin.Context) {
 	id := c.Param("id")
-	var request repositories.GraduationRequest
-	if err := c.BindJSON(&request); err != nil {
+	var body repositories.GraduationRequest
+	if err := c.BindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -146,36 +148,16 @@ func (ctrl *Controllers) UpdateGraduationRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	request.ID = objectID
-	err = ctrl.Repo.UpdateGraduationRequest(&request)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if body.Status != repositories.Approved && body.Status != repositories.Rejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be Approved or Rejected"})
 		return
 	}
-	if request.Status == "Approved" {
-		_, _ = ctrl.CreateNotificationByRecipient(repositories.Notification{
-			RecipientID:    request.StudentID,
-			RecipientType:  "id",
-			RecipientValue: request.StudentID.Hex(),
-			Title:          "Graduation request",
-			Content:        "Congratulations! Your graduation request has been approved",
-		})
-
-		err = ctrl.Repo.GraduateStudent(request.StudentID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	} else {
-		_, _ = ctrl.CreateNotificationByRecipient(repositories.Notification{
-			RecipientID:    request.StudentID,
-			RecipientType:  "id",
-			RecipientValue: request.StudentID.Hex(),
-			Title:          "Graduation request",
-			Content:        "Your graduation request has been updated to " + string(request.Status) + " with the following comments: " + request.Comments,
-			CreatedAt:      time.Now(),
-		})
+	request, err := ctrl.Repo.ReviewGraduationRequest(objectID, body.Status == repositories.Approved, body.Comments)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
 	}
+	ctrl.notifyGraduationStudent(request, reviewNotification(request))
 	c.JSON(http.StatusOK, request)
 }
 
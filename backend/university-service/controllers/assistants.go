@@ -83,7 +83,7 @@ func (ctrl *Controllers) DeleteAssistant(c *gin.Context) {
 
 	err := ctrl.Repo.DeleteAssistant(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
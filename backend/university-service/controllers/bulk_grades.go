@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateExamGradesBulk grades many students of an exam session at once. The grades are sent as
+// JSON, {"grades": [{"index_number": "SW-12/2025", "grade": 8, "comments": "..."}]}, or as a CSV
+// file (multipart field "file" or a text/csv body) with the columns index_number or student_id,
+// grade or exam_points, and optionally comments. With exam_points the grade is computed from the
+// subject's grading scheme. Valid rows are applied together and the rest are reported
+// with the reason they were rejected.
+func (ctrl *Controllers) CreateExamGradesBulk(c *gin.Context) {
+	examSession, err := ctrl.Repo.GetExamSessionByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+
+	rows, err := bulkGradeRows(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No grades to apply"})
+		return
+	}
+
+	result, err := ctrl.Repo.CreateExamGradesBulk(examSession, rows)
+	switch {
+	case errors.Is(err, repositories.ErrExamNotHeld):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Each student appears once in the result, so GPA is recomputed and a notification sent
+	// once per student
+	for i := range result.Grades {
+		grade := &result.Grades[i]
+		if grade.Passed {
+			if err := ctrl.UpdateStudentGPA(&grade.Student); err != nil {
+				ctrl.logger.Printf("Failed to update GPA of student %s: %v", grade.Student.ID.Hex(), err)
+			}
+		}
+		_, _ = ctrl.CreateNotificationByRecipient(examGradeNotification(examSession.Subject.Name, grade))
+	}
+	if result.Applied > 0 {
+		if _, err := ctrl.Repo.CompleteExamSessionIfGraded(examSession.ID); err != nil {
+			ctrl.logger.Printf("Failed to complete exam session %s: %v", examSession.ID.Hex(), err)
+		}
+	}
+
+	status := http.StatusOK
+	if result.Applied == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, result)
+}
+
+// bulkGradeRows reads the rows of a bulk grade entry from a CSV upload or a JSON body.
+func bulkGradeRows(c *gin.Context) ([]repositories.BulkGradeRow, error) {
+	switch c.ContentType() {
+	case "multipart/form-data":
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("a CSV file is required in the \"file\" field")
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseGradeCSV(f)
+	case "text/csv":
+		return parseGradeCSV(c.Request.Body)
+	default:
+		var req repositories.BulkGradeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return nil, err
+		}
+		return req.Grades, nil
+	}
+}
+
+// spreadsheetCSVReader reads a CSV file exported from a spreadsheet. Both comma and semicolon
+// separated files are accepted, since spreadsheets export either depending on the locale.
+func spreadsheetCSVReader(content []byte) *csv.Reader {
+	text := strings.TrimPrefix(string(content), "\ufeff") // spreadsheet programs often prepend a byte order mark
+	firstLine, _, _ := strings.Cut(text, "\n")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	return reader
+}
+
+// parseGradeCSV reads grade rows from a CSV file with a header line. Rows are numbered by their
+// line in the file.
+func parseGradeCSV(r io.Reader) ([]repositories.BulkGradeRow, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := spreadsheetCSVReader(content)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasIndex := columns["index_number"]
+	_, hasStudent := columns["student_id"]
+	_, hasGrade := columns["grade"]
+	_, hasPoints := columns["exam_points"]
+	if (!hasGrade && !hasPoints) || (!hasIndex && !hasStudent) {
+		return nil, fmt.Errorf("the CSV header must contain grade or exam_points, and index_number or student_id")
+	}
+
+	rows := []repositories.BulkGradeRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		line, _ := reader.FieldPos(0)
+		// A grade that is not a number is left at 0 and reported as out of range
+		grade, _ := strconv.Atoi(field("grade"))
+		row := repositories.BulkGradeRow{
+			Row:         line,
+			StudentID:   field("student_id"),
+			IndexNumber: field("index_number"),
+			Grade:       grade,
+			Comments:    field("comments"),
+		}
+		if value := field("exam_points"); value != "" {
+			points, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+			if err != nil {
+				points = -1 // reported as out of range for the component
+			}
+			row.ExamPoints = &points
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// deletableEntities maps URL entity names to the collections that carry a cascade policy.
+var deletableEntities = map[string]string{
+	"students":      "student",
+	"professors":    "professor",
+	"departments":   "department",
+	"subjects":      "subjects",
+	"majors":        "majors",
+	"exam-sessions": "exam_sessions",
+}
+
+// respondDeleteError writes the response for a failed cascading delete.
+// Restricted deletes return 409 together with the documents that block them.
+func respondDeleteError(c *gin.Context, err error) {
+	var restricted *repositories.DeleteRestrictedError
+	switch {
+	case errors.As(err, &restricted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "impact": restricted.Impact})
+	case errors.Is(err, repositories.ErrEntityNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// PreviewDelete is a dry run that reports what deleting /:entity/:id would delete, unlink or
+// be blocked by, so studentska služba can review it before confirming the delete.
+func (ctrl *Controllers) PreviewDelete(c *gin.Context) {
+	entityType, ok := deletableEntities[c.Param("entity")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity must be one of: students, professors, departments, subjects, majors, exam-sessions"})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	impact, err := ctrl.Repo.PreviewDelete(entityType, id)
+	if err != nil {
+		respondDeleteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, impact)
+}
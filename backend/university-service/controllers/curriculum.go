@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetElectiveGroups returns the major's elective groups.
+func (ctrl *Controllers) GetElectiveGroups(c *gin.Context) {
+	majorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid major ID"})
+		return
+	}
+	major, err := ctrl.Repo.GetMajorByID(majorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if major == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Major not found"})
+		return
+	}
+	groups := major.ElectiveGroups
+	if groups == nil {
+		groups = []repositories.ElectiveGroup{}
+	}
+	c.JSON(http.StatusOK, groups)
+}
+
+// SetElectiveGroups replaces the major's elective groups, e.g.
+// {"elective_groups": [{"name": "Izborni blok 1", "year": 3, "choose": 2, "subject_ids": ["...", "..."]}]}.
+// The subjects must be part of the major's curriculum and of the group's year.
+func (ctrl *Controllers) SetElectiveGroups(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+	var req repositories.SetElectiveGroupsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	major, err := ctrl.Repo.SetElectiveGroups(majorID, req.ElectiveGroups)
+	if errors.Is(err, repositories.ErrInvalidElectiveGroups) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, major.ElectiveGroups)
+}
+
+// SetSubjectPrerequisites replaces the subjects that must be passed before a student may register
+// for the subject's exam, e.g. {"prerequisite_ids": ["..."]}.
+func (ctrl *Controllers) SetSubjectPrerequisites(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	var req repositories.SetPrerequisitesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	subject, err := ctrl.Repo.SetPrerequisites(subjectID, req.PrerequisiteIDs)
+	if errors.Is(err, repositories.ErrInvalidPrerequisites) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subject)
+}
+
+func curriculumVersionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrMajorNotFound), errors.Is(err, repositories.ErrCurriculumVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidCurriculumVersion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetCurriculumVersions returns the major's published curriculum versions, oldest first.
+func (ctrl *Controllers) GetCurriculumVersions(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+	versions, err := ctrl.Repo.GetCurriculumVersions(majorID)
+	if err != nil {
+		curriculumVersionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetCurriculumVersion returns one published version of the major's curriculum.
+func (ctrl *Controllers) GetCurriculumVersion(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid curriculum version"})
+		return
+	}
+	version, err := ctrl.Repo.GetCurriculumVersion(majorID, number)
+	if err != nil {
+		curriculumVersionError(c, err)
+		return
+	}
+	if version == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Curriculum version not found"})
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// PublishCurriculumVersion publishes the major's current subjects and elective groups as a new
+// curriculum version for new generations, e.g. {"name": "Akreditacija 2025", "generation": 2025}.
+func (ctrl *Controllers) PublishCurriculumVersion(c *gin.Context) {
+	majorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid major ID"})
+		return
+	}
+	var req repositories.PublishCurriculumVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	publishedBy, _ := currentUserID(c)
+	version, err := ctrl.Repo.PublishCurriculumVersion(majorID, req, publishedBy)
+	if err != nil {
+		curriculumVersionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, version)
+}
+
+// CompareCurriculumVersions lists the subjects added, removed and moved between two curriculum
+// versions, e.g. ?from=1&to=2. Omitting to compares against the major's current subjects.
+func (ctrl *Controllers) CompareCurriculumVersions(c *gin.Context) {
+	majorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid major ID"})
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a curriculum version"})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a curriculum version"})
+		return
+	}
+	diff, err := ctrl.Repo.CompareCurriculumVersions(majorID, from, to)
+	if err != nil {
+		curriculumVersionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
@@ -94,22 +94,11 @@ func (ctrl *Controllers) UpdateDepartment(c *gin.Context) {
 
 func (ctrl *Controllers) DeleteDepartment(c *gin.Context) {
 	id := c.Param("id")
-	department, err := ctrl.Repo.GetDepartmentByID(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	for _, majorID := range department.MajorIDs {
-		err = ctrl.RemoveDepartmentFromMajor(majorID, department.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	}
 
-	err = ctrl.Repo.DeleteDepartment(id)
+	// Majors of the department are unlinked by the department cascade policy
+	err := ctrl.Repo.DeleteDepartment(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
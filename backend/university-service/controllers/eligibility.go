@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetEligibilityRules returns which exam registration rules are enabled for a major.
+func (ctrl *Controllers) GetEligibilityRules(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	ruleSet, err := ctrl.Repo.GetEligibilityRules(majorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ruleSet)
+}
+
+// UpdateEligibilityRules toggles exam registration rules for a major, e.g. {"rules": {"tuition_paid": true}}.
+func (ctrl *Controllers) UpdateEligibilityRules(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	var req repositories.UpdateEligibilityRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for rule := range req.Rules {
+		if !rule.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown eligibility rule: " + string(rule), "valid_rules": repositories.AllEligibilityRules})
+			return
+		}
+	}
+
+	ruleSet, err := ctrl.Repo.SetEligibilityRules(majorID, req.Rules)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ruleSet)
+}
+
+// CheckExamEligibility reports, without registering, whether a student may register for an exam session.
+func (ctrl *Controllers) CheckExamEligibility(c *gin.Context) {
+	studentID, ok := ctrl.studentIDParam(c, "studentId")
+	if !ok {
+		return
+	}
+	student, err := ctrl.Repo.GetStudentByIDObject(studentID)
+	if err != nil || student == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+	examSession, err := ctrl.Repo.GetExamSessionByID(c.Param("examSessionId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+
+	failures, err := ctrl.Repo.CheckExamEligibility(student, examSession, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"eligible": len(failures) == 0, "failed_rules": failures})
+}
+
+// existingMajorID parses the :id param and checks that the major exists, writing the error response otherwise.
+func (ctrl *Controllers) existingMajorID(c *gin.Context) (primitive.ObjectID, bool) {
+	majorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid major ID"})
+		return primitive.NilObjectID, false
+	}
+	major, err := ctrl.Repo.GetMajorByID(majorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch major"})
+		return primitive.NilObjectID, false
+	}
+	if major == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Major not found"})
+		return primitive.NilObjectID, false
+	}
+	return majorID, true
+}
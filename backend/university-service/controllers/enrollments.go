@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func enrollmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrEnrollmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrAlreadyEnrolled), errors.Is(err, repositories.ErrSemesterNotCertified),
+		errors.Is(err, repositories.ErrSemesterAlreadyCertified):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidEnrollment), errors.Is(err, repositories.ErrInvalidElectiveChoice):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// bindEnrollmentRequest reads an optional EnrollmentRequest body.
+func bindEnrollmentRequest(c *gin.Context) (repositories.EnrollmentRequest, bool) {
+	var req repositories.EnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+// nextAcademicYear is the academic year after the student's latest enrollment, or after the
+// current one if the student has never been enrolled.
+func (ctrl *Controllers) nextAcademicYear(studentID primitive.ObjectID) (int, error) {
+	latest, err := ctrl.Repo.GetLatestEnrollment(studentID)
+	if err != nil {
+		return 0, err
+	}
+	if latest != nil {
+		return latest.AcademicYear + 1, nil
+	}
+	current, err := ctrl.Repo.CurrentAcademicYear(time.Now())
+	return current + 1, err
+}
+
+// enroll stores the enrollment and refreshes the student's standing when credits were
+// transferred, writing the error response on failure.
+func (ctrl *Controllers) enroll(c *gin.Context, student *repositories.Student, enrollment *repositories.Enrollment) bool {
+	if err := ctrl.Repo.EnrollStudent(student, enrollment); err != nil {
+		enrollmentError(c, err)
+		return false
+	}
+	if enrollment.TransferredESPB > 0 {
+		if err := ctrl.UpdateStudentGPA(student); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+// EnrollStudent records the student's enrollment in their current year of study, e.g. a new
+// student's first year: {"financing": "budget", "academic_year": 2025, "electives": ["..."]}.
+// Use /students/:id/advance to enroll an already enrolled student in the following year.
+func (ctrl *Controllers) EnrollStudent(c *gin.Context) {
+	student, err := ctrl.Repo.GetStudentByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+	req, ok := bindEnrollmentRequest(c)
+	if !ok {
+		return
+	}
+	if req.AcademicYear == 0 {
+		if req.AcademicYear, err = ctrl.Repo.CurrentAcademicYear(time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	yearOfStudy := student.Year
+	if yearOfStudy == 0 {
+		yearOfStudy = 1
+	}
+
+	enrollment := &repositories.Enrollment{
+		AcademicYear:     req.AcademicYear,
+		YearOfStudy:      yearOfStudy,
+		Financing:        req.Financing,
+		ESPBAtEnrollment: student.ESPB,
+		TransferredESPB:  req.TransferredESPB,
+		Electives:        req.Electives,
+		Note:             req.Note,
+	}
+	enrollment.EnrolledBy, _ = currentUserID(c)
+	if !ctrl.enroll(c, student, enrollment) {
+		return
+	}
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// GetStudentEnrollments returns the student's enrollment history, oldest first.
+func (ctrl *Controllers) GetStudentEnrollments(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	enrollments, err := ctrl.Repo.GetEnrollmentsByStudent(studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollments)
+}
+
+// CertifySemester certifies (overava) a semester of an enrollment, e.g. {"semester": 1}.
+// Both semesters of a year must be certified before the student can enroll in the next one.
+func (ctrl *Controllers) CertifySemester(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid enrollment ID"})
+		return
+	}
+	var req repositories.CertifySemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	certifiedBy, _ := currentUserID(c)
+	enrollment, err := ctrl.Repo.CertifySemester(id, req.Semester, certifiedBy)
+	if err != nil {
+		enrollmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
@@ -25,6 +25,7 @@ func (ctrl *Controllers) GetExamGradeByID(c *gin.Context) {
 	c.JSON(http.StatusOK, grade)
 }
 
+// CreateExamGrade grades a registered student, identified by student_id or index_number.
 func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 	var req repositories.CreateExamGradeRequest
 	if err := c.BindJSON(&req); err != nil {
@@ -32,12 +33,20 @@ func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 		return
 	}
 
-	if req.Grade < 5 || req.Grade > 10 {
+	if req.ExamPoints == nil && (req.Grade < 5 || req.Grade > 10) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Grade must be between 5 and 10"})
 		return
 	}
 
-	fetchedStudent, err := ctrl.Repo.GetStudentByID(req.StudentID.Hex())
+	studentID := req.StudentID
+	if studentID.IsZero() {
+		id, ok := ctrl.studentIDByIndexNumber(c, req.IndexNumber)
+		if !ok {
+			return
+		}
+		studentID = id
+	}
+	fetchedStudent, err := ctrl.Repo.GetStudentByIDObject(studentID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
@@ -48,15 +57,12 @@ func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
 		return
 	}
-
-	examSession, err := ctrl.Repo.GetExamSessionByID(examRegistration.ExamSessionID.Hex())
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+	if examRegistration.Status == repositories.RegistrationAbsent {
+		c.JSON(http.StatusConflict, gin.H{"error": "The student was marked absent from this exam"})
 		return
 	}
 
-	examSession.Status = repositories.Completed
-	err = ctrl.Repo.UpdateExamSession(examSession)
+	examSession, err := ctrl.Repo.GetExamSessionByID(examRegistration.ExamSessionID.Hex())
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
 		return
@@ -64,12 +70,24 @@ func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 
 	professor := examSession.Professor
 
+	totalPoints := 0.0
+	if req.ExamPoints != nil {
+		points, err := ctrl.Repo.GradeFromExamPoints(examSession.Subject.ID, fetchedStudent.ID, *req.ExamPoints, professor.ID)
+		if err != nil {
+			gradingSchemeError(c, err)
+			return
+		}
+		req.Grade = points.Grade
+		totalPoints = points.TotalPoints
+	}
+
 	grade := repositories.ExamGrade{
 		Student:            *fetchedStudent,
 		ExamRegistrationId: req.ExamRegistrationId,
 		ExamSessionId:      examSession.ID,
 		SubjectId:          examSession.Subject.ID,
 		Grade:              req.Grade,
+		Points:             totalPoints,
 		Passed:             req.Grade >= 6,
 		GradedBy:           professor,
 		Comments:           req.Comments,
@@ -80,15 +98,12 @@ func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// The session is completed once the last registered student is graded or marked absent
+	if _, err := ctrl.Repo.CompleteExamSessionIfGraded(examSession.ID); err != nil {
+		ctrl.logger.Printf("Failed to complete exam session %s: %v", examSession.ID.Hex(), err)
+	}
+	_, _ = ctrl.CreateNotificationByRecipient(examGradeNotification(examSession.Subject.Name, &grade))
 	if grade.Passed {
-		_, _ = ctrl.CreateNotificationByRecipient(repositories.Notification{
-			RecipientID:    grade.Student.ID,
-			RecipientType:  "id",
-			RecipientValue: grade.Student.ID.Hex(),
-			Title:          "You have passed the " + examSession.Subject.Name + " exam",
-			Content:        "You have passed the " + examSession.Subject.Name + " exam with a grade of " + strconv.Itoa(grade.Grade) + ". \nHere is the comment from professor " + *grade.GradedBy.FirstName + " " + *grade.GradedBy.LastName + ": " + grade.Comments,
-		})
-
 		for i, subject := range fetchedStudent.Subjects {
 			if subject.ID == examSession.Subject.ID {
 				fetchedStudent.Subjects[i].HasPassed = grade.Passed
@@ -114,40 +129,37 @@ func (ctrl *Controllers) CreateExamGrade(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-	} else {
-		_, _ = ctrl.CreateNotificationByRecipient(repositories.Notification{
-			RecipientID:    grade.Student.ID,
-			RecipientType:  "id",
-			RecipientValue: grade.Student.ID.Hex(),
-			Title:          "You have failed the " + examSession.Subject.Name + " exam",
-			Content:        "You have failed the " + examSession.Subject.Name + " exam, better luck next time. \nHere is the comment from professor " + *grade.GradedBy.FirstName + " " + *grade.GradedBy.LastName + ": " + grade.Comments,
-		})
 	}
 
 	c.JSON(http.StatusCreated, grade)
 }
 
-func (ctrl *Controllers) UpdateStudentGPA(student *repositories.Student) error {
-	grades, err := ctrl.Repo.GetExamGradesByStudent(student.ID)
-	if err != nil {
-		return fmt.Errorf("error fetching exam grades for GPA: %s", err.Error())
-	}
-	var sum int
-	passedCount := 0
-	for _, g := range grades {
-		if g.Passed {
-			sum += g.Grade
-			passedCount++
-		}
+// examGradeNotification tells the student the result of an exam along with the professor's comment.
+func examGradeNotification(subjectName string, grade *repositories.ExamGrade) repositories.Notification {
+	notification := repositories.Notification{
+		RecipientID:    grade.Student.ID,
+		RecipientType:  "id",
+		RecipientValue: grade.Student.ID.Hex(),
 	}
-	student.GPA = 0.0
-	if passedCount > 0 {
-		student.GPA = float64(sum) / float64(passedCount)
+	comment := "\nHere is the comment from professor " + *grade.GradedBy.FirstName + " " + *grade.GradedBy.LastName + ": " + grade.Comments
+	if grade.Passed {
+		notification.Title = "You have passed the " + subjectName + " exam"
+		notification.Content = "You have passed the " + subjectName + " exam with a grade of " + strconv.Itoa(grade.Grade) + ". " + comment
+	} else {
+		notification.Title = "You have failed the " + subjectName + " exam"
+		notification.Content = "You have failed the " + subjectName + " exam, better luck next time. " + comment
 	}
-	err = ctrl.Repo.UpdateStudentGPA(student.ID, student.GPA)
+	return notification
+}
+
+// UpdateStudentGPA recomputes the student's ESPB-weighted GPA and earned ESPB and stores them.
+func (ctrl *Controllers) UpdateStudentGPA(student *repositories.Student) error {
+	gpa, espb, err := ctrl.Repo.RecalculateStudentStanding(student.ID)
 	if err != nil {
 		return fmt.Errorf("error updating student GPA: %s", err.Error())
 	}
+	student.GPA = gpa
+	student.ESPB = espb
 	return nil
 }
 
@@ -214,6 +226,15 @@ func (ctrl *Controllers) DeleteExamGrade(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Grade not found"})
 		return
 	}
+	pending, err := ctrl.Repo.HasPendingGradeAppeal(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if pending {
+		c.JSON(http.StatusConflict, gin.H{"error": repositories.ErrGradeAppealPending.Error()})
+		return
+	}
 
 	err = ctrl.Repo.DeleteExamGrade(id)
 	if err != nil {
@@ -244,9 +265,8 @@ func (ctrl *Controllers) DeleteExamGrade(c *gin.Context) {
 }
 
 func (ctrl *Controllers) GetExamGradesByStudent(c *gin.Context) {
-	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+	studentID, ok := ctrl.studentIDParam(c, "studentId")
+	if !ok {
 		return
 	}
 
@@ -276,9 +296,8 @@ func (ctrl *Controllers) GetExamGradesByExamSession(c *gin.Context) {
 }
 
 func (ctrl *Controllers) GetExamGradeByStudentAndExam(c *gin.Context) {
-	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+	studentID, ok := ctrl.studentIDParam(c, "studentId")
+	if !ok {
 		return
 	}
 
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func examPeriodTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrExamPeriodTemplateNotFound), errors.Is(err, repositories.ErrMajorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrExamPeriodOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidExamPeriodTemplate), errors.Is(err, repositories.ErrOutsideAcademicCalendar):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetExamPeriodTemplates returns the exam period templates in academic year order.
+func (ctrl *Controllers) GetExamPeriodTemplates(c *gin.Context) {
+	templates, err := ctrl.Repo.GetExamPeriodTemplates()
+	if err != nil {
+		examPeriodTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// CreateExamPeriodTemplate adds a recurring exam period, e.g.
+// {"name_pattern": "Januarski ispitni rok {year}", "month": 1, "week_offset": 1, "weeks": 3, "semester": 1}.
+func (ctrl *Controllers) CreateExamPeriodTemplate(c *gin.Context) {
+	var req repositories.ExamPeriodTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template, err := ctrl.Repo.CreateExamPeriodTemplate(req)
+	if err != nil {
+		examPeriodTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+func (ctrl *Controllers) UpdateExamPeriodTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam period template ID"})
+		return
+	}
+	var req repositories.ExamPeriodTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template, err := ctrl.Repo.UpdateExamPeriodTemplate(id, req)
+	if err != nil {
+		examPeriodTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func (ctrl *Controllers) DeleteExamPeriodTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam period template ID"})
+		return
+	}
+	if err := ctrl.Repo.DeleteExamPeriodTemplate(id); err != nil {
+		examPeriodTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Exam period template deleted successfully"})
+}
+
+// GenerateExamPeriods creates all exam periods of an academic year from the templates, e.g.
+// {"academic_year": 2025}. The generated periods activate and deactivate on their own.
+func (ctrl *Controllers) GenerateExamPeriods(c *gin.Context) {
+	var req repositories.GenerateExamPeriodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := ctrl.Repo.GenerateExamPeriods(req.AcademicYear, time.Now())
+	if err != nil {
+		examPeriodTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
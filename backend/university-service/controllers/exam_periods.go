@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 	repositories "university-service/repository"
 
 	"github.com/gin-gonic/gin"
@@ -32,25 +34,45 @@ func (ctrl *Controllers) CreateExamPeriod(c *gin.Context) {
 		Semester:     req.Semester,
 		MajorID:      req.MajorID,
 		IsActive:     req.IsActive,
+		AutoActivate: req.AutoActivate,
+		ActivateAt:   req.ActivateAt,
+	}
+	if period.AutoActivate {
+		if period.ActivateAt.IsZero() {
+			period.ActivateAt = period.StartDate
+		}
+		period.IsActive = period.ActiveOn(time.Now())
+	}
+	warnings, err := ctrl.Repo.CheckExamPeriodCalendar(period)
+	if errors.Is(err, repositories.ErrOutsideAcademicCalendar) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 	if err := ctrl.Repo.CreateExamPeriod(period); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	period.CalendarWarnings = warnings
 	c.JSON(http.StatusCreated, period)
 }
 
-// GetAllExamPeriods returns all exam periods.
+// GetAllExamPeriods lists exam periods, filtered by active, academic_year, semester, major or a
+// start_date range (start_date_from, start_date_to).
 func (ctrl *Controllers) GetAllExamPeriods(c *gin.Context) {
-	periods, err := ctrl.Repo.GetAllExamPeriods()
+	q, ok := ctrl.listQuery(c, repositories.ExamPeriodListSpec)
+	if !ok {
+		return
+	}
+	periods, total, err := ctrl.Repo.ListExamPeriods(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if periods == nil {
-		periods = []repositories.ExamPeriod{}
-	}
-	c.JSON(http.StatusOK, periods)
+	respondList(c, q, periods, total)
 }
 
 // GetActiveExamPeriods returns only active exam periods (for scheduling).
@@ -86,7 +108,8 @@ func (ctrl *Controllers) GetExamPeriodByID(c *gin.Context) {
 	c.JSON(http.StatusOK, period)
 }
 
-// UpdateExamPeriod updates an exam period (e.g. toggle is_active, adjust dates).
+// UpdateExamPeriod updates an exam period (e.g. toggle is_active, adjust dates). Toggling is_active
+// of an auto-activated period takes it over from the status updater.
 func (ctrl *Controllers) UpdateExamPeriod(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := primitive.ObjectIDFromHex(idStr)
@@ -129,6 +152,20 @@ func (ctrl *Controllers) UpdateExamPeriod(c *gin.Context) {
 	if req.MajorID == nil && existing.MajorID != nil {
 		req.MajorID = existing.MajorID
 	}
+	// A period keeps activating on its own until is_active is toggled by hand
+	req.TemplateID = existing.TemplateID
+	if req.ActivateAt.IsZero() {
+		req.ActivateAt = existing.ActivateAt
+	}
+	if existing.AutoActivate {
+		req.AutoActivate = req.IsActive == existing.IsActive
+	}
+	if req.AutoActivate {
+		if req.ActivateAt.IsZero() {
+			req.ActivateAt = req.StartDate
+		}
+		req.IsActive = req.ActiveOn(time.Now())
+	}
 	if req.EndDate.Before(req.StartDate) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be on or after start_date"})
 		return
@@ -137,11 +174,21 @@ func (ctrl *Controllers) UpdateExamPeriod(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "semester must be 1 or 2"})
 		return
 	}
+	warnings, err := ctrl.Repo.CheckExamPeriodCalendar(&req)
+	if errors.Is(err, repositories.ErrOutsideAcademicCalendar) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err := ctrl.Repo.UpdateExamPeriod(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	req.CalendarWarnings = warnings
 	c.JSON(http.StatusOK, req)
 }
 
@@ -1,19 +1,29 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 	repositories "university-service/repository"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// RegisterForExam registers a student, identified by student_id or index_number, for an exam session.
 func (ctrl *Controllers) RegisterForExam(c *gin.Context) {
 	var req repositories.CreateExamRegistrationRequest
 	if err := c.BindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.StudentID.IsZero() {
+		id, ok := ctrl.studentIDByIndexNumber(c, req.IndexNumber)
+		if !ok {
+			return
+		}
+		req.StudentID = id
+	}
 
 	student, err := ctrl.Repo.GetStudentByID(req.StudentID.Hex())
 	if err != nil {
@@ -39,12 +49,60 @@ func (ctrl *Controllers) RegisterForExam(c *gin.Context) {
 		return
 	}
 
+	failures, err := ctrl.Repo.CheckExamEligibility(student, examSession, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(failures) > 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Student is not eligible to register for this exam", "failed_rules": failures})
+		return
+	}
+
+	overlapping, err := ctrl.Repo.FindOverlappingExam(student.ID, examSession)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if overlapping != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":               repositories.ErrExamTimeOverlap.Error(),
+			"conflicting_session": overlapping,
+		})
+		return
+	}
+
 	registration := repositories.ExamRegistration{
 		Student:       *student,
 		ExamSessionID: examSession.ID,
 	}
 
 	err = ctrl.Repo.RegisterForExam(&registration)
+	if errors.Is(err, repositories.ErrExamSessionClosed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrAlreadyRegistered) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrExamSessionFull) {
+		// No seats left: queue the student; they are registered automatically when a seat frees up
+		entry, err := ctrl.Repo.AddToExamWaitlist(*student, examSession.ID)
+		if errors.Is(err, repositories.ErrAlreadyOnWaitlist) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":  "Exam session is full, you have been added to the waitlist",
+			"waitlist": entry,
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -54,9 +112,8 @@ func (ctrl *Controllers) RegisterForExam(c *gin.Context) {
 }
 
 func (ctrl *Controllers) DeregisterFromExam(c *gin.Context) {
-	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+	studentID, ok := ctrl.studentIDParam(c, "studentId")
+	if !ok {
 		return
 	}
 
@@ -66,19 +123,25 @@ func (ctrl *Controllers) DeregisterFromExam(c *gin.Context) {
 		return
 	}
 
-	err = ctrl.Repo.DeregisterFromExam(studentID, examSessionID)
+	promoted, err := ctrl.Repo.DeregisterFromExam(studentID, examSessionID)
+	if errors.Is(err, repositories.ErrExamSessionClosed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if promoted != nil {
+		ctrl.notifyPromotedFromWaitlist(promoted)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully deregistered from exam"})
 }
 
 func (ctrl *Controllers) GetExamRegistrationsByStudent(c *gin.Context) {
-	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+	studentID, ok := ctrl.studentIDParam(c, "studentId")
+	if !ok {
 		return
 	}
 
@@ -106,3 +169,69 @@ func (ctrl *Controllers) GetExamRegistrationsByExamSession(c *gin.Context) {
 
 	c.JSON(http.StatusOK, registrations)
 }
+
+// GetExamWaitlist returns the students waiting for a seat in an exam session, in promotion order.
+func (ctrl *Controllers) GetExamWaitlist(c *gin.Context) {
+	examSessionID, err := primitive.ObjectIDFromHex(c.Param("examSessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam session ID"})
+		return
+	}
+
+	waitlist, err := ctrl.Repo.GetExamWaitlist(examSessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if waitlist == nil {
+		waitlist = []repositories.ExamWaitlistEntry{}
+	}
+
+	c.JSON(http.StatusOK, waitlist)
+}
+
+// notifyPromotedFromWaitlist tells a student they got a seat after waiting for it.
+func (ctrl *Controllers) notifyPromotedFromWaitlist(registration *repositories.ExamRegistration) {
+	subjectName := "an exam"
+	if examSession, err := ctrl.Repo.GetExamSessionByID(registration.ExamSessionID.Hex()); err == nil {
+		subjectName = "the " + examSession.Subject.Name + " exam"
+	}
+	_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+		RecipientID:    registration.Student.ID,
+		RecipientType:  "id",
+		RecipientValue: registration.Student.ID.Hex(),
+		Title:          "A seat opened up for " + subjectName,
+		Content:        "You have been moved from the waitlist and are now registered for " + subjectName + ".",
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to notify student %s about waitlist promotion: %v", registration.Student.ID.Hex(), err)
+	}
+}
+
+// MarkExamRegistrationAbsent records that the student did not attend the exam, so the session can
+// be completed without a grade for them.
+func (ctrl *Controllers) MarkExamRegistrationAbsent(c *gin.Context) {
+	registrationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid registration ID"})
+		return
+	}
+
+	registration, err := ctrl.Repo.MarkRegistrationAbsent(registrationID)
+	switch {
+	case errors.Is(err, repositories.ErrExamRegistrationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repositories.ErrExamNotHeld), errors.Is(err, repositories.ErrRegistrationGraded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := ctrl.Repo.CompleteExamSessionIfGraded(registration.ExamSessionID); err != nil {
+		ctrl.logger.Printf("Failed to complete exam session %s: %v", registration.ExamSessionID.Hex(), err)
+	}
+	c.JSON(http.StatusOK, registration)
+}
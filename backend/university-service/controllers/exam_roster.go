@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	helper "university-service/helpers"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetExamRoster returns the students registered for an exam with their attendance, grades and
+// statistics, as JSON or as a printable PDF with ?format=pdf.
+func (ctrl *Controllers) GetExamRoster(c *gin.Context) {
+	examSessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam session ID"})
+		return
+	}
+	roster, err := ctrl.Repo.GetExamRoster(examSessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if roster == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+
+	if c.Query("format") != "pdf" {
+		c.JSON(http.StatusOK, roster)
+		return
+	}
+
+	pdf, err := helper.RenderExamRosterPDF(roster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate roster PDF"})
+		return
+	}
+	filename := fmt.Sprintf("roster-%s-%s.pdf", roster.ExamDate.Format("2006-01-02"), examSessionID.Hex())
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// CheckInStudent marks a student present at the exam, e.g. {"index_number": "SW-12/2025"}.
+func (ctrl *Controllers) CheckInStudent(c *gin.Context) {
+	examSession, err := ctrl.Repo.GetExamSessionByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+	var req repositories.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := ctrl.Repo.CheckInStudent(examSession, req)
+	switch {
+	case errors.Is(err, repositories.ErrExamRegistrationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repositories.ErrCheckInClosed), errors.Is(err, repositories.ErrRegistrationGraded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// MarkExamAbsences marks students of a held exam absent in bulk, either listed by index number
+// or student ID, or everyone not checked in with {"all_not_checked_in": true}. Absent students
+// are excluded from grading.
+func (ctrl *Controllers) MarkExamAbsences(c *gin.Context) {
+	examSession, err := ctrl.Repo.GetExamSessionByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+	var req repositories.MarkAbsencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IndexNumbers) == 0 && len(req.StudentIDs) == 0 && !req.AllNotCheckedIn {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "index_numbers, student_ids or all_not_checked_in is required"})
+		return
+	}
+
+	result, err := ctrl.Repo.MarkAbsences(examSession, req)
+	switch {
+	case errors.Is(err, repositories.ErrExamNotHeld):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(result.Marked) > 0 {
+		if _, err := ctrl.Repo.CompleteExamSessionIfGraded(examSession.ID); err != nil {
+			ctrl.logger.Printf("Failed to complete exam session %s: %v", examSession.ID.Hex(), err)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
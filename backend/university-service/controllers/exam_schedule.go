@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetExamSchedulingPolicy returns the minimum gap between exams of the same year for a major.
+func (ctrl *Controllers) GetExamSchedulingPolicy(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := ctrl.Repo.GetExamSchedulingPolicy(majorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateExamSchedulingPolicy changes a major's exam gap and whether conflicts warn or block,
+// e.g. {"min_gap_hours": 48, "mode": "block"}.
+func (ctrl *Controllers) UpdateExamSchedulingPolicy(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	var req repositories.UpdateExamSchedulingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MinGapHours != nil && *req.MinGapHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_gap_hours cannot be negative"})
+		return
+	}
+	if req.Mode != nil && !req.Mode.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be warn or block"})
+		return
+	}
+
+	policy, err := ctrl.Repo.SetExamSchedulingPolicy(majorID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// checkExamSchedule applies the major's scheduling policy to a new or moved exam. Conflicts are
+// attached to the session as warnings, or rejected with 409 when the policy blocks them; in that
+// case the response has been written and false is returned.
+func (ctrl *Controllers) checkExamSchedule(c *gin.Context, examSession *repositories.ExamSession) bool {
+	conflicts, policy, err := ctrl.Repo.CheckExamSchedule(examSession)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if len(conflicts) > 0 && policy.Mode == repositories.ExamConflictBlock {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         repositories.ErrExamScheduleConflict.Error(),
+			"min_gap_hours": policy.MinGapHours,
+			"conflicts":     conflicts,
+		})
+		return false
+	}
+	examSession.ScheduleConflicts = conflicts
+	return true
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -46,17 +47,37 @@ func (ctrl *Controllers) CreateExamSession(c *gin.Context) {
 		return
 	}
 
+	if req.Location == "" && req.RoomID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "location or room_id is required"})
+		return
+	}
+
 	examSession := repositories.ExamSession{
-		Subject:      *subject,
-		Professor:    *professor,
-		ExamDate:     req.ExamDate,
-		ExamPeriodID: &period.ID,
-		Location:     req.Location,
-		MaxStudents:  req.MaxStudents,
+		ID:              primitive.NewObjectID(),
+		Subject:         *subject,
+		Professor:       *professor,
+		ExamDate:        req.ExamDate,
+		ExamPeriodID:    &period.ID,
+		Location:        req.Location,
+		RoomID:          req.RoomID,
+		Duration:        req.Duration,
+		MaxStudents:     req.MaxStudents,
+		RegistrationFee: req.RegistrationFee,
+	}
+
+	if !ctrl.checkExamSchedule(c, &examSession) {
+		return
+	}
+
+	// Reserve the room first so overlapping bookings and undersized rooms are rejected up front
+	if _, err := ctrl.Repo.ReserveRoomForExamSession(&examSession); err != nil {
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
 	err = ctrl.Repo.CreateExamSession(&examSession)
 	if err != nil {
+		_ = ctrl.Repo.ReleaseRoomForExamSession(examSession.ID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -157,12 +178,68 @@ func (ctrl *Controllers) UpdateExamSession(c *gin.Context) {
 	examSession.Subject = oldExamSession.Subject
 	examSession.Professor = oldExamSession.Professor
 	examSession.CreatedAt = oldExamSession.CreatedAt
+	if examSession.RoomID == nil {
+		examSession.RoomID = oldExamSession.RoomID
+	}
+	if examSession.Duration == 0 {
+		examSession.Duration = oldExamSession.Duration
+	}
+	if !examSession.ExamDate.Equal(oldExamSession.ExamDate) || examSession.Duration != oldExamSession.Duration {
+		if !ctrl.checkExamSchedule(c, &examSession) {
+			return
+		}
+	}
+
+	// Re-reserve the room for the (possibly) new time window and capacity; the old booking is kept
+	// until the session is saved
+	var booking *repositories.RoomBooking
+	if examSession.RoomID != nil {
+		reservation := examSession
+		if reservation.MaxStudents == 0 {
+			reservation.MaxStudents = oldExamSession.MaxStudents
+		}
+		booking, err = ctrl.Repo.ReserveRoomForExamSession(&reservation)
+		if err != nil {
+			c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		examSession.Location = reservation.Location
+	}
 
 	err = ctrl.Repo.UpdateExamSession(&examSession)
 	if err != nil {
+		if booking != nil {
+			if err := ctrl.Repo.CancelRoomBooking(booking.ID); err != nil {
+				ctrl.logger.Printf("Failed to roll back room booking %s: %v", booking.ID.Hex(), err)
+			}
+		}
+		if errors.Is(err, repositories.ErrCapacityBelowSeats) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if booking != nil {
+		if err := ctrl.Repo.KeepOnlyRoomBooking(examSession.ID, booking.ID); err != nil {
+			ctrl.logger.Printf("Failed to release the previous room booking of exam session %s: %v", examSession.ID.Hex(), err)
+		}
+	}
+	// A larger capacity frees seats for students on the waitlist
+	if examSession.MaxStudents > oldExamSession.MaxStudents {
+		for {
+			promoted, err := ctrl.Repo.PromoteFromWaitlist(examSession.ID)
+			if err != nil {
+				ctrl.logger.Printf("Failed to promote from waitlist of exam session %s: %v", examSession.ID.Hex(), err)
+				break
+			}
+			if promoted == nil {
+				break
+			}
+			ctrl.notifyPromotedFromWaitlist(promoted)
+		}
+	}
+
 	registrations, err := ctrl.Repo.GetExamRegistrationsByExamSession(examSession.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -188,21 +265,47 @@ func (ctrl *Controllers) UpdateExamSession(c *gin.Context) {
 func (ctrl *Controllers) DeleteExamSession(c *gin.Context) {
 	id := c.Param("id")
 
+	// Registrations and the room booking are removed by the exam session cascade policy
 	err := ctrl.Repo.DeleteExamSession(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// GetAllExamSessions lists exam sessions, filtered by status, subject, major, department,
+// professor, exam_period, room or a date range (date_from, date_to), sorted by date by default.
 func (ctrl *Controllers) GetAllExamSessions(c *gin.Context) {
-	examSessions, err := ctrl.Repo.GetAllExamSessions()
+	q, ok := ctrl.listQuery(c, repositories.ExamSessionListSpec)
+	if !ok {
+		return
+	}
+	examSessions, total, err := ctrl.Repo.ListExamSessions(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, examSessions)
+	respondList(c, q, examSessions, total)
+}
+
+// GetExamGradingProgress shows how many registered students still need a grade and the grading deadline.
+func (ctrl *Controllers) GetExamGradingProgress(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	progress, err := ctrl.Repo.GetExamGradingProgress(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if progress == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam session not found"})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
 }
@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func gradeAppealError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrGradeAppealNotFound), errors.Is(err, repositories.ErrExamGradeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrGradeAppealExists), errors.Is(err, repositories.ErrAppealWindowClosed),
+		errors.Is(err, repositories.ErrInvalidAppealTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidAppealDecision):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// currentUserID returns the ID of the authenticated user from the token claims.
+func currentUserID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.GetString("uid"))
+	return id, err == nil
+}
+
+func (ctrl *Controllers) notifyAppealStudent(appeal *repositories.GradeAppeal, content string) {
+	_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+		RecipientID:    appeal.StudentID,
+		RecipientType:  "id",
+		RecipientValue: appeal.StudentID.Hex(),
+		Title:          "Grade appeal: " + appeal.SubjectName,
+		Content:        content,
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to notify student %s about grade appeal %s: %v", appeal.StudentID.Hex(), appeal.ID.Hex(), err)
+	}
+}
+
+// notifyAppealReviewer tells whoever has to decide the appeal now that it is waiting for them.
+func (ctrl *Controllers) notifyAppealReviewer(appeal *repositories.GradeAppeal) {
+	notification := repositories.Notification{
+		Title:   "Grade appeal to review: " + appeal.SubjectName,
+		Content: fmt.Sprintf("A student has appealed grade %d in %s. Reason: %s", appeal.OriginalGrade, appeal.SubjectName, appeal.Reason),
+	}
+	if reviewer, ok := appeal.Reviewer(); ok {
+		notification.RecipientID = reviewer
+		notification.RecipientType = "id"
+		notification.RecipientValue = reviewer.Hex()
+	} else {
+		notification.RecipientType = "role"
+		notification.RecipientValue = "STUDENTSKA_SLUZBA"
+	}
+	if appeal.ReviewDeadline != nil {
+		notification.Content += fmt.Sprintf("\nPlease decide by %s.", appeal.ReviewDeadline.Format("02.01.2006. 15:04"))
+	}
+	if _, err := ctrl.CreateNotificationByRecipient(notification); err != nil {
+		ctrl.logger.Printf("Failed to notify the reviewer of grade appeal %s: %v", appeal.ID.Hex(), err)
+	}
+}
+
+// FileGradeAppeal lets a student appeal their grade, e.g. {"reason": "..."}. The appeal goes to
+// the professor who gave the grade.
+func (ctrl *Controllers) FileGradeAppeal(c *gin.Context) {
+	gradeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grade ID"})
+		return
+	}
+	var req repositories.FileGradeAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grade, err := ctrl.Repo.GetExamGradeByID(gradeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grade not found"})
+		return
+	}
+	if userID, ok := currentUserID(c); ok && c.GetString("user_type") == "STUDENT" && userID != grade.Student.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Students can only appeal their own grades"})
+		return
+	}
+
+	appeal, err := ctrl.Repo.FileGradeAppeal(gradeID, req.Reason)
+	if err != nil {
+		gradeAppealError(c, err)
+		return
+	}
+	ctrl.notifyAppealStudent(appeal, fmt.Sprintf("Your appeal of grade %d has been received and sent to the professor for review", appeal.OriginalGrade))
+	ctrl.notifyAppealReviewer(appeal)
+	c.JSON(http.StatusCreated, appeal)
+}
+
+func (ctrl *Controllers) GetGradeAppealByID(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	appeal, err := ctrl.Repo.GetGradeAppealByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if appeal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrGradeAppealNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeal)
+}
+
+func (ctrl *Controllers) GetGradeAppealsByStudent(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	appeals, err := ctrl.Repo.GetGradeAppealsByStudent(studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeals)
+}
+
+// GetGradeAppealsForReviewer lists the appeals waiting for a professor, as grader or department head.
+func (ctrl *Controllers) GetGradeAppealsForReviewer(c *gin.Context) {
+	professorID, err := primitive.ObjectIDFromHex(c.Param("professorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid professor ID"})
+		return
+	}
+	appeals, err := ctrl.Repo.GetGradeAppealsForReviewer(professorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeals)
+}
+
+// DecideGradeAppeal records the reviewer's decision: {"decision": "uphold"}, {"decision": "change",
+// "grade": 8} or, for the grading professor, {"decision": "escalate"} to pass it on to the
+// department head. Only the current reviewer may decide; studentska služba decides appeals
+// escalated in a department without a head.
+func (ctrl *Controllers) DecideGradeAppeal(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req repositories.DecideGradeAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appeal, err := ctrl.Repo.GetGradeAppealByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if appeal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrGradeAppealNotFound.Error()})
+		return
+	}
+	actorID, _ := currentUserID(c)
+	if reviewer, ok := appeal.Reviewer(); ok {
+		if actorID != reviewer {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the current reviewer can decide this appeal"})
+			return
+		}
+	} else if !appeal.Stage.IsFinal() && c.GetString("user_type") != "STUDENTSKA_SLUZBA" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The department has no head; studentska služba decides this appeal"})
+		return
+	}
+
+	if req.Decision == repositories.AppealDecisionEscalate {
+		appeal, err = ctrl.Repo.EscalateGradeAppeal(appeal, &actorID, req.Note)
+		if err != nil {
+			gradeAppealError(c, err)
+			return
+		}
+		ctrl.notifyAppealStudent(appeal, "The professor has forwarded your appeal to the head of the department")
+		ctrl.notifyAppealReviewer(appeal)
+		c.JSON(http.StatusOK, appeal)
+		return
+	}
+
+	appeal, err = ctrl.Repo.ResolveGradeAppeal(appeal, actorID, req.Decision, req.Grade, req.Note)
+	if err != nil {
+		gradeAppealError(c, err)
+		return
+	}
+	if appeal.Stage == repositories.AppealChanged {
+		student := &repositories.Student{ID: appeal.StudentID}
+		if err := ctrl.UpdateStudentGPA(student); err != nil {
+			ctrl.logger.Printf("Failed to update GPA of student %s: %v", appeal.StudentID.Hex(), err)
+		}
+		ctrl.notifyAppealStudent(appeal, strings.TrimSpace(fmt.Sprintf("Your appeal was accepted. Your grade has been changed from %d to %d. %s", appeal.OriginalGrade, appeal.FinalGrade, req.Note)))
+	} else {
+		ctrl.notifyAppealStudent(appeal, strings.TrimSpace(fmt.Sprintf("Your appeal was rejected and grade %d stands. %s", appeal.OriginalGrade, req.Note)))
+	}
+	c.JSON(http.StatusOK, appeal)
+}
+
+// GetGradeAppealPolicy returns how long students have to appeal a grade and reviewers to decide.
+func (ctrl *Controllers) GetGradeAppealPolicy(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := ctrl.Repo.GetGradeAppealPolicy(majorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateGradeAppealPolicy changes a major's appeal deadlines, e.g. {"window_hours": 48, "review_hours": 24}.
+func (ctrl *Controllers) UpdateGradeAppealPolicy(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+
+	var req repositories.UpdateGradeAppealPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.WindowHours != nil && *req.WindowHours <= 0) || (req.ReviewHours != nil && *req.ReviewHours <= 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window_hours and review_hours must be positive"})
+		return
+	}
+
+	policy, err := ctrl.Repo.SetGradeAppealPolicy(majorID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func gradingSchemeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrGradingSchemeNotFound), errors.Is(err, repositories.ErrUnknownComponent):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidGradingScheme), errors.Is(err, repositories.ErrPointsOutOfRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// existingSubjectID parses the :id param and checks that the subject exists, writing the error response otherwise.
+func (ctrl *Controllers) existingSubjectID(c *gin.Context) (primitive.ObjectID, bool) {
+	subjectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subject ID"})
+		return primitive.NilObjectID, false
+	}
+	if _, err := ctrl.Repo.GetSubjectByID(subjectID.Hex()); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subject not found"})
+		return primitive.NilObjectID, false
+	}
+	return subjectID, true
+}
+
+// GetGradingScheme returns the components a subject's grade is made up of.
+func (ctrl *Controllers) GetGradingScheme(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	scheme, err := ctrl.Repo.GetGradingScheme(subjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if scheme == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrGradingSchemeNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, scheme)
+}
+
+// SetGradingScheme defines the subject's components, e.g.
+// {"components": [{"name": "Colloquium 1", "type": "colloquium", "max_points": 20}, ...,
+// {"name": "Exam", "type": "final_exam", "max_points": 40, "min_points": 20}], "min_pre_exam_points": 30}.
+// The maxima must add up to 100. Pass the IDs of existing components to keep their points.
+func (ctrl *Controllers) SetGradingScheme(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	var req repositories.SetGradingSchemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scheme, err := ctrl.Repo.SetGradingScheme(subjectID, req)
+	if err != nil {
+		gradingSchemeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scheme)
+}
+
+// RecordComponentPoints enters a student's points in one component of the subject, e.g.
+// {"student_id": "...", "component_id": "...", "points": 17.5}.
+func (ctrl *Controllers) RecordComponentPoints(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	var req repositories.RecordPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := ctrl.Repo.GetStudentByID(req.StudentID.Hex()); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+
+	enteredBy, _ := currentUserID(c)
+	if _, err := ctrl.Repo.RecordComponentPoints(subjectID, req.StudentID, req.ComponentID, req.Points, enteredBy); err != nil {
+		gradingSchemeError(c, err)
+		return
+	}
+	points, err := ctrl.Repo.GetStudentPoints(subjectID, req.StudentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, points)
+}
+
+// GetStudentPoints returns a student's points in the subject and whether the pre-exam
+// obligations are met.
+func (ctrl *Controllers) GetStudentPoints(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	points, err := ctrl.Repo.GetStudentPoints(subjectID, studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if points == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrGradingSchemeNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, points)
+}
+
+// GetSubjectPoints returns the points of every student in the subject.
+func (ctrl *Controllers) GetSubjectPoints(c *gin.Context) {
+	subjectID, ok := ctrl.existingSubjectID(c)
+	if !ok {
+		return
+	}
+	points, err := ctrl.Repo.GetSubjectPoints(subjectID)
+	if err != nil {
+		gradingSchemeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, points)
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	helper "university-service/helpers"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// graduationRequestError writes the response for an error returned by a graduation workflow step.
+func graduationRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrGraduationRequestNotFound), errors.Is(err, repositories.ErrStudentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidGraduationTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidThesis):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (ctrl *Controllers) notifyGraduationStudent(request *repositories.GraduationRequest, content string) {
+	_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+		RecipientID:    request.StudentID,
+		RecipientType:  "id",
+		RecipientValue: request.StudentID.Hex(),
+		Title:          "Graduation request",
+		Content:        content,
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to notify student %s about graduation request %s: %v", request.StudentID.Hex(), request.ID.Hex(), err)
+	}
+}
+
+func (ctrl *Controllers) notifyThesisMembers(request *repositories.GraduationRequest) {
+	if request.Thesis == nil {
+		return
+	}
+	members := append([]repositories.ThesisMember{request.Thesis.Mentor}, request.Thesis.Committee...)
+	for i, member := range members {
+		role := "a committee member"
+		if i == 0 {
+			role = "the mentor"
+		}
+		_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+			RecipientID:    member.ID,
+			RecipientType:  "id",
+			RecipientValue: member.ID.Hex(),
+			Title:          "Thesis assignment",
+			Content:        fmt.Sprintf("You have been assigned as %s for the thesis \"%s\"", role, request.Thesis.Topic),
+		})
+		if err != nil {
+			ctrl.logger.Printf("Failed to notify professor %s about thesis assignment: %v", member.ID.Hex(), err)
+		}
+	}
+}
+
+// reviewNotification is the text sent to the student after studentska služba reviews their request.
+func reviewNotification(request *repositories.GraduationRequest) string {
+	if request.CurrentStage() == repositories.GraduationApproved {
+		return "Your graduation request has been approved. A thesis topic and mentor will be assigned next"
+	}
+	content := "Your graduation request has been rejected"
+	if request.Comments != "" {
+		content += " with the following comments: " + request.Comments
+	}
+	return content
+}
+
+func (ctrl *Controllers) GetGraduationRequestByID(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	request, err := ctrl.Repo.GetGraduationRequestByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if request == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrGraduationRequestNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// ReviewGraduationRequest approves or rejects a pending graduation request.
+func (ctrl *Controllers) ReviewGraduationRequest(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req repositories.ReviewGraduationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := ctrl.Repo.ReviewGraduationRequest(id, req.Approve, req.Comments)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
+	}
+	ctrl.notifyGraduationStudent(request, reviewNotification(request))
+	c.JSON(http.StatusOK, request)
+}
+
+// AssignThesis sets the thesis topic, mentor and committee of an approved request.
+func (ctrl *Controllers) AssignThesis(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req repositories.AssignThesisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := ctrl.Repo.AssignThesis(id, req)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
+	}
+	ctrl.notifyGraduationStudent(request, fmt.Sprintf("Your thesis topic \"%s\" has been assigned. Mentor: %s",
+		request.Thesis.Topic, request.Thesis.Mentor.Name))
+	ctrl.notifyThesisMembers(request)
+	c.JSON(http.StatusOK, request)
+}
+
+// ScheduleDefense sets or moves the thesis defense date.
+func (ctrl *Controllers) ScheduleDefense(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req repositories.ScheduleDefenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := ctrl.Repo.ScheduleDefense(id, req.DefenseDate)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
+	}
+	ctrl.notifyGraduationStudent(request, "Your thesis defense is scheduled for "+req.DefenseDate.Format("02.01.2006. 15:04"))
+	c.JSON(http.StatusOK, request)
+}
+
+// RecordDefense stores the committee's grade for the defense.
+func (ctrl *Controllers) RecordDefense(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req repositories.RecordDefenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := ctrl.Repo.RecordDefense(id, req.Grade, req.Comments)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
+	}
+	if request.CurrentStage() == repositories.GraduationDefended {
+		ctrl.notifyGraduationStudent(request, fmt.Sprintf("You have defended your thesis with grade %d. Your diploma will be issued shortly", req.Grade))
+	} else {
+		ctrl.notifyGraduationStudent(request, "Your thesis defense was not successful. A new defense date will be scheduled")
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// IssueDiploma completes the workflow and records the degree on the student's candidate profile
+// in employment-service. A failure to reach employment-service is logged and does not undo the diploma.
+func (ctrl *Controllers) IssueDiploma(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	diploma, request, err := ctrl.Repo.IssueDiploma(id)
+	if err != nil {
+		graduationRequestError(c, err)
+		return
+	}
+	ctrl.notifyGraduationStudent(request, fmt.Sprintf("Congratulations! Your diploma number %s has been issued", diploma.Number))
+
+	err = helper.UpdateCandidateDegree(diploma.StudentID.Hex(), helper.CandidateDegree{
+		DiplomaNumber: diploma.Number,
+		Major:         diploma.MajorName,
+		GraduatedAt:   diploma.IssuedAt,
+		GPA:           diploma.GPA,
+		ESPB:          diploma.ESPB,
+		ThesisTopic:   diploma.ThesisTopic,
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to update candidate degree for student %s: %v", diploma.StudentID.Hex(), err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"diploma": diploma, "graduation_request": request})
+}
+
+func (ctrl *Controllers) GetStudentDiploma(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	diploma, err := ctrl.Repo.GetDiplomaByStudentID(studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if diploma == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No diploma has been issued for this student"})
+		return
+	}
+	c.JSON(http.StatusOK, diploma)
+}
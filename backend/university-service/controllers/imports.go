@@ -0,0 +1,379 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	helper "university-service/helpers"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// importProfileWait is how long an import waits for auth-service to create the university profile
+// of a user it registered before creating the profile itself.
+const importProfileWait = 15 * time.Second
+
+// importColumns are the columns an import file must have; date_of_birth, password and, for
+// students, major are read when present.
+var importColumns = []string{"first_name", "last_name", "email", "jmbg", "phone", "address"}
+
+// readImportRecords returns the records of a CSV or XLSX file and the line each one is on.
+func readImportRecords(content []byte) ([][]string, []int, error) {
+	if helper.IsXLSX(content) {
+		records, err := helper.ReadXLSX(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return nil, nil, err
+		}
+		lines := make([]int, len(records))
+		for i := range records {
+			lines[i] = i + 1
+		}
+		return records, lines, nil
+	}
+
+	reader := spreadsheetCSVReader(content)
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return records, lines, nil
+}
+
+// parseImportFile reads the people of an import file with a header line. Empty rows are ignored.
+func parseImportFile(content []byte) ([]repositories.ImportRow, error) {
+	records, lines, err := readImportRecords(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("the header must contain the columns %s", strings.Join(importColumns, ", "))
+		}
+	}
+
+	rows := []repositories.ImportRow{}
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		rows = append(rows, repositories.ImportRow{
+			Row:         lines[i+1],
+			FirstName:   field("first_name"),
+			LastName:    field("last_name"),
+			Email:       field("email"),
+			JMBG:        field("jmbg"),
+			Phone:       field("phone"),
+			Address:     field("address"),
+			DateOfBirth: field("date_of_birth"),
+			Major:       field("major"),
+			Password:    field("password"),
+		})
+	}
+	return rows, nil
+}
+
+// generatePassword returns a random initial password for an imported user.
+func generatePassword() (string, error) {
+	const alphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	password := make([]byte, 12)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// StartImport imports students, professors or assistants from a CSV or XLSX file (multipart field
+// "file"), e.g. POST /imports?type=students. The header names the columns first_name, last_name,
+// email, jmbg, phone, address and optionally date_of_birth and password; students also need major
+// (its ID or name). Rows are validated straight away and the job continues in the background:
+// poll GET /imports/:id for progress and download the report from GET /imports/:id/report.
+// A job interrupted by a restart is marked failed; importing the same file again completes the
+// profiles of users auth-service already registered and skips users that are fully set up.
+func (ctrl *Controllers) StartImport(c *gin.Context) {
+	kind := repositories.ImportKind(c.Query("type"))
+	if kind == "" {
+		kind = repositories.ImportKind(c.PostForm("type"))
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a CSV or XLSX file is required in the \"file\" field"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows, err := parseImportFile(content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startedBy, _ := currentUserID(c)
+	job, err := ctrl.Repo.CreateImportJob(kind, file.Filename, rows, startedBy)
+	if errors.Is(err, repositories.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	go ctrl.runImport(job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// runImport registers the job's pending rows one by one, recording each outcome as it goes.
+func (ctrl *Controllers) runImport(job *repositories.ImportJob) {
+	var jobErr error
+	for i := range job.Rows {
+		row := job.Rows[i]
+		if row.Status != repositories.ImportRowPending {
+			continue
+		}
+		ctrl.importRow(job.Kind, &row)
+		if jobErr = ctrl.Repo.RecordImportRow(job.ID, i, row); jobErr != nil {
+			break
+		}
+	}
+	if err := ctrl.Repo.FinishImportJob(job.ID, jobErr); err != nil {
+		ctrl.logger.Printf("Failed to finish import job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// importRow registers the person in auth-service and completes their university profile.
+func (ctrl *Controllers) importRow(kind repositories.ImportKind, row *repositories.ImportRow) {
+	fail := func(format string, args ...interface{}) {
+		row.Status = repositories.ImportRowFailed
+		row.Error = fmt.Sprintf(format, args...)
+	}
+	password := row.Password
+	sealed := ""
+	if password == "" {
+		generated, err := generatePassword()
+		if err == nil {
+			sealed, err = helper.SealSecret(generated)
+		}
+		if err != nil {
+			fail("generating a password: %v", err)
+			return
+		}
+		password = generated
+	}
+
+	userType, _ := kind.UserType()
+	userID, err := helper.RegisterAuthUser(helper.AuthUser{
+		FirstName:   row.FirstName,
+		LastName:    row.LastName,
+		Email:       row.Email,
+		Password:    password,
+		Phone:       row.Phone,
+		Address:     row.Address,
+		JMBG:        row.JMBG,
+		DateOfBirth: row.BirthDate(),
+		UserType:    string(userType),
+	})
+	if errors.Is(err, helper.ErrAuthUserExists) {
+		ctrl.resumeImportedUser(kind, row)
+		return
+	}
+	if err != nil {
+		fail("registration in auth-service failed: %v", err)
+		return
+	}
+	row.UserID = userID
+	row.SealedPassword = sealed
+
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		fail("auth-service returned an invalid user ID %q", userID)
+		return
+	}
+	if err := ctrl.completeImportedProfile(kind, id, row); err != nil {
+		fail("the user was registered, but their profile could not be completed: %v", err)
+		return
+	}
+	row.Status = repositories.ImportRowCreated
+}
+
+// resumeImportedUser handles a row whose user auth-service already registered, e.g. by a job
+// interrupted before it recorded the row. A missing profile or major registration is completed;
+// a user who already has them is skipped.
+func (ctrl *Controllers) resumeImportedUser(kind repositories.ImportKind, row *repositories.ImportRow) {
+	row.Status = repositories.ImportRowFailed
+	id, err := ctrl.Repo.FindImportedProfileID(kind, row.Email)
+	if err != nil {
+		row.Error = fmt.Sprintf("looking up the existing user: %v", err)
+		return
+	}
+	if id == nil {
+		row.Error = fmt.Sprintf("%v, but has no university profile; the profile has to be created by hand", helper.ErrAuthUserExists)
+		return
+	}
+	row.UserID = id.Hex()
+	complete, err := ctrl.Repo.ImportedProfileComplete(kind, *id, row)
+	if err != nil {
+		row.Error = fmt.Sprintf("checking the existing user's profile: %v", err)
+		return
+	}
+	if complete {
+		row.Status = repositories.ImportRowSkipped
+		row.Error = helper.ErrAuthUserExists.Error()
+		return
+	}
+	if err := ctrl.completeImportedProfile(kind, *id, row); err != nil {
+		row.Error = fmt.Sprintf("the user was already registered, but their profile could not be completed: %v", err)
+		return
+	}
+	row.Status = repositories.ImportRowCreated
+	row.Error = ""
+}
+
+// completeImportedProfile waits for the profile auth-service creates after registration, creating
+// it if it does not appear in time, and registers a student for their major.
+func (ctrl *Controllers) completeImportedProfile(kind repositories.ImportKind, id primitive.ObjectID, row *repositories.ImportRow) error {
+	deadline := time.Now().Add(importProfileWait)
+	for {
+		exists, err := ctrl.Repo.ImportedProfileExists(kind, id)
+		if err != nil {
+			return err
+		}
+		if exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			if err := ctrl.Repo.CreateImportedProfile(kind, id, row); err != nil {
+				return err
+			}
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if kind == repositories.ImportStudents {
+		_, err := ctrl.Repo.RegisterStudentForMajor(id, row.MajorID)
+		return err
+	}
+	return nil
+}
+
+// GetImportJobs lists the import jobs, newest first, without their rows.
+func (ctrl *Controllers) GetImportJobs(c *gin.Context) {
+	jobs, err := ctrl.Repo.GetImportJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (ctrl *Controllers) importJob(c *gin.Context) (*repositories.ImportJob, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return nil, false
+	}
+	job, err := ctrl.Repo.GetImportJob(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import job not found"})
+		return nil, false
+	}
+	return job, true
+}
+
+// GetImportJob returns the job's progress and the outcome of each row processed so far.
+func (ctrl *Controllers) GetImportJob(c *gin.Context) {
+	job, ok := ctrl.importJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportReport downloads the outcome of every row as CSV, including the initial passwords
+// generated for created users. Each password is only included in the first report downloaded
+// after its row was processed; it is not kept afterwards.
+func (ctrl *Controllers) GetImportReport(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return
+	}
+	job, err := ctrl.Repo.TakeImportReport(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import job not found"})
+		return
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"row", "status", "first_name", "last_name", "email", "user_id", "initial_password", "error"})
+	for _, row := range job.Rows {
+		password := ""
+		if row.SealedPassword != "" {
+			if password, err = helper.OpenSecret(row.SealedPassword); err != nil {
+				password = "(unavailable: " + err.Error() + ")"
+			}
+		}
+		_ = w.Write([]string{strconv.Itoa(row.Row), string(row.Status), row.FirstName, row.LastName, row.Email, row.UserID, password, row.Error})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("import-%s-%s.csv", job.Kind, job.StartedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// studentIDParam reads a student path parameter given either as the student's ID or as their index
+// number, URL-encoded when it contains a slash (e.g. SW-12%2F2025).
+func (ctrl *Controllers) studentIDParam(c *gin.Context, name string) (primitive.ObjectID, bool) {
+	value := c.Param(name)
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		return id, true
+	}
+	return ctrl.studentIDByIndexNumber(c, value)
+}
+
+// studentIDByIndexNumber looks up the ID of the student with the index number.
+func (ctrl *Controllers) studentIDByIndexNumber(c *gin.Context, indexNumber string) (primitive.ObjectID, bool) {
+	if indexNumber == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "student_id or index_number is required"})
+		return primitive.NilObjectID, false
+	}
+	student, err := ctrl.Repo.GetStudentByIndexNumber(indexNumber)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return primitive.NilObjectID, false
+	}
+	if student == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No student with index number " + indexNumber})
+		return primitive.NilObjectID, false
+	}
+	return student.ID, true
+}
+
+// SetIndexPattern sets how the index numbers of the major's students are formed, e.g.
+// {"index_pattern": "SW-{seq}/{year}"}. It applies to students enrolling from now on.
+func (ctrl *Controllers) SetIndexPattern(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+	var req repositories.SetIndexPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	major, err := ctrl.Repo.SetIndexPattern(majorID, req.IndexPattern)
+	if errors.Is(err, repositories.ErrInvalidIndexPattern) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrMajorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	pattern := major.IndexPattern
+	if pattern == "" {
+		pattern = repositories.DefaultIndexPattern
+	}
+	c.JSON(http.StatusOK, gin.H{"index_pattern": pattern})
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetStudentLedger returns the student's ledger with the running balance after each entry.
+func (ctrl *Controllers) GetStudentLedger(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	account, err := ctrl.Repo.GetStudentAccount(studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// GetStudentBalance returns just the student's current balance.
+func (ctrl *Controllers) GetStudentBalance(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	balance, err := ctrl.Repo.GetStudentBalance(studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"student_id": studentID, "balance": balance})
+}
+
+// CreateLedgerEntry lets studentska služba post tuition instalments, payments and refunds by hand.
+func (ctrl *Controllers) CreateLedgerEntry(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	student, err := ctrl.Repo.GetStudentByID(studentID.Hex())
+	if err != nil || student == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+
+	var req repositories.CreateLedgerEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := repositories.LedgerEntry{
+		StudentID:     studentID,
+		Type:          req.Type,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		ExamSessionID: req.ExamSessionID,
+		AcademicYear:  req.AcademicYear,
+	}
+	if entry.Type == repositories.LedgerTuitionCharge && entry.AcademicYear == 0 {
+		entry.AcademicYear, err = ctrl.Repo.CurrentAcademicYear(time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := ctrl.Repo.PostLedgerEntry(&entry); err != nil {
+		if errors.Is(err, repositories.ErrInvalidLedgerEntry) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if entry.Type.IsCharge() {
+		_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+			RecipientID:    studentID,
+			RecipientType:  "id",
+			RecipientValue: studentID.Hex(),
+			Title:          "New charge on your account",
+			Content:        fmt.Sprintf("%s: %.2f has been charged to your account.", entry.Description, entry.Amount),
+		})
+		if err != nil {
+			ctrl.logger.Printf("Failed to notify student %s about ledger charge: %v", studentID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// SetMajorTuition sets the yearly tuition of the major's self-financed students, e.g.
+// {"tuition_fee": 120000, "tuition_instalments": 4}. It is charged on enrollment from now on.
+func (ctrl *Controllers) SetMajorTuition(c *gin.Context) {
+	majorID, ok := ctrl.existingMajorID(c)
+	if !ok {
+		return
+	}
+	var req repositories.SetTuitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	major, err := ctrl.Repo.SetMajorTuition(majorID, req.TuitionFee, req.TuitionInstalments)
+	if errors.Is(err, repositories.ErrInvalidLedgerEntry) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrMajorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tuition_fee": major.TuitionFee, "tuition_instalments": major.TuitionInstalments})
+}
+
+// ChargeEnrollmentTuition posts the tuition instalments of a self-financed enrollment, for
+// enrollments made before the major had a tuition fee or with a different amount, e.g.
+// {"amount": 120000, "instalments": 4}. Both fields default to the major's tuition settings.
+func (ctrl *Controllers) ChargeEnrollmentTuition(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid enrollment ID"})
+		return
+	}
+	enrollment, err := ctrl.Repo.GetEnrollmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if enrollment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrEnrollmentNotFound.Error()})
+		return
+	}
+
+	var req repositories.ChargeTuitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.Amount == 0 || req.Instalments == 0) && !enrollment.MajorID.IsZero() {
+		major, err := ctrl.Repo.GetMajorByID(enrollment.MajorID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if major != nil {
+			if req.Amount == 0 {
+				req.Amount = major.TuitionFee
+			}
+			if req.Instalments == 0 {
+				req.Instalments = major.TuitionInstalments
+			}
+		}
+	}
+
+	entries, err := ctrl.Repo.ChargeTuition(enrollment, req.Amount, req.Instalments)
+	if errors.Is(err, repositories.ErrInvalidLedgerEntry) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrTuitionAlreadyCharged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, err = ctrl.CreateNotificationByRecipient(repositories.Notification{
+		RecipientID:    enrollment.StudentID,
+		RecipientType:  "id",
+		RecipientValue: enrollment.StudentID.Hex(),
+		Title:          "Tuition charged",
+		Content:        fmt.Sprintf("Tuition of %.2f has been charged to your account in %d instalment(s).", req.Amount, len(entries)),
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to notify student %s about tuition charge: %v", enrollment.StudentID.Hex(), err)
+	}
+	c.JSON(http.StatusCreated, entries)
+}
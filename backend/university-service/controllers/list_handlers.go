@@ -1,84 +1,144 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	repositories "university-service/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
+// listQuery reads the page, sort order and filters of a list request, writing the error response
+// when they are invalid.
+func (ctrl *Controllers) listQuery(c *gin.Context, spec repositories.ListSpec) (repositories.ListQuery, bool) {
+	q, err := ctrl.Repo.ParseListQuery(c.Request.URL.Query(), spec)
+	if errors.Is(err, repositories.ErrInvalidListQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return q, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return q, false
+	}
+	return q, true
+}
+
+// respondList writes the listed items with their total count in the X-Total-Count header. A
+// request for a page (page or limit given) gets a ListPage; otherwise the plain list is returned.
+func respondList(c *gin.Context, q repositories.ListQuery, items interface{}, total int64) {
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	if q.Page > 0 {
+		c.JSON(http.StatusOK, repositories.NewListPage(q, items, total))
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// GetAllStudents lists students, filtered by major, department, year, graduated or index_number
+// and sorted by name, index_number, year, gpa or espb.
 func (ctrl *Controllers) GetAllStudents(c *gin.Context) {
-	students, err := ctrl.Repo.GetAllStudents()
+	q, ok := ctrl.listQuery(c, repositories.StudentListSpec)
+	if !ok {
+		return
+	}
+	students, total, err := ctrl.Repo.ListStudents(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, students)
+	respondList(c, q, students, total)
 }
 
+// GetAllProfessors lists professors, filtered by department or subject.
 func (ctrl *Controllers) GetAllProfessors(c *gin.Context) {
-	professors, err := ctrl.Repo.GetAllProfessors()
+	q, ok := ctrl.listQuery(c, repositories.ProfessorListSpec)
+	if !ok {
+		return
+	}
+	professors, total, err := ctrl.Repo.ListProfessors(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, professors)
+	respondList(c, q, professors, total)
 }
 
+// GetAllSubjects lists subjects, filtered by major, department, year, semester or professor.
 func (ctrl *Controllers) GetAllSubjects(c *gin.Context) {
-	subjects, err := ctrl.Repo.GetAllSubjects()
+	q, ok := ctrl.listQuery(c, repositories.SubjectListSpec)
+	if !ok {
+		return
+	}
+	subjects, total, err := ctrl.Repo.ListSubjects(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, subjects)
+	respondList(c, q, subjects, total)
 }
 
+// GetAllDepartments lists departments, filtered by head.
 func (ctrl *Controllers) GetAllDepartments(c *gin.Context) {
-	departments, err := ctrl.Repo.GetAllDepartments()
+	q, ok := ctrl.listQuery(c, repositories.DepartmentListSpec)
+	if !ok {
+		return
+	}
+	departments, total, err := ctrl.Repo.ListDepartments(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, departments)
+	respondList(c, q, departments, total)
 }
 
+// GetAllUniversities lists universities, filtered by location.
 func (ctrl *Controllers) GetAllUniversities(c *gin.Context) {
-	universities, err := ctrl.Repo.GetAllUniversities()
+	q, ok := ctrl.listQuery(c, repositories.UniversityListSpec)
+	if !ok {
+		return
+	}
+	universities, total, err := ctrl.Repo.ListUniversities(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, universities)
+	respondList(c, q, universities, total)
 }
 
+// GetAllAdministrators lists administrators, filtered by position.
 func (ctrl *Controllers) GetAllAdministrators(c *gin.Context) {
-	administrators, err := ctrl.Repo.GetAllAdministrators()
+	q, ok := ctrl.listQuery(c, repositories.AdministratorListSpec)
+	if !ok {
+		return
+	}
+	administrators, total, err := ctrl.Repo.ListAdministrators(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, administrators)
+	respondList(c, q, administrators, total)
 }
 
+// GetAllAssistants lists assistants, filtered by department, professor or subject.
 func (ctrl *Controllers) GetAllAssistants(c *gin.Context) {
-	assistants, err := ctrl.Repo.GetAllAssistants()
+	q, ok := ctrl.listQuery(c, repositories.AssistantListSpec)
+	if !ok {
+		return
+	}
+	assistants, total, err := ctrl.Repo.ListAssistants(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, assistants)
-}
-
-func (ctrl *Controllers) GetLectures(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Lectures endpoint"})
+	respondList(c, q, assistants, total)
 }
 
 func (ctrl *Controllers) PayTuition(c *gin.Context) {
@@ -88,11 +148,20 @@ func (ctrl *Controllers) PayTuition(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.Repo.PayTuition(&payment)
+	entry, err := ctrl.Repo.PayTuition(&payment)
+	if errors.Is(err, repositories.ErrInvalidLedgerEntry) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	balance, err := ctrl.Repo.GetStudentBalance(payment.StudentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Tuition payment successful!"})
+	c.JSON(http.StatusCreated, gin.H{"message": "Tuition payment successful!", "entry": entry, "balance": balance})
 }
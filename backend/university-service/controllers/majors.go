@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	repositories "university-service/repository"
@@ -54,14 +55,19 @@ func (ctrl *Controllers) CreateMajor(c *gin.Context) {
 	c.JSON(http.StatusCreated, major)
 }
 
+// GetAllMajors lists majors, filtered by department or duration.
 func (ctrl *Controllers) GetAllMajors(c *gin.Context) {
-	majors, err := ctrl.Repo.GetAllMajors()
+	q, ok := ctrl.listQuery(c, repositories.MajorListSpec)
+	if !ok {
+		return
+	}
+	majors, total, err := ctrl.Repo.ListMajors(q)
 	if err != nil {
 		ctrl.logger.Println(err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch majors"})
 		return
 	}
-	c.JSON(http.StatusOK, majors)
+	respondList(c, q, majors, total)
 }
 
 func (ctrl *Controllers) GetMajorByID(c *gin.Context) {
@@ -204,19 +210,12 @@ func (ctrl *Controllers) DeleteMajor(c *gin.Context) {
 		return
 	}
 
+	// Subjects, major-scoped exam periods and department links are handled by the major cascade policy
 	if err := ctrl.Repo.DeleteMajor(objID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete major"})
+		respondDeleteError(c, err)
 		return
 	}
 
-	if major.DepartmentID != nil {
-		err = ctrl.RemoveMajorFromDepartment(*major.DepartmentID, objID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{"message": "Major deleted successfully"})
 }
 
@@ -236,8 +235,31 @@ func (ctrl *Controllers) GetSubjectsFromMajor(c *gin.Context) {
 	c.JSON(http.StatusOK, subjects)
 }
 
+// RegisterStudentForMajor assigns a student to a major and copies the major's curriculum into
+// the student's subjects. Re-running it picks up subjects added to the curriculum since.
 func (ctrl *Controllers) RegisterStudentForMajor(c *gin.Context) {
-	// Placeholder - not yet implemented
+	studentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	majorID, err := primitive.ObjectIDFromHex(c.Param("major_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid major ID"})
+		return
+	}
+
+	student, err := ctrl.Repo.RegisterStudentForMajor(studentID, majorID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrStudentNotFound) || errors.Is(err, repositories.ErrMajorNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, student)
 }
 
 func (ctrl *Controllers) GetPassedSubjectsForStudent(c *gin.Context) {
@@ -357,14 +357,20 @@ func (ctrl *Controllers) GetNotificationByUserIDHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, notifications)
 }
 
+// GetAllNotificationsHandler lists notifications, filtered by recipient, recipient_type, seen or
+// a created_at range (created_at_from, created_at_to).
 func (ctrl *Controllers) GetAllNotificationsHandler(c *gin.Context) {
-	notifications, err := ctrl.Repo.GetAllNotifications()
+	q, ok := ctrl.listQuery(c, repositories.NotificationListSpec)
+	if !ok {
+		return
+	}
+	notifications, total, err := ctrl.Repo.ListNotifications(q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, notifications)
+	respondList(c, q, notifications, total)
 }
 
 func (ctrl *Controllers) DeleteNotificationHandler(c *gin.Context) {
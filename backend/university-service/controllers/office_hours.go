@@ -0,0 +1,322 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxAvailabilityWeeks = 26
+
+func officeHourError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrOfficeHourSlotNotFound), errors.Is(err, repositories.ErrOfficeHourBookingNotFound),
+		errors.Is(err, repositories.ErrRoomNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidOfficeHourSlot), errors.Is(err, repositories.ErrInvalidOfficeHourBooking):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrOfficeHourFull), errors.Is(err, repositories.ErrOfficeHoursBlocked),
+		errors.Is(err, repositories.ErrAlreadyBookedOfficeHour), errors.Is(err, repositories.ErrOfficeHourSlotHasBookings):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (ctrl *Controllers) notifyOfficeHours(recipientID primitive.ObjectID, title, content string) {
+	_, err := ctrl.CreateNotificationByRecipient(repositories.Notification{
+		RecipientID:    recipientID,
+		RecipientType:  "id",
+		RecipientValue: recipientID.Hex(),
+		Title:          title,
+		Content:        content,
+	})
+	if err != nil {
+		ctrl.logger.Printf("Failed to send office-hour notification to %s: %v", recipientID.Hex(), err)
+	}
+}
+
+func describeConsultation(booking *repositories.OfficeHourBooking) string {
+	when := booking.Start.Format("02.01.2006. 15:04")
+	if booking.Location == "" {
+		return when
+	}
+	return when + ", " + booking.Location
+}
+
+// ownOfficeHourSlot loads the slot in the path, which only the professor or assistant holding it
+// and studentska služba may change.
+func (ctrl *Controllers) ownOfficeHourSlot(c *gin.Context) (*repositories.OfficeHourSlot, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid office-hour slot ID"})
+		return nil, false
+	}
+	slot, err := ctrl.Repo.GetOfficeHourSlotByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if slot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrOfficeHourSlotNotFound.Error()})
+		return nil, false
+	}
+	if userID, _ := currentUserID(c); c.GetString("user_type") != "STUDENTSKA_SLUZBA" && userID != slot.StaffID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the professor or assistant holding these office hours can change them"})
+		return nil, false
+	}
+	return slot, true
+}
+
+// CreateOfficeHourSlot publishes weekly office hours of the signed-in professor or assistant,
+// e.g. {"day": 2, "start_time": "12:00", "end_time": "13:30", "capacity": 3, "room_id": "...",
+// "online_link": "https://meet.example.com/abc", "block_during_exam_periods": true}.
+func (ctrl *Controllers) CreateOfficeHourSlot(c *gin.Context) {
+	staffID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID in token"})
+		return
+	}
+	role := repositories.TeacherProfessor
+	if c.GetString("user_type") == "ASSISTANT" {
+		role = repositories.TeacherAssistant
+	}
+	var req repositories.OfficeHourSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slot, err := ctrl.Repo.CreateOfficeHourSlot(staffID, role, req)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, slot)
+}
+
+// UpdateOfficeHourSlot replaces an office-hour slot. Moving it to another day or time is
+// rejected with 409 while students are booked.
+func (ctrl *Controllers) UpdateOfficeHourSlot(c *gin.Context) {
+	existing, ok := ctrl.ownOfficeHourSlot(c)
+	if !ok {
+		return
+	}
+	var req repositories.OfficeHourSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slot, err := ctrl.Repo.UpdateOfficeHourSlot(existing.ID, req)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, slot)
+}
+
+// DeleteOfficeHourSlot removes an office-hour slot, cancelling and notifying its upcoming bookings.
+func (ctrl *Controllers) DeleteOfficeHourSlot(c *gin.Context) {
+	slot, ok := ctrl.ownOfficeHourSlot(c)
+	if !ok {
+		return
+	}
+	userID, _ := currentUserID(c)
+	cancelled, err := ctrl.Repo.DeleteOfficeHourSlot(slot.ID, userID)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+	for i := range cancelled {
+		ctrl.notifyOfficeHours(cancelled[i].StudentID, "Consultation cancelled: "+slot.StaffName,
+			fmt.Sprintf("%s no longer holds these office hours, so your consultation on %s is cancelled.", slot.StaffName, describeConsultation(&cancelled[i])))
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Office-hour slot deleted successfully", "cancelled_bookings": len(cancelled)})
+}
+
+// GetOfficeHourSlots lists office-hour slots, filtered by staff, staff_role, day or room and
+// sorted by day or staff.
+func (ctrl *Controllers) GetOfficeHourSlots(c *gin.Context) {
+	q, ok := ctrl.listQuery(c, repositories.OfficeHourSlotListSpec)
+	if !ok {
+		return
+	}
+	slots, total, err := ctrl.Repo.ListOfficeHourSlots(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondList(c, q, slots, total)
+}
+
+func (ctrl *Controllers) GetOfficeHourSlotByID(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid office-hour slot ID"})
+		return
+	}
+	slot, err := ctrl.Repo.GetOfficeHourSlotByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if slot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrOfficeHourSlotNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, slot)
+}
+
+// GetOfficeHourAvailability lists the slot's dates from ?date= (default today) for ?weeks=
+// (default 4) weeks with the places still free on each.
+func (ctrl *Controllers) GetOfficeHourAvailability(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid office-hour slot ID"})
+		return
+	}
+	from, ok := scheduleDay(c)
+	if !ok {
+		return
+	}
+	weeks := 4
+	if value := c.Query("weeks"); value != "" {
+		weeks, err = strconv.Atoi(value)
+		if err != nil || weeks < 1 || weeks > maxAvailabilityWeeks {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("weeks must be a number from 1 to %d", maxAvailabilityWeeks)})
+			return
+		}
+	}
+	occurrences, err := ctrl.Repo.GetOfficeHourAvailability(id, from, weeks)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, occurrences)
+}
+
+// BookOfficeHour books the signed-in student a place in the slot on a date, e.g.
+// {"date": "2025-11-04", "topic": "Questions about the second colloquium"}, and tells the
+// professor or assistant.
+func (ctrl *Controllers) BookOfficeHour(c *gin.Context) {
+	slotID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid office-hour slot ID"})
+		return
+	}
+	studentID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID in token"})
+		return
+	}
+	var req repositories.BookOfficeHourRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	student, err := ctrl.Repo.GetStudentByIDObject(studentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+	booking, err := ctrl.Repo.BookOfficeHour(slotID, student, req)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+
+	content := fmt.Sprintf("%s booked your office hours on %s.", booking.StudentName, describeConsultation(booking))
+	if booking.Topic != "" {
+		content += " Topic: " + booking.Topic
+	}
+	ctrl.notifyOfficeHours(booking.StaffID, "New consultation booking", content)
+	c.JSON(http.StatusCreated, booking)
+}
+
+// CancelOfficeHourBooking cancels a booking that has not started. The student who made it, the
+// professor or assistant holding the office hours and studentska služba may cancel it; the other
+// side is notified.
+func (ctrl *Controllers) CancelOfficeHourBooking(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid office-hour booking ID"})
+		return
+	}
+	booking, err := ctrl.Repo.GetOfficeHourBookingByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if booking == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrOfficeHourBookingNotFound.Error()})
+		return
+	}
+	userID, _ := currentUserID(c)
+	if c.GetString("user_type") != "STUDENTSKA_SLUZBA" && userID != booking.StudentID && userID != booking.StaffID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the student or the professor or assistant can cancel this booking"})
+		return
+	}
+	booking, err = ctrl.Repo.CancelOfficeHourBooking(id, userID)
+	if err != nil {
+		officeHourError(c, err)
+		return
+	}
+
+	if userID == booking.StudentID {
+		ctrl.notifyOfficeHours(booking.StaffID, "Consultation cancelled",
+			fmt.Sprintf("%s cancelled their consultation on %s.", booking.StudentName, describeConsultation(booking)))
+	} else {
+		ctrl.notifyOfficeHours(booking.StudentID, "Consultation cancelled: "+booking.StaffName,
+			fmt.Sprintf("Your consultation with %s on %s has been cancelled.", booking.StaffName, describeConsultation(booking)))
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+// GetOfficeHourAgenda returns the office hours and booked students of ?date= (default today) for
+// the signed-in professor or assistant, or for ?staff= when studentska služba asks.
+func (ctrl *Controllers) GetOfficeHourAgenda(c *gin.Context) {
+	staffID, ok := currentUserID(c)
+	if c.GetString("user_type") == "STUDENTSKA_SLUZBA" {
+		var err error
+		staffID, err = primitive.ObjectIDFromHex(c.Query("staff"))
+		ok = err == nil
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID"})
+		return
+	}
+	day, ok := scheduleDay(c)
+	if !ok {
+		return
+	}
+	agenda, err := ctrl.Repo.GetOfficeHourAgenda(staffID, day)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, agenda)
+}
+
+// GetStudentOfficeHourBookings lists a student's bookings, filtered by status, staff, slot or
+// start (start_from, start_to) and sorted by start or created_at.
+func (ctrl *Controllers) GetStudentOfficeHourBookings(c *gin.Context) {
+	studentID, ok := ctrl.studentIDParam(c, "id")
+	if !ok {
+		return
+	}
+	q, ok := ctrl.listQuery(c, repositories.OfficeHourBookingListSpec)
+	if !ok {
+		return
+	}
+	q.Filter["student_id"] = studentID
+	bookings, total, err := ctrl.Repo.ListOfficeHourBookings(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondList(c, q, bookings, total)
+}
@@ -83,7 +83,7 @@ func (ctrl *Controllers) DeleteProfessor(c *gin.Context) {
 
 	err := ctrl.Repo.DeleteProfessor(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// roomErrorStatus maps room reservation errors to HTTP status codes.
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrRoomAlreadyBooked), errors.Is(err, repositories.ErrRoomHasUpcomingBookings),
+		errors.Is(err, repositories.ErrRoomInTimetable), errors.Is(err, repositories.ErrRoomBusy):
+		return http.StatusConflict
+	case errors.Is(err, repositories.ErrRoomCapacityTooLow):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (ctrl *Controllers) CreateRoom(c *gin.Context) {
+	var room repositories.Room
+	if err := c.ShouldBindJSON(&room); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if room.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if room.Capacity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be greater than 0"})
+		return
+	}
+
+	if err := ctrl.Repo.CreateRoom(&room); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, room)
+}
+
+// GetAllRooms lists rooms, filtered by building or equipment.
+func (ctrl *Controllers) GetAllRooms(c *gin.Context) {
+	q, ok := ctrl.listQuery(c, repositories.RoomListSpec)
+	if !ok {
+		return
+	}
+	rooms, total, err := ctrl.Repo.ListRooms(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondList(c, q, rooms, total)
+}
+
+func (ctrl *Controllers) GetRoomByID(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+	room, err := ctrl.Repo.GetRoomByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if room == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+func (ctrl *Controllers) UpdateRoom(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+	existing, err := ctrl.Repo.GetRoomByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	var req repositories.Room
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ID = id
+	req.CreatedAt = existing.CreatedAt
+	if req.Name == "" {
+		req.Name = existing.Name
+	}
+	if req.Building == "" {
+		req.Building = existing.Building
+	}
+	if req.Capacity == 0 {
+		req.Capacity = existing.Capacity
+	}
+	if req.Equipment == nil {
+		req.Equipment = existing.Equipment
+	}
+	if req.Capacity < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be greater than 0"})
+		return
+	}
+
+	if err := ctrl.Repo.UpdateRoom(&req); err != nil {
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
+
+func (ctrl *Controllers) DeleteRoom(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+	if err := ctrl.Repo.DeleteRoom(id); err != nil {
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Room deleted successfully"})
+}
+
+// GetRoomOccupancy returns bookings and free slots for a day (?date=YYYY-MM-DD, default today).
+// Pass ?room_id= to limit the result to a single room.
+func (ctrl *Controllers) GetRoomOccupancy(c *gin.Context) {
+	day := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, dateStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+			return
+		}
+		day = parsed
+	}
+
+	var roomID *primitive.ObjectID
+	if roomIDStr := c.Query("room_id"); roomIDStr != "" {
+		id, err := primitive.ObjectIDFromHex(roomIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+			return
+		}
+		roomID = &id
+	}
+
+	occupancy, err := ctrl.Repo.GetRoomOccupancy(day, roomID)
+	if err != nil {
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, occupancy)
+}
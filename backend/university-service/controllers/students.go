@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -27,6 +28,8 @@ func (ctrl *Controllers) CreateStudent(c *gin.Context) {
 	}
 
 	student := &req.Student
+	// Index numbers are only assigned on enrollment
+	student.IndexNumber = ""
 
 	// Use auth service's user_id as student ID so it matches the user record in auth DB
 	if req.UserID != "" {
@@ -52,10 +55,19 @@ func (ctrl *Controllers) CreateStudent(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "major not found"})
 			return
 		}
-		student.Subjects = []repositories.Subject{}
-		for _, subject := range major.Subjects {
-			student.Subjects = append(student.Subjects, subject)
+		// A new student has no enrollments yet, so they belong to the current generation
+		generation, err := ctrl.Repo.CurrentAcademicYear(time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error getting academic year: " + err.Error()})
+			return
+		}
+		curriculum, version, err := ctrl.Repo.PinnedCurriculum(student, major, false, generation)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error getting curriculum: " + err.Error()})
+			return
 		}
+		student.Subjects = repositories.MergeCurriculum(nil, curriculum.CurriculumFor(nil), true)
+		student.CurriculumVersion = version
 	}
 
 	err := ctrl.Repo.CreateStudent(student)
@@ -67,10 +79,14 @@ func (ctrl *Controllers) CreateStudent(c *gin.Context) {
 	c.JSON(http.StatusCreated, student)
 }
 
+// GetStudentByID returns the student by their ID or index number.
 func (ctrl *Controllers) GetStudentByID(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := ctrl.studentIDParam(c, "id")
+	if !ok {
+		return
+	}
 
-	student, err := ctrl.Repo.GetStudentByID(id)
+	student, err := ctrl.Repo.GetStudentByIDObject(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
@@ -170,39 +186,42 @@ func (ctrl *Controllers) updateUserFields(user *repositories.User, updateData ma
 	return nil
 }
 
-// updateStudentFields updates Student-specific fields from a map.
-func (ctrl *Controllers) updateStudentFields(student *repositories.Student, updateData map[string]interface{}) error {
-	var errors []string
+// studentFieldsManagedElsewhere are the student fields UpdateStudent does not change, with the
+// place they are changed instead.
+var studentFieldsManagedElsewhere = map[string]string{
+	"major_id": "PUT /students/:id/major/:major_id",
+	"year":     "the enrollment actions (/students/:id/enrollments and /students/:id/advance)",
+	"gpa":      "exam grades",
+	"esbp":     "exam grades and enrollments",
+}
 
-	if majorID, ok := updateData["major_id"]; ok {
-		if majorIDStr, ok := majorID.(string); ok {
-			if objectID, err := primitive.ObjectIDFromHex(majorIDStr); err == nil {
-				student.MajorID = objectID
-			} else {
-				errors = append(errors, "major_id must be a valid ObjectID")
-			}
-			major, err := ctrl.Repo.GetMajorByID(student.MajorID)
-			if err != nil {
-				errors = append(errors, "error getting major: "+err.Error())
-			}
-			if major == nil {
-				errors = append(errors, "major not found")
-			}
-			student.Subjects = []repositories.Subject{}
-			for _, subject := range major.Subjects {
-				student.Subjects = append(student.Subjects, subject)
-			}
-		} else {
-			errors = append(errors, "major_id must be a string")
+// checkStudentFieldsManagedElsewhere rejects an update that tries to change a field listed in
+// studentFieldsManagedElsewhere. Sending a field with its current value is allowed, so clients can
+// send back the whole student.
+func checkStudentFieldsManagedElsewhere(student *repositories.Student, updateData map[string]interface{}) error {
+	current := map[string]interface{}{
+		"major_id": student.MajorID.Hex(),
+		"year":     float64(student.Year),
+		"gpa":      student.GPA,
+		"esbp":     float64(student.ESPB),
+	}
+	for field, managedBy := range studentFieldsManagedElsewhere {
+		value, ok := updateData[field]
+		if !ok || value == current[field] {
+			continue
 		}
-	}
-	if year, ok := updateData["year"]; ok {
-		if yearFloat, ok := year.(float64); ok {
-			student.Year = int(yearFloat)
-		} else {
-			errors = append(errors, "year must be a number")
+		if field == "major_id" && value == "" && student.MajorID.IsZero() {
+			continue
 		}
+		return fmt.Errorf("%s cannot be changed here, it is managed through %s", field, managedBy)
 	}
+	return nil
+}
+
+// updateStudentFields updates Student-specific fields from a map.
+func (ctrl *Controllers) updateStudentFields(student *repositories.Student, updateData map[string]interface{}) error {
+	var errors []string
+
 	if highschoolGPA, ok := updateData["highschool_gpa"]; ok {
 		if gpaFloat, ok := highschoolGPA.(float64); ok {
 			student.HighschoolGPA = gpaFloat
@@ -210,13 +229,6 @@ func (ctrl *Controllers) updateStudentFields(student *repositories.Student, upda
 			errors = append(errors, "highschool_gpa must be a number")
 		}
 	}
-	if gpa, ok := updateData["gpa"]; ok {
-		if gpaFloat, ok := gpa.(float64); ok {
-			student.GPA = gpaFloat
-		} else {
-			errors = append(errors, "gpa must be a number")
-		}
-	}
 	if cvFile, ok := updateData["cv_file"]; ok {
 		if cvFileStr, ok := cvFile.(string); ok {
 			student.CVFile = cvFileStr
@@ -269,6 +281,11 @@ func (ctrl *Controllers) UpdateStudent(c *gin.Context) {
 		return
 	}
 
+	if err := checkStudentFieldsManagedElsewhere(existingStudent, updateData); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := ctrl.updateUserFields(&existingStudent.User, updateData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -293,7 +310,7 @@ func (ctrl *Controllers) DeleteStudent(c *gin.Context) {
 
 	err := ctrl.Repo.DeleteStudent(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
@@ -317,6 +334,20 @@ func (ctrl *Controllers) HasStudentPassedAllSubjectsForCurrentYear(student *repo
 	return true
 }
 
+// studentStanding refreshes the student's GPA and earned ESPB and returns their major at the
+// curriculum version they are pinned to, whose curriculum decides which ESPB thresholds apply
+// (nil when the student has no major).
+func (ctrl *Controllers) studentStanding(student *repositories.Student) (*repositories.Major, error) {
+	if err := ctrl.UpdateStudentGPA(student); err != nil {
+		return nil, err
+	}
+	return ctrl.Repo.GetStudentMajor(student)
+}
+
+// AdvanceToNextYear enrolls the student in the next academic year. A student who has the ESPB
+// required by their major (or, for curricula without ESPB, has passed every subject of the
+// current year) moves up a year; otherwise they repeat the current year (obnova godine).
+// The optional body is an EnrollmentRequest.
 func (ctrl *Controllers) AdvanceToNextYear(c *gin.Context) {
 	id := c.Param("id")
 
@@ -325,21 +356,70 @@ func (ctrl *Controllers) AdvanceToNextYear(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
 	}
-
-	if !ctrl.HasStudentPassedAllSubjectsForCurrentYear(student) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Student has not passed all subjects for current year"})
+	req, ok := bindEnrollmentRequest(c)
+	if !ok {
 		return
 	}
 
-	student.Year++
+	err = ctrl.Repo.CheckYearAdvanceWindow(time.Now())
+	if errors.Is(err, repositories.ErrOutsideAcademicCalendar) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	err = ctrl.Repo.UpdateStudent(student)
+	major, err := ctrl.studentStanding(student)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	var reason string
+	if major != nil && repositories.CurriculumHasESPB(major.Subjects) {
+		required := major.ESPBToAdvanceFrom(student.Year)
+		if espb := student.ESPB + req.TransferredESPB; espb < required {
+			reason = fmt.Sprintf("Student has %d ESPB, %d are required to enroll in year %d", espb, required, student.Year+1)
+		}
+	} else if !ctrl.HasStudentPassedAllSubjectsForCurrentYear(student) {
+		reason = "Student has not passed all subjects for current year"
+	}
+	if reason == "" && major != nil && major.Duration > 0 && student.Year >= major.Duration {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Student has completed the final year of %s and should request graduation", major.Name)})
+		return
+	}
 
-	c.JSON(http.StatusOK, student)
+	academicYear := req.AcademicYear
+	if academicYear == 0 {
+		academicYear, err = ctrl.nextAcademicYear(student.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	enrollment := &repositories.Enrollment{
+		AcademicYear:     academicYear,
+		YearOfStudy:      student.Year + 1,
+		Financing:        req.Financing,
+		ESPBAtEnrollment: student.ESPB,
+		TransferredESPB:  req.TransferredESPB,
+		Electives:        req.Electives,
+		Note:             req.Note,
+	}
+	if reason != "" {
+		enrollment.YearOfStudy = student.Year
+		enrollment.Repeat = true
+		if enrollment.Note == "" {
+			enrollment.Note = reason
+		}
+	}
+	enrollment.EnrolledBy, _ = currentUserID(c)
+	if !ctrl.enroll(c, student, enrollment) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"student": student, "enrollment": enrollment})
 }
 
 func (ctrl *Controllers) RequestGraduation(c *gin.Context) {
@@ -354,10 +434,34 @@ func (ctrl *Controllers) RequestGraduation(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
 	}
-	if !ctrl.HasStudentPassedAllSubjects(student) {
+	major, err := ctrl.studentStanding(student)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if major != nil && repositories.CurriculumHasESPB(major.Subjects) {
+		required := major.ESPBToGraduate()
+		if student.ESPB < required {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":         fmt.Sprintf("Student has %d ESPB, %d are required to graduate", student.ESPB, required),
+				"espb":          student.ESPB,
+				"required_espb": required,
+			})
+			return
+		}
+	} else if !ctrl.HasStudentPassedAllSubjects(student) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Student has not passed all subjects"})
 		return
 	}
+	active, err := ctrl.Repo.HasActiveGraduationRequest(studentObjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if active {
+		c.JSON(http.StatusConflict, gin.H{"error": repositories.ErrGraduationRequestActive.Error()})
+		return
+	}
 	request := repositories.GraduationRequest{
 		StudentID: studentObjectID,
 	}
@@ -18,6 +18,10 @@ func (ctrl *Controllers) CreateCourse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "semester must be 1 (first semester) or 2 (second semester)"})
 		return
 	}
+	if subject.ESPB < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "espb must not be negative"})
+		return
+	}
 
 	err := ctrl.Repo.CreateSubject(&subject)
 	if err != nil {
@@ -51,6 +55,10 @@ func (ctrl *Controllers) UpdateSubject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "semester must be 1 (first semester) or 2 (second semester)"})
 		return
 	}
+	if subject.ESPB < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "espb must not be negative"})
+		return
+	}
 	oldSubject, err := ctrl.Repo.GetSubjectByID(id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -104,7 +112,7 @@ func (ctrl *Controllers) DeleteSubject(c *gin.Context) {
 
 	err := ctrl.Repo.DeleteSubject(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDeleteError(c, err)
 		return
 	}
 
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func timetableError(c *gin.Context, err error) {
+	var conflict *repositories.TimetableConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": conflict.Conflicts})
+	case errors.Is(err, repositories.ErrTimetableSlotNotFound), errors.Is(err, repositories.ErrRoomNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidTimetableSlot):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// scheduleDay reads ?date=YYYY-MM-DD, defaulting to today. Any day of the wanted week will do.
+func scheduleDay(c *gin.Context) (time.Time, bool) {
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		return time.Now(), true
+	}
+	day, err := time.ParseInLocation(time.DateOnly, dateStr, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// CreateTimetableSlot adds a weekly class, e.g. {"subject_id": "...", "type": "lecture",
+// "academic_year": 2025, "day": 1, "start_time": "10:15", "end_time": "12:00",
+// "room_id": "...", "teacher_id": "...", "teacher_role": "professor", "group": "G1"}.
+// Slots that take a room or teacher already in use at that time are rejected with 409.
+func (ctrl *Controllers) CreateTimetableSlot(c *gin.Context) {
+	var req repositories.TimetableSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slot, err := ctrl.Repo.CreateTimetableSlot(req)
+	if err != nil {
+		timetableError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, slot)
+}
+
+func (ctrl *Controllers) UpdateTimetableSlot(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timetable slot ID"})
+		return
+	}
+	var req repositories.TimetableSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slot, err := ctrl.Repo.UpdateTimetableSlot(id, req)
+	if err != nil {
+		timetableError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, slot)
+}
+
+func (ctrl *Controllers) DeleteTimetableSlot(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timetable slot ID"})
+		return
+	}
+	if err := ctrl.Repo.DeleteTimetableSlot(id); err != nil {
+		timetableError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Timetable slot deleted successfully"})
+}
+
+// GetTimetableSlots lists timetable slots, optionally filtered by ?academic_year=, ?semester=,
+// ?subject_id=, ?room_id= and ?teacher_id=.
+func (ctrl *Controllers) GetTimetableSlots(c *gin.Context) {
+	var filter repositories.TimetableFilter
+	for param, target := range map[string]*int{"academic_year": &filter.AcademicYear, "semester": &filter.Semester} {
+		if value := c.Query(param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number"})
+				return
+			}
+			*target = n
+		}
+	}
+	for param, target := range map[string]*primitive.ObjectID{"room_id": &filter.RoomID, "teacher_id": &filter.TeacherID} {
+		if value := c.Query(param); value != "" {
+			id, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = id
+		}
+	}
+	if value := c.Query("subject_id"); value != "" {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subject_id"})
+			return
+		}
+		filter.SubjectIDs = []primitive.ObjectID{id}
+	}
+
+	slots, err := ctrl.Repo.GetTimetableSlots(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, slots)
+}
+
+// GetStudentSchedule returns a student's classes for the week containing ?date= (default today).
+func (ctrl *Controllers) GetStudentSchedule(c *gin.Context) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	ctrl.respondWithSchedule(c, studentID, ctrl.Repo.GetStudentSchedule)
+}
+
+// GetProfessorSchedule returns a professor's classes for the week containing ?date= (default today).
+func (ctrl *Controllers) GetProfessorSchedule(c *gin.Context) {
+	professorID, err := primitive.ObjectIDFromHex(c.Param("professorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid professor ID"})
+		return
+	}
+	ctrl.respondWithSchedule(c, professorID, ctrl.Repo.GetProfessorSchedule)
+}
+
+// GetAssistantSchedule returns an assistant's classes for the week containing ?date= (default today).
+func (ctrl *Controllers) GetAssistantSchedule(c *gin.Context) {
+	assistantID, err := primitive.ObjectIDFromHex(c.Param("assistantId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assistant ID"})
+		return
+	}
+	ctrl.respondWithSchedule(c, assistantID, ctrl.Repo.GetAssistantSchedule)
+}
+
+// GetLectures returns the weekly schedule of the signed-in student, professor or assistant for
+// the week containing ?date= (default today).
+func (ctrl *Controllers) GetLectures(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID in token"})
+		return
+	}
+	switch c.GetString("user_type") {
+	case "STUDENT":
+		ctrl.respondWithSchedule(c, userID, ctrl.Repo.GetStudentSchedule)
+	case "PROFESSOR":
+		ctrl.respondWithSchedule(c, userID, ctrl.Repo.GetProfessorSchedule)
+	case "ASSISTANT":
+		ctrl.respondWithSchedule(c, userID, ctrl.Repo.GetAssistantSchedule)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only students, professors and assistants have a lecture schedule; use /timetable instead"})
+	}
+}
+
+func (ctrl *Controllers) respondWithSchedule(c *gin.Context, ownerID primitive.ObjectID, load func(primitive.ObjectID, time.Time) (*repositories.WeeklySchedule, error)) {
+	day, ok := scheduleDay(c)
+	if !ok {
+		return
+	}
+	schedule, err := load(ownerID, day)
+	if errors.Is(err, repositories.ErrStudentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
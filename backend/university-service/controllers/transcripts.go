@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	helper "university-service/helpers"
+	repositories "university-service/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// transcriptVerifyURL is the public address printed on transcripts for checking a verification code.
+func transcriptVerifyURL(code string) string {
+	baseURL := os.Getenv("UNIVERSITY_PUBLIC_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8088"
+	}
+	return baseURL + "/transcripts/verify/" + code
+}
+
+// GetTranscript returns the student's transcript (prepis ocena) as JSON, or as a PDF with ?format=pdf.
+// Both carry a verification code that can be checked through VerifyTranscript.
+func (ctrl *Controllers) GetTranscript(c *gin.Context) {
+	studentID, ok := ctrl.studentIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	transcript, err := ctrl.Repo.IssueTranscript(studentID)
+	if errors.Is(err, repositories.ErrStudentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") != "pdf" {
+		c.JSON(http.StatusOK, transcript)
+		return
+	}
+
+	pdf, err := helper.RenderTranscriptPDF(transcript, transcriptVerifyURL(transcript.VerificationCode))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate transcript PDF"})
+		return
+	}
+	filename := fmt.Sprintf("transcript-%s.pdf", transcript.VerificationCode)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// VerifyTranscript is public: anyone holding a transcript (e.g. an employer) can confirm it was
+// issued by the university and see the grades it was issued with.
+func (ctrl *Controllers) VerifyTranscript(c *gin.Context) {
+	code := c.Param("code")
+	issued, err := ctrl.Repo.VerifyTranscript(code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if issued == nil {
+		c.JSON(http.StatusNotFound, repositories.TranscriptVerification{Valid: false, VerificationCode: code})
+		return
+	}
+
+	c.JSON(http.StatusOK, repositories.TranscriptVerification{
+		Valid:            true,
+		VerificationCode: issued.VerificationCode,
+		StudentName:      issued.Transcript.StudentName,
+		IndexNumber:      issued.Transcript.IndexNumber,
+		MajorName:        issued.Transcript.MajorName,
+		IssuedAt:         issued.IssuedAt,
+		GPA:              issued.Transcript.GPA,
+		TotalESPB:        issued.Transcript.TotalESPB,
+		PassedCount:      issued.Transcript.PassedCount,
+		Entries:          issued.Transcript.Entries,
+	})
+}
@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	repositories "university-service/repository"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// rosterStatusLabels are the attendance markings printed on the roster.
+var rosterStatusLabels = map[repositories.ExamStatus]string{
+	repositories.RegistrationAttended: "Present",
+	repositories.RegistrationAbsent:   "Absent",
+}
+
+// RenderExamRosterPDF renders the exam roster as an A4 PDF for signing in students on paper,
+// with the attendance recorded so far and a summary at the end.
+func RenderExamRosterPDF(roster *repositories.ExamRoster) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	text := func(s string) string { return tr(latinDiacritics.Replace(s)) }
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, text("Exam Roster"), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "", 11)
+	for _, line := range [][2]string{
+		{"Subject", roster.SubjectName},
+		{"Professor", roster.ProfessorName},
+		{"Date", roster.ExamDate.Format("02.01.2006. 15:04")},
+		{"Location", roster.Location},
+	} {
+		pdf.CellFormat(40, 7, text(line[0]+":"), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, text(line[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	widths := []float64{10, 34, 66, 24, 16, 40}
+	headers := []string{"#", "Index", "Student", "Attendance", "Grade", "Signature"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range headers {
+		pdf.CellFormat(widths[i], 8, text(h), "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for i, e := range roster.Entries {
+		grade := ""
+		if e.Grade > 0 {
+			grade = fmt.Sprintf("%d", e.Grade)
+		}
+		row := []string{
+			fmt.Sprintf("%d", i+1),
+			e.IndexNumber,
+			e.LastName + " " + e.FirstName,
+			rosterStatusLabels[e.Status],
+			grade,
+			"",
+		}
+		for j, cell := range row {
+			align := "C"
+			if j == 2 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[j], 8, text(cell), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(4)
+
+	stats := roster.Statistics
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, fmt.Sprintf("Registered: %d   Present: %d   Absent: %d", stats.Registered, stats.Attended, stats.Absent), "", 1, "L", false, 0, "")
+	if stats.Graded > 0 {
+		pdf.CellFormat(0, 7, fmt.Sprintf("Passed: %d / %d (%.0f%%)   Average grade: %.2f", stats.Passed, stats.Graded, stats.PassRate*100, stats.AverageGrade), "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	secretKeyOnce sync.Once
+	secretKey     []byte
+)
+
+// secretBoxKey derives the key secrets are encrypted with from IMPORT_PASSWORD_KEY. Without it a
+// random key is used, so secrets stored before a restart can no longer be read.
+func secretBoxKey() []byte {
+	secretKeyOnce.Do(func() {
+		if value := os.Getenv("IMPORT_PASSWORD_KEY"); value != "" {
+			sum := sha256.Sum256([]byte(value))
+			secretKey = sum[:]
+			return
+		}
+		secretKey = make([]byte, 32)
+		if _, err := rand.Read(secretKey); err != nil {
+			panic(fmt.Sprintf("generating the secret key: %v", err))
+		}
+	})
+	return secretKey
+}
+
+func secretBoxCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secretBoxKey())
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealSecret encrypts a secret, like a generated password, for storing in the database.
+func SealSecret(plaintext string) (string, error) {
+	gcm, err := secretBoxCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret decrypts a secret sealed by SealSecret.
+func OpenSecret(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := secretBoxCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("sealed secret is too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("the secret can no longer be decrypted")
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,186 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Service tokens are valid for 24h; refreshing after half of that keeps a margin for clock drift.
+const serviceTokenLifetime = 12 * time.Hour
+
+var (
+	serviceTokenMu      sync.Mutex
+	serviceToken        string
+	serviceTokenExpires time.Time
+)
+
+func authServiceURL() string {
+	if url := os.Getenv("AUTH_SERVICE_URL"); url != "" {
+		return url
+	}
+	return "http://auth-service:8080"
+}
+
+func employmentServiceURL() string {
+	if url := os.Getenv("EMPLOYMENT_SERVICE_URL"); url != "" {
+		return url
+	}
+	return "http://employment-service:8089"
+}
+
+// ServiceToken returns a token for calling other services as the university-service account.
+// The token is cached and renewed well before it expires.
+func ServiceToken() (string, error) {
+	serviceTokenMu.Lock()
+	defer serviceTokenMu.Unlock()
+
+	if serviceToken != "" && time.Now().Before(serviceTokenExpires) {
+		return serviceToken, nil
+	}
+
+	password := os.Getenv("UNIVERSITY_SERVICE_PASSWORD")
+	if password == "" {
+		return "", fmt.Errorf("UNIVERSITY_SERVICE_PASSWORD is not set")
+	}
+	body, err := json.Marshal(map[string]string{
+		"service_name": "university-service",
+		"password":     password,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(authServiceURL()+"/service-token", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to auth service: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get service token, status: %d, response: %s", resp.StatusCode, string(respBody))
+	}
+
+	var tokenResponse struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	serviceToken = tokenResponse.Token
+	serviceTokenExpires = time.Now().Add(serviceTokenLifetime)
+	return serviceToken, nil
+}
+
+// ServiceError is returned when another service answers with an error status.
+type ServiceError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// callService sends a JSON request to another service with the university-service token.
+// The response body is decoded into out when out is not nil.
+func callService(method, url string, payload, out interface{}) error {
+	token, err := ServiceToken()
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &ServiceError{Method: method, URL: url, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// CandidateDegree is the degree recorded on the student's employment-service candidate profile.
+type CandidateDegree struct {
+	DiplomaNumber string    `json:"diploma_number"`
+	Major         string    `json:"major"`
+	GraduatedAt   time.Time `json:"graduated_at"`
+	GPA           float64   `json:"gpa"`
+	ESPB          int       `json:"espb"`
+	ThesisTopic   string    `json:"thesis_topic"`
+}
+
+// UpdateCandidateDegree records the degree on the candidate with the same ID as the student.
+func UpdateCandidateDegree(studentID string, degree CandidateDegree) error {
+	return callService(http.MethodPut, fmt.Sprintf("%s/candidates/%s/degree", employmentServiceURL(), studentID), degree, nil)
+}
+
+// ErrAuthUserExists is returned by RegisterAuthUser when auth-service already has a user with the email.
+var ErrAuthUserExists = errors.New("a user with this email already exists")
+
+// AuthUser is the payload of auth-service's register flow.
+type AuthUser struct {
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Email       string     `json:"email"`
+	Password    string     `json:"password"`
+	Phone       string     `json:"phone"`
+	Address     string     `json:"address"`
+	JMBG        string     `json:"jmbg"`
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
+	UserType    string     `json:"user_type"`
+}
+
+// RegisterAuthUser creates the user through auth-service's register flow and returns their user
+// ID. auth-service then creates the matching profile in university-service on its own.
+func RegisterAuthUser(user AuthUser) (string, error) {
+	var response struct {
+		UserID string `json:"user_id"`
+	}
+	err := callService(http.MethodPost, authServiceURL()+"/users/register", user, &response)
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusConflict {
+		return "", ErrAuthUserExists
+	}
+	if err != nil {
+		return "", err
+	}
+	if response.UserID == "" {
+		return "", fmt.Errorf("auth-service did not return a user ID")
+	}
+	return response.UserID, nil
+}
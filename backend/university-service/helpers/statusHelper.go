@@ -1,28 +1,212 @@
 package helper
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 	repositories "university-service/repository"
 )
 
-// Add to main.go
-func StartExamStatusUpdater(repo *repositories.Repository, logger *log.Logger) {
-	err := repo.UpdateExamSessionsToPending()
-	if err != nil {
-		logger.Printf("Error updating exam sessions to pending on startup: %v", err)
+// Notifier delivers a notification through the notification subsystem (Controllers.CreateNotificationByRecipient).
+type Notifier func(notification repositories.Notification) (int, error)
+
+// gradingDeadline reads EXAM_GRADING_DEADLINE_DAYS, the number of days professors have to grade an exam.
+func gradingDeadline(logger *log.Logger) time.Duration {
+	value := os.Getenv("EXAM_GRADING_DEADLINE_DAYS")
+	if value == "" {
+		return repositories.DefaultGradingDeadline
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		logger.Printf("Invalid EXAM_GRADING_DEADLINE_DAYS %q, using the default", value)
+		return repositories.DefaultGradingDeadline
 	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Add to main.go
+func StartExamStatusUpdater(repo *repositories.Repository, logger *log.Logger, notify Notifier) {
+	deadline := gradingDeadline(logger)
+	runExamLifecycle(repo, logger, notify, deadline)
 
 	ticker := time.NewTicker(1 * time.Hour)
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				err := repo.UpdateExamSessionsToPending()
-				if err != nil {
-					logger.Printf("Error updating exam sessions to pending: %v", err)
-				}
+				runExamLifecycle(repo, logger, notify, deadline)
 			}
 		}
 	}()
 }
+
+// runExamLifecycle moves exams Scheduled → Pending → Completed, opens and closes auto-activated exam
+// periods and sends the grading reminders that are due.
+func runExamLifecycle(repo *repositories.Repository, logger *log.Logger, notify Notifier, deadline time.Duration) {
+	if err := repo.UpdateExamSessionsToPending(deadline); err != nil {
+		logger.Printf("Error updating exam sessions to pending: %v", err)
+	}
+	if _, err := repo.CompleteGradedExamSessions(); err != nil {
+		logger.Printf("Error completing graded exam sessions: %v", err)
+	}
+	if err := repo.FlagExamSessionCalendarConflicts(time.Now()); err != nil {
+		logger.Printf("Error checking exam sessions against the academic calendar: %v", err)
+	}
+	if activated, deactivated, err := repo.SyncExamPeriodActivation(time.Now()); err != nil {
+		logger.Printf("Error activating exam periods: %v", err)
+	} else if activated+deactivated > 0 {
+		logger.Printf("Exam periods: %d activated, %d deactivated", activated, deactivated)
+	}
+	sendGradingReminders(repo, logger, notify, deadline)
+	escalateOverdueAppeals(repo, logger, notify)
+	sendOfficeHourReminders(repo, logger, notify)
+}
+
+// sendGradingReminders notifies professors as the grading deadline approaches; once it has passed,
+// studentska služba is notified as well.
+func sendGradingReminders(repo *repositories.Repository, logger *log.Logger, notify Notifier, deadline time.Duration) {
+	reminders, err := repo.DueGradingReminders(time.Now(), deadline)
+	if err != nil {
+		logger.Printf("Error collecting grading reminders: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		session := reminder.ExamSession
+		exam := fmt.Sprintf("the %s exam held on %s", session.Subject.Name, session.ExamDate.Format("02.01.2006."))
+		due := reminder.Deadline.Format("02.01.2006. 15:04")
+
+		var title, content string
+		switch reminder.Level {
+		case repositories.ReminderFirst:
+			title = "Reminder: grade the " + session.Subject.Name + " exam"
+			content = fmt.Sprintf("%d students of %s are still waiting for a grade. Grades are due by %s.", reminder.Remaining, exam, due)
+		case repositories.ReminderFinal:
+			title = "Final reminder: grades for " + session.Subject.Name + " are due tomorrow"
+			content = fmt.Sprintf("%d students of %s are still waiting for a grade. Grades are due by %s.", reminder.Remaining, exam, due)
+		default:
+			title = "Grading deadline missed: " + session.Subject.Name
+			content = fmt.Sprintf("The grading deadline for %s passed on %s and %d students are still waiting for a grade. Studentska služba has been notified.", exam, due, reminder.Remaining)
+		}
+
+		_, err := notify(repositories.Notification{
+			RecipientID:    session.Professor.ID,
+			RecipientType:  "id",
+			RecipientValue: session.Professor.ID.Hex(),
+			Title:          title,
+			Content:        content,
+		})
+		if err != nil {
+			logger.Printf("Failed to send grading reminder for exam session %s: %v", session.ID.Hex(), err)
+		}
+
+		if reminder.Level == repositories.ReminderOverdue {
+			professor := "the professor"
+			if session.Professor.FirstName != nil && session.Professor.LastName != nil {
+				professor = *session.Professor.FirstName + " " + *session.Professor.LastName
+			}
+			_, err := notify(repositories.Notification{
+				RecipientType:  "role",
+				RecipientValue: "STUDENTSKA_SLUZBA",
+				Title:          "Overdue exam grades: " + session.Subject.Name,
+				Content:        fmt.Sprintf("%s has not graded %s. The deadline was %s and %d students are still waiting for a grade.", professor, exam, due, reminder.Remaining),
+			})
+			if err != nil {
+				logger.Printf("Failed to escalate grading reminder for exam session %s: %v", session.ID.Hex(), err)
+			}
+		}
+	}
+}
+
+// escalateOverdueAppeals passes grade appeals the professor did not decide in time on to the
+// department head, or to studentska služba if the department has no head, and tells the student.
+func escalateOverdueAppeals(repo *repositories.Repository, logger *log.Logger, notify Notifier) {
+	appeals, err := repo.EscalateOverdueGradeAppeals(time.Now())
+	if err != nil {
+		logger.Printf("Error escalating overdue grade appeals: %v", err)
+	}
+
+	for _, appeal := range appeals {
+		reviewer := repositories.Notification{
+			RecipientType:  "role",
+			RecipientValue: "STUDENTSKA_SLUZBA",
+			Title:          "Grade appeal to review: " + appeal.SubjectName,
+			Content:        fmt.Sprintf("The professor did not decide on a student's appeal of grade %d in %s in time. Reason: %s", appeal.OriginalGrade, appeal.SubjectName, appeal.Reason),
+		}
+		if head, ok := appeal.Reviewer(); ok {
+			reviewer.RecipientID = head
+			reviewer.RecipientType = "id"
+			reviewer.RecipientValue = head.Hex()
+		}
+		if _, err := notify(reviewer); err != nil {
+			logger.Printf("Failed to notify the reviewer of grade appeal %s: %v", appeal.ID.Hex(), err)
+		}
+
+		_, err := notify(repositories.Notification{
+			RecipientID:    appeal.StudentID,
+			RecipientType:  "id",
+			RecipientValue: appeal.StudentID.Hex(),
+			Title:          "Grade appeal: " + appeal.SubjectName,
+			Content:        "The professor did not decide on your appeal in time, so it has been forwarded to the head of the department",
+		})
+		if err != nil {
+			logger.Printf("Failed to notify student %s about grade appeal %s: %v", appeal.StudentID.Hex(), appeal.ID.Hex(), err)
+		}
+	}
+}
+
+// sendOfficeHourReminders reminds students of consultations starting within a day, and tells the
+// professor or assistant once per office hours who is coming.
+func sendOfficeHourReminders(repo *repositories.Repository, logger *log.Logger, notify Notifier) {
+	bookings, err := repo.DueOfficeHourReminders(time.Now())
+	if err != nil {
+		logger.Printf("Error collecting office-hour reminders: %v", err)
+	}
+
+	var keys []string
+	byOccurrence := map[string][]repositories.OfficeHourBooking{}
+	for _, booking := range bookings {
+		when := booking.Start.Format("02.01.2006. 15:04")
+		if booking.Location != "" {
+			when += ", " + booking.Location
+		}
+		_, err := notify(repositories.Notification{
+			RecipientID:    booking.StudentID,
+			RecipientType:  "id",
+			RecipientValue: booking.StudentID.Hex(),
+			Title:          "Reminder: consultation with " + booking.StaffName,
+			Content:        fmt.Sprintf("You have a consultation with %s on %s. Cancel the booking if you cannot come.", booking.StaffName, when),
+		})
+		if err != nil {
+			logger.Printf("Failed to send office-hour reminder for booking %s: %v", booking.ID.Hex(), err)
+		}
+
+		key := booking.SlotID.Hex() + "-" + booking.Date
+		if _, ok := byOccurrence[key]; !ok {
+			keys = append(keys, key)
+		}
+		byOccurrence[key] = append(byOccurrence[key], booking)
+	}
+
+	for _, key := range keys {
+		booked := byOccurrence[key]
+		names := make([]string, 0, len(booked))
+		for _, booking := range booked {
+			names = append(names, booking.StudentName)
+		}
+		staffID := booked[0].StaffID
+		_, err := notify(repositories.Notification{
+			RecipientID:    staffID,
+			RecipientType:  "id",
+			RecipientValue: staffID.Hex(),
+			Title:          "Reminder: office hours on " + booked[0].Start.Format("02.01.2006. 15:04"),
+			Content:        fmt.Sprintf("Students coming to your office hours: %s.", strings.Join(names, ", ")),
+		})
+		if err != nil {
+			logger.Printf("Failed to send office-hour reminder to %s: %v", staffID.Hex(), err)
+		}
+	}
+}
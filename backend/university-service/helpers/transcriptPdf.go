@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	repositories "university-service/repository"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// latinDiacritics replaces the Serbian letters the core PDF fonts cannot encode.
+var latinDiacritics = strings.NewReplacer("č", "c", "ć", "c", "đ", "dj", "Č", "C", "Ć", "C", "Đ", "Dj")
+
+// RenderTranscriptPDF renders the transcript as an A4 PDF with the verification code in the footer.
+func RenderTranscriptPDF(transcript *repositories.Transcript, verifyURL string) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	text := func(s string) string { return tr(latinDiacritics.Replace(s)) }
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-20)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 4, text("Verification code: "+transcript.VerificationCode), "", 1, "C", false, 0, "")
+		pdf.CellFormat(0, 4, text("Verify at "+verifyURL), "", 1, "C", false, 0, "")
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, text("Transcript of Records"), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "", 11)
+	lines := [][2]string{{"Student", transcript.StudentName}}
+	if transcript.IndexNumber != "" {
+		lines = append(lines, [2]string{"Index number", transcript.IndexNumber})
+	}
+	lines = append(lines,
+		[2]string{"Major", transcript.MajorName},
+		[2]string{"Year of study", fmt.Sprintf("%d", transcript.Year)},
+		[2]string{"Issued", transcript.IssuedAt.Format("02.01.2006.")},
+	)
+	for _, line := range lines {
+		pdf.CellFormat(40, 7, text(line[0]+":"), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, text(line[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	widths := []float64{10, 76, 14, 14, 16, 16, 44}
+	headers := []string{"#", "Subject", "Year", "Sem.", "ESPB", "Grade", "Passed on"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range headers {
+		pdf.CellFormat(widths[i], 8, text(h), "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for i, e := range transcript.Entries {
+		row := []string{
+			fmt.Sprintf("%d", i+1),
+			e.SubjectName,
+			fmt.Sprintf("%d", e.Year),
+			fmt.Sprintf("%d", e.Semester),
+			fmt.Sprintf("%d", e.ESPB),
+			fmt.Sprintf("%d", e.Grade),
+			e.PassedAt.Format("02.01.2006."),
+		}
+		for j, cell := range row {
+			align := "C"
+			if j == 1 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[j], 7, text(cell), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, fmt.Sprintf("Passed exams: %d / %d", transcript.PassedCount, transcript.TotalSubjects), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 7, fmt.Sprintf("Total ESPB: %d", transcript.TotalESPB), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 7, fmt.Sprintf("Grade point average: %.2f", transcript.GPA), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
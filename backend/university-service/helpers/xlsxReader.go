@@ -0,0 +1,141 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as "C12".
+func xlsxColumn(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A') + 1
+	}
+	return column - 1
+}
+
+func readZipFile(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// ReadXLSX returns the cell values of the first worksheet of an .xlsx workbook, one slice per
+// row. Rows missing from the sheet are returned empty so that a row's index is its line number
+// minus one. Dates are returned as Excel serial numbers.
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not an XLSX file: %v", err)
+	}
+	var sheets []*zip.File
+	var sharedStrings *zip.File
+	for _, f := range archive.File {
+		switch {
+		case f.Name == "xl/sharedStrings.xml":
+			sharedStrings = f
+		case strings.HasPrefix(f.Name, "xl/worksheets/sheet") && strings.HasSuffix(f.Name, ".xml"):
+			sheets = append(sheets, f)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("the XLSX file has no worksheets")
+	}
+	// sheet1.xml, sheet2.xml, ... sorted by number rather than by name
+	sheetNumber := func(f *zip.File) int {
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(f.Name, "xl/worksheets/sheet"), ".xml"))
+		return n
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheetNumber(sheets[i]) < sheetNumber(sheets[j]) })
+
+	var shared struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if sharedStrings != nil {
+		if err := readZipFile(sharedStrings, &shared); err != nil {
+			return nil, fmt.Errorf("invalid XLSX shared strings: %v", err)
+		}
+	}
+	var sheet xlsxSheet
+	if err := readZipFile(sheets[0], &sheet); err != nil {
+		return nil, fmt.Errorf("invalid XLSX worksheet: %v", err)
+	}
+
+	rows := [][]string{}
+	for _, row := range sheet.Rows {
+		for row.Number > len(rows)+1 {
+			rows = append(rows, []string{})
+		}
+		values := []string{}
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = xlsxColumn(cell.Ref)
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid XLSX shared string reference in cell %s", cell.Ref)
+				}
+				values[column] = shared.Items[index].String()
+			case "inlineStr":
+				values[column] = cell.Inline.String()
+			default:
+				values[column] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// IsXLSX reports whether the content looks like an .xlsx workbook (a zip archive).
+func IsXLSX(content []byte) bool {
+	return bytes.HasPrefix(content, []byte("PK\x03\x04"))
+}
@@ -31,17 +31,43 @@ func main() {
 		logger.Fatalf("Failed to initialize repository: %v", err)
 	}
 
+	if err := repo.EnsureIndexNumberIndex(); err != nil {
+		logger.Printf("Failed to create the unique index on index numbers: %v", err)
+	}
+	if err := repo.EnsureExamRegistrationIndex(); err != nil {
+		logger.Printf("Failed to create the unique index on exam registrations: %v", err)
+	}
+
+	// Tuition payments made before the ledger existed are carried over once; reruns skip them
+	if migrated, err := repo.MigrateTuitionPayments(); err != nil {
+		logger.Printf("Failed to migrate tuition payments to the ledger: %v", err)
+	} else if migrated > 0 {
+		logger.Printf("Migrated %d tuition payments to the ledger", migrated)
+	}
+
+	if err := repo.PurgePlaintextImportPasswords(); err != nil {
+		logger.Printf("Failed to remove plaintext passwords from import jobs: %v", err)
+	}
+	if failed, err := repo.FailInterruptedImportJobs(); err != nil {
+		logger.Printf("Failed to close interrupted import jobs: %v", err)
+	} else if failed > 0 {
+		logger.Printf("Marked %d interrupted import jobs as failed", failed)
+	}
+
 	ctrl := controllers.NewControllers(repo, controllerLogger)
-	helper.StartExamStatusUpdater(repo, logger)
+	helper.StartExamStatusUpdater(repo, logger, ctrl.CreateNotificationByRecipient)
 
 	router := gin.New()
+	// Index numbers like SW-12/2025 are passed URL-encoded in paths; routing on the raw path keeps
+	// the encoded slash inside the parameter
+	router.UseRawPath = true
 	router.Use(gin.Logger())
 
 	router.Use(cors.Middleware(cors.Config{
 		Origins:         "http://localhost:4321, http://localhost:3000, http://localhost:4200",
 		Methods:         "GET, PUT, POST, DELETE, OPTIONS, PATCH",
 		RequestHeaders:  "Origin, Authorization, Content-Type, Accept, X-Requested-With",
-		ExposedHeaders:  "",
+		ExposedHeaders:  "X-Total-Count",
 		MaxAge:          50 * time.Second,
 		Credentials:     true,
 		ValidateHeaders: false,
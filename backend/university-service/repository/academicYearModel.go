@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SemesterDates is the teaching period of one semester.
+type SemesterDates struct {
+	Number    int       `bson:"number" json:"number"` // 1 = winter, 2 = summer
+	StartDate time.Time `bson:"start_date" json:"start_date"`
+	EndDate   time.Time `bson:"end_date" json:"end_date"` // inclusive
+}
+
+// CalendarDay is a single day on which the faculty does not work.
+type CalendarDay struct {
+	Date time.Time `bson:"date" json:"date"`
+	Name string    `bson:"name" json:"name"` // e.g. "Božić", "Dan fakulteta"
+}
+
+// AcademicYear is the calendar of one academic year: its bounds, the teaching dates of each
+// semester and the days the faculty is closed. Year is the calendar year it starts in, so
+// 2025 is the 2025/26 academic year, matching ExamPeriod.AcademicYear.
+type AcademicYear struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Year           int                `bson:"year" json:"year"`
+	StartDate      time.Time          `bson:"start_date" json:"start_date"`
+	EndDate        time.Time          `bson:"end_date" json:"end_date"` // inclusive
+	Semesters      []SemesterDates    `bson:"semesters" json:"semesters"`
+	Holidays       []CalendarDay      `bson:"holidays" json:"holidays"`
+	NonWorkingDays []CalendarDay      `bson:"non_working_days" json:"non_working_days"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Name returns the academic year in the usual "2025/26" form.
+func (a *AcademicYear) Name() string {
+	return formatAcademicYear(a.Year)
+}
+
+// Contains reports whether t falls within the academic year.
+func (a *AcademicYear) Contains(t time.Time) bool {
+	return withinDays(t, a.StartDate, a.EndDate)
+}
+
+// Semester returns the dates of the given semester, or nil if they are not defined.
+func (a *AcademicYear) Semester(number int) *SemesterDates {
+	for i := range a.Semesters {
+		if a.Semesters[i].Number == number {
+			return &a.Semesters[i]
+		}
+	}
+	return nil
+}
+
+// TeachingEnd is the last day of teaching in the year, or the end of the year if no semesters are defined.
+func (a *AcademicYear) TeachingEnd() time.Time {
+	end := time.Time{}
+	for _, s := range a.Semesters {
+		if s.EndDate.After(end) {
+			end = s.EndDate
+		}
+	}
+	if end.IsZero() {
+		return a.EndDate
+	}
+	return end
+}
+
+// ClosedOn returns the holiday or non-working day that falls on t, if any.
+func (a *AcademicYear) ClosedOn(t time.Time) (*CalendarDay, bool) {
+	for _, days := range [][]CalendarDay{a.Holidays, a.NonWorkingDays} {
+		for i := range days {
+			if sameDay(days[i].Date, t) {
+				return &days[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// AcademicYearRequest is the payload for creating or replacing an academic year.
+type AcademicYearRequest struct {
+	Year           int             `json:"year" binding:"required"`
+	StartDate      time.Time       `json:"start_date" binding:"required"`
+	EndDate        time.Time       `json:"end_date" binding:"required"`
+	Semesters      []SemesterDates `json:"semesters" binding:"required"`
+	Holidays       []CalendarDay   `json:"holidays"`
+	NonWorkingDays []CalendarDay   `json:"non_working_days"`
+}
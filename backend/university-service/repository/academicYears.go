@@ -0,0 +1,319 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAcademicYearNotFound    = errors.New("academic year not found")
+	ErrAcademicYearExists      = errors.New("academic year already exists")
+	ErrInvalidAcademicYear     = errors.New("invalid academic year")
+	ErrOutsideAcademicCalendar = errors.New("outside the academic calendar")
+)
+
+func formatAcademicYear(year int) string {
+	return fmt.Sprintf("%d/%02d", year, (year+1)%100)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func sameDay(a, b time.Time) bool {
+	b = b.In(a.Location())
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// withinDays reports whether t falls on or between the days of start and end.
+func withinDays(t, start, end time.Time) bool {
+	day := startOfDay(t.In(start.Location()))
+	return !day.Before(startOfDay(start)) && !day.After(startOfDay(end.In(start.Location())))
+}
+
+func validateAcademicYear(a *AcademicYear) error {
+	if a.Year < 2000 {
+		return fmt.Errorf("%w: year must be the calendar year the academic year starts in", ErrInvalidAcademicYear)
+	}
+	if !a.EndDate.After(a.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidAcademicYear)
+	}
+	if len(a.Semesters) == 0 {
+		return fmt.Errorf("%w: at least one semester is required", ErrInvalidAcademicYear)
+	}
+	sort.Slice(a.Semesters, func(i, j int) bool { return a.Semesters[i].Number < a.Semesters[j].Number })
+	for i, s := range a.Semesters {
+		if s.Number != 1 && s.Number != 2 {
+			return fmt.Errorf("%w: semester must be 1 or 2", ErrInvalidAcademicYear)
+		}
+		if s.EndDate.Before(s.StartDate) {
+			return fmt.Errorf("%w: semester %d ends before it starts", ErrInvalidAcademicYear, s.Number)
+		}
+		if !a.Contains(s.StartDate) || !a.Contains(s.EndDate) {
+			return fmt.Errorf("%w: semester %d is outside the academic year", ErrInvalidAcademicYear, s.Number)
+		}
+		if i > 0 {
+			prev := a.Semesters[i-1]
+			if prev.Number == s.Number {
+				return fmt.Errorf("%w: semester %d is defined twice", ErrInvalidAcademicYear, s.Number)
+			}
+			if !s.StartDate.After(prev.EndDate) {
+				return fmt.Errorf("%w: semester %d must start after semester %d ends", ErrInvalidAcademicYear, s.Number, prev.Number)
+			}
+		}
+	}
+	for _, days := range [][]CalendarDay{a.Holidays, a.NonWorkingDays} {
+		for _, d := range days {
+			if !a.Contains(d.Date) {
+				return fmt.Errorf("%w: %s (%s) is outside the academic year", ErrInvalidAcademicYear, d.Name, d.Date.Format("02.01.2006."))
+			}
+		}
+	}
+	if a.Holidays == nil {
+		a.Holidays = []CalendarDay{}
+	}
+	if a.NonWorkingDays == nil {
+		a.NonWorkingDays = []CalendarDay{}
+	}
+	return nil
+}
+
+func (r *Repository) CreateAcademicYear(a *AcademicYear) error {
+	if err := validateAcademicYear(a); err != nil {
+		return err
+	}
+	existing, err := r.GetAcademicYearByYear(a.Year)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrAcademicYearExists, a.Name())
+	}
+	a.ID = primitive.NewObjectID()
+	a.CreatedAt = time.Now()
+	a.UpdatedAt = a.CreatedAt
+	_, err = r.getCollection("academic_years").InsertOne(context.TODO(), a)
+	return err
+}
+
+func (r *Repository) GetAcademicYears() ([]AcademicYear, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "year", Value: 1}})
+	cursor, err := r.getCollection("academic_years").Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var years []AcademicYear
+	err = cursor.All(context.TODO(), &years)
+	return years, err
+}
+
+func (r *Repository) findAcademicYear(filter bson.M) (*AcademicYear, error) {
+	var year AcademicYear
+	err := r.getCollection("academic_years").FindOne(context.TODO(), filter).Decode(&year)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &year, nil
+}
+
+func (r *Repository) GetAcademicYearByID(id primitive.ObjectID) (*AcademicYear, error) {
+	return r.findAcademicYear(bson.M{"_id": id})
+}
+
+// GetAcademicYearByYear returns the academic year starting in the given calendar year, or nil.
+func (r *Repository) GetAcademicYearByYear(year int) (*AcademicYear, error) {
+	return r.findAcademicYear(bson.M{"year": year})
+}
+
+// GetAcademicYearForDate returns the academic year containing t, or nil if none is defined.
+func (r *Repository) GetAcademicYearForDate(t time.Time) (*AcademicYear, error) {
+	years, err := r.GetAcademicYears()
+	if err != nil {
+		return nil, err
+	}
+	for i := range years {
+		if years[i].Contains(t) {
+			return &years[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *Repository) UpdateAcademicYear(a *AcademicYear) error {
+	if err := validateAcademicYear(a); err != nil {
+		return err
+	}
+	existing, err := r.GetAcademicYearByYear(a.Year)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != a.ID {
+		return fmt.Errorf("%w: %s", ErrAcademicYearExists, a.Name())
+	}
+	a.UpdatedAt = time.Now()
+	result, err := r.getCollection("academic_years").UpdateOne(context.TODO(), bson.M{"_id": a.ID}, bson.M{"$set": bson.M{
+		"year":             a.Year,
+		"start_date":       a.StartDate,
+		"end_date":         a.EndDate,
+		"semesters":        a.Semesters,
+		"holidays":         a.Holidays,
+		"non_working_days": a.NonWorkingDays,
+		"updated_at":       a.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAcademicYearNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteAcademicYear(id primitive.ObjectID) error {
+	result, err := r.getCollection("academic_years").DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrAcademicYearNotFound
+	}
+	return nil
+}
+
+// CheckExamPeriodCalendar checks an exam period against the academic calendar. A period outside
+// its academic year is rejected with ErrOutsideAcademicCalendar; overlapping teaching weeks or
+// starting/ending on a day the faculty is closed only produces warnings. If the period has no
+// AcademicYear it is filled in from the calendar. Without a calendar nothing is checked.
+func (r *Repository) CheckExamPeriodCalendar(period *ExamPeriod) ([]string, error) {
+	var year *AcademicYear
+	var err error
+	if period.AcademicYear != 0 {
+		year, err = r.GetAcademicYearByYear(period.AcademicYear)
+	} else {
+		year, err = r.GetAcademicYearForDate(period.StartDate)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if year == nil {
+		if period.AcademicYear != 0 {
+			return []string{fmt.Sprintf("No calendar is defined for academic year %s", formatAcademicYear(period.AcademicYear))}, nil
+		}
+		return nil, nil
+	}
+
+	if !year.Contains(period.StartDate) || !year.Contains(period.EndDate) {
+		return nil, fmt.Errorf("%w: academic year %s runs from %s to %s", ErrOutsideAcademicCalendar, year.Name(),
+			year.StartDate.Format("02.01.2006."), year.EndDate.Format("02.01.2006."))
+	}
+	period.AcademicYear = year.Year
+
+	warnings := []string{}
+	for _, s := range year.Semesters {
+		if !period.StartDate.After(s.EndDate) && !period.EndDate.Before(s.StartDate) {
+			warnings = append(warnings, fmt.Sprintf("The exam period overlaps teaching in semester %d (%s - %s)",
+				s.Number, s.StartDate.Format("02.01.2006."), s.EndDate.Format("02.01.2006.")))
+		}
+	}
+	if period.Semester != 0 {
+		if s := year.Semester(period.Semester); s != nil && period.StartDate.Before(s.StartDate) {
+			warnings = append(warnings, fmt.Sprintf("The exam period starts before semester %d begins", period.Semester))
+		}
+	}
+	for _, t := range []time.Time{period.StartDate, period.EndDate} {
+		if day, closed := year.ClosedOn(t); closed {
+			warnings = append(warnings, fmt.Sprintf("%s falls on a day the faculty is closed: %s", t.Format("02.01.2006."), day.Name))
+		}
+	}
+	return warnings, nil
+}
+
+// CheckYearAdvanceWindow rejects advancing students while teaching in the current academic year
+// is still under way. Without a calendar for the current date the check passes.
+func (r *Repository) CheckYearAdvanceWindow(now time.Time) error {
+	year, err := r.GetAcademicYearForDate(now)
+	if err != nil {
+		return err
+	}
+	if year == nil {
+		return nil
+	}
+	if teachingEnd := year.TeachingEnd(); !now.After(startOfDay(teachingEnd).Add(24 * time.Hour)) {
+		return fmt.Errorf("%w: students can enroll in the next year once teaching in %s ends on %s",
+			ErrOutsideAcademicCalendar, year.Name(), teachingEnd.Format("02.01.2006."))
+	}
+	return nil
+}
+
+// calendarConflict describes why an exam on t clashes with the calendar, or returns "".
+func calendarConflict(years []AcademicYear, t time.Time) string {
+	for i := range years {
+		if !years[i].Contains(t) {
+			continue
+		}
+		if day, closed := years[i].ClosedOn(t); closed {
+			return "The exam falls on a day the faculty is closed: " + day.Name
+		}
+		return ""
+	}
+	return "No academic year is defined for the exam date"
+}
+
+// FlagExamSessionCalendarConflicts marks upcoming exam sessions that fall on a holiday, a
+// non-working day or outside every defined academic year, and clears the mark once fixed.
+// Nothing is flagged until at least one academic year is defined.
+func (r *Repository) FlagExamSessionCalendarConflicts(now time.Time) error {
+	years, err := r.GetAcademicYears()
+	if err != nil {
+		return err
+	}
+	if len(years) == 0 {
+		return nil
+	}
+
+	collection := r.getCollection("exam_sessions")
+	cursor, err := collection.Find(context.TODO(), bson.M{
+		"status":    Scheduled,
+		"exam_date": bson.M{"$gte": now},
+	})
+	if err != nil {
+		return err
+	}
+	var sessions []ExamSession
+	if err := cursor.All(context.TODO(), &sessions); err != nil {
+		return err
+	}
+
+	flagged := 0
+	for _, session := range sessions {
+		conflict := calendarConflict(years, session.ExamDate)
+		if conflict == session.CalendarConflict {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"calendar_conflict": conflict}}
+		if conflict == "" {
+			update = bson.M{"$unset": bson.M{"calendar_conflict": ""}}
+		} else {
+			flagged++
+		}
+		if _, err := collection.UpdateOne(context.TODO(), bson.M{"_id": session.ID}, update); err != nil {
+			return err
+		}
+	}
+	if flagged > 0 {
+		r.logger.Printf("Flagged %d exam sessions that clash with the academic calendar", flagged)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// BulkGradeRow is one line of a bulk grade entry. The student is identified by index number or
+// by student ID; if both are given they must refer to the same student.
+type BulkGradeRow struct {
+	Row         int      `json:"row,omitempty"` // line in the uploaded file; the position in the list if omitted
+	StudentID   string   `json:"student_id,omitempty"`
+	IndexNumber string   `json:"index_number,omitempty"`
+	Grade       int      `json:"grade"`
+	ExamPoints  *float64 `json:"exam_points,omitempty"` // final exam points; the grade is then computed from the grading scheme
+	Comments    string   `json:"comments,omitempty"`
+}
+
+// Key returns how the row identifies the student, for error reports.
+func (row BulkGradeRow) Key() string {
+	if row.IndexNumber != "" {
+		return row.IndexNumber
+	}
+	return row.StudentID
+}
+
+type BulkGradeRequest struct {
+	Grades []BulkGradeRow `json:"grades" binding:"required"`
+}
+
+// BulkGradeRowError explains why a row was not applied.
+type BulkGradeRowError struct {
+	Row   int    `json:"row"`
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error"`
+}
+
+// BulkGradeResult reports which rows of a bulk grade entry were applied and which were rejected.
+type BulkGradeResult struct {
+	ExamSessionID primitive.ObjectID  `json:"exam_session_id"`
+	Received      int                 `json:"received"`
+	Applied       int                 `json:"applied"`
+	Grades        []ExamGrade         `json:"grades"`
+	Errors        []BulkGradeRowError `json:"errors"`
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateExamGradesBulk validates the rows against the session's registrations and inserts the
+// grades of all valid rows at once. Invalid rows are reported and skipped, so one bad line does
+// not hold back the rest of the exam. Passed subjects are marked on the students' records; GPA
+// is left to the caller so it can be recomputed once per student.
+func (r *Repository) CreateExamGradesBulk(session *ExamSession, rows []BulkGradeRow) (*BulkGradeResult, error) {
+	result := &BulkGradeResult{
+		ExamSessionID: session.ID,
+		Received:      len(rows),
+		Grades:        []ExamGrade{},
+		Errors:        []BulkGradeRowError{},
+	}
+	if session.ExamDate.After(time.Now()) {
+		return nil, ErrExamNotHeld
+	}
+
+	loaded, err := r.loadExamRegistrations(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	seen := make(map[primitive.ObjectID]int)
+	documents := []interface{}{}
+	for i, row := range rows {
+		if row.Row == 0 {
+			row.Row = i + 1
+		}
+		reject := func(message string) {
+			result.Errors = append(result.Errors, BulkGradeRowError{Row: row.Row, Key: row.Key(), Error: message})
+		}
+
+		registration, err := loaded.find(row.StudentID, row.IndexNumber)
+		if err != nil {
+			reject(err.Error())
+			continue
+		}
+		if row.ExamPoints == nil && (row.Grade < 5 || row.Grade > 10) {
+			reject("grade must be between 5 and 10")
+			continue
+		}
+		if registration.Status == RegistrationAbsent {
+			reject("the student was marked absent")
+			continue
+		}
+		if _, graded := loaded.grades[registration.ID]; graded {
+			reject(ErrRegistrationGraded.Error())
+			continue
+		}
+		if first, dup := seen[registration.ID]; dup {
+			reject("the student is already graded in row " + strconv.Itoa(first))
+			continue
+		}
+
+		grade := ExamGrade{
+			ID:                 primitive.NewObjectID(),
+			Student:            loaded.student(registration),
+			ExamRegistrationId: registration.ID,
+			ExamSessionId:      session.ID,
+			SubjectId:          session.Subject.ID,
+			Grade:              row.Grade,
+			GradedAt:           now,
+			GradedBy:           session.Professor,
+			Comments:           row.Comments,
+		}
+		if row.ExamPoints != nil {
+			points, err := r.GradeFromExamPoints(session.Subject.ID, grade.Student.ID, *row.ExamPoints, session.Professor.ID)
+			if err != nil {
+				reject(err.Error())
+				continue
+			}
+			grade.Grade = points.Grade
+			grade.Points = points.TotalPoints
+		}
+		grade.Passed = grade.Grade >= 6
+		seen[registration.ID] = row.Row
+		result.Grades = append(result.Grades, grade)
+		documents = append(documents, grade)
+	}
+
+	if len(documents) == 0 {
+		return result, nil
+	}
+	if _, err := r.getCollection("exam_grades").InsertMany(context.TODO(), documents); err != nil {
+		return nil, err
+	}
+	result.Applied = len(documents)
+
+	for i := range result.Grades {
+		grade := &result.Grades[i]
+		if !grade.Passed {
+			continue
+		}
+		if err := r.recordSubjectResult(grade); err != nil {
+			r.logger.Printf("Failed to mark subject %s passed for student %s: %v", grade.SubjectId.Hex(), grade.Student.ID.Hex(), err)
+		}
+	}
+	r.logger.Printf("Bulk graded %d of %d rows for exam session %s", result.Applied, result.Received, session.ID.Hex())
+	return result, nil
+}
+
+// recordSubjectResult updates the subject on the student's record to match the grade: a passing
+// grade marks the subject passed, a failing one clears an earlier pass.
+func (r *Repository) recordSubjectResult(grade *ExamGrade) error {
+	update := bson.M{"$set": bson.M{"subjects.$.has_passed": true, "subjects.$.grade": *grade}}
+	if !grade.Passed {
+		update = bson.M{
+			"$set":   bson.M{"subjects.$.has_passed": false},
+			"$unset": bson.M{"subjects.$.grade": ""},
+		}
+	}
+	_, err := r.getCollection("student").UpdateOne(context.TODO(),
+		bson.M{"_id": grade.Student.ID, "subjects._id": grade.SubjectId},
+		update,
+	)
+	return err
+}
@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrEntityNotFound = errors.New("entity not found")
+
+// cascadeRule describes one kind of document that references an entity.
+type cascadeRule struct {
+	collection  string
+	description string
+	policy      CascadePolicy
+	filter      func(id primitive.ObjectID) bson.M
+	nullify     func(id primitive.ObjectID) bson.M // update applied for CascadeNullify
+	childEntity string                             // for CascadeDelete: apply this entity's policy to each deleted document
+}
+
+func refersTo(field string) func(id primitive.ObjectID) bson.M {
+	return func(id primitive.ObjectID) bson.M { return bson.M{field: id} }
+}
+
+func pullID(field string) func(id primitive.ObjectID) bson.M {
+	return func(id primitive.ObjectID) bson.M { return bson.M{"$pull": bson.M{field: id}} }
+}
+
+func pullSubject(field string) func(id primitive.ObjectID) bson.M {
+	return func(id primitive.ObjectID) bson.M { return bson.M{"$pull": bson.M{field: bson.M{"_id": id}}} }
+}
+
+func unsetField(field string) func(id primitive.ObjectID) bson.M {
+	return func(id primitive.ObjectID) bson.M { return bson.M{"$unset": bson.M{field: ""}} }
+}
+
+// cascadePolicies lists, per collection, what happens to referencing documents on delete.
+// Keys are collection names, matching the entity types accepted by DeleteDynamic.
+var cascadePolicies = map[string][]cascadeRule{
+	"majors": {
+		{collection: "student", description: "students enrolled in the major", policy: CascadeRestrict, filter: refersTo("major_id")},
+		{collection: "subjects", description: "subjects of the major", policy: CascadeDelete, filter: refersTo("major_id"), childEntity: "subjects"},
+		{collection: "exam_periods", description: "exam periods scoped to the major", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "exam_period_templates", description: "exam period templates scoped to the major", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "eligibility_rules", description: "exam eligibility rule settings", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "exam_scheduling_policies", description: "exam scheduling policy", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "grade_appeal_policies", description: "grade appeal deadlines", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "curriculum_versions", description: "published curriculum versions", policy: CascadeDelete, filter: refersTo("major_id")},
+		{collection: "department", description: "departments listing the major", policy: CascadeNullify, filter: refersTo("major_ids"), nullify: pullID("major_ids")},
+	},
+	"department": {
+		{collection: "majors", description: "majors belonging to the department", policy: CascadeNullify, filter: refersTo("department_id"), nullify: unsetField("department_id")},
+	},
+	"subjects": {
+		{collection: "exam_grades", description: "exam grades recorded for the subject", policy: CascadeRestrict, filter: refersTo("subject_id")},
+		{collection: "grading_schemes", description: "grading scheme of the subject", policy: CascadeDelete, filter: refersTo("subject_id")},
+		{collection: "component_points", description: "points entered for the subject's components", policy: CascadeDelete, filter: refersTo("subject_id")},
+		{collection: "exam_sessions", description: "exam sessions of the subject", policy: CascadeDelete, filter: refersTo("subject._id"), childEntity: "exam_sessions"},
+		{collection: "timetable_slots", description: "lecture timetable slots of the subject", policy: CascadeDelete, filter: refersTo("subject_id")},
+		{collection: "majors", description: "majors listing the subject", policy: CascadeNullify, filter: refersTo("subjects._id"), nullify: pullSubject("subjects")},
+		{collection: "majors", description: "elective groups offering the subject", policy: CascadeNullify, filter: refersTo("elective_groups.subject_ids"), nullify: pullID("elective_groups.$[].subject_ids")},
+		{collection: "curriculum_versions", description: "published curricula listing the subject", policy: CascadeNullify, filter: refersTo("subjects._id"), nullify: pullSubject("subjects")},
+		{collection: "curriculum_versions", description: "published elective groups offering the subject", policy: CascadeNullify, filter: refersTo("elective_groups.subject_ids"), nullify: pullID("elective_groups.$[].subject_ids")},
+		{collection: "subjects", description: "subjects requiring it as a prerequisite", policy: CascadeNullify, filter: refersTo("prerequisite_ids"), nullify: pullID("prerequisite_ids")},
+		{collection: "student", description: "students tracking the subject", policy: CascadeNullify, filter: refersTo("subjects._id"), nullify: pullSubject("subjects")},
+		{collection: "professor", description: "professors teaching the subject", policy: CascadeNullify, filter: refersTo("subjects._id"), nullify: pullSubject("subjects")},
+		{collection: "assistant", description: "assistants on the subject", policy: CascadeNullify, filter: refersTo("subjects._id"), nullify: pullSubject("subjects")},
+	},
+	"professor": {
+		{collection: "exam_sessions", description: "exam sessions not yet completed", policy: CascadeRestrict, filter: func(id primitive.ObjectID) bson.M {
+			return bson.M{"professor._id": id, "status": bson.M{"$ne": Completed}}
+		}},
+		{collection: "department", description: "departments headed by the professor", policy: CascadeRestrict, filter: refersTo("head")},
+		{collection: "graduation_requests", description: "theses in progress mentored or examined by the professor", policy: CascadeRestrict, filter: func(id primitive.ObjectID) bson.M {
+			return bson.M{
+				"$or":   []bson.M{{"thesis.mentor._id": id}, {"thesis.committee._id": id}},
+				"stage": bson.M{"$nin": []GraduationStage{GraduationCompleted, GraduationRejected}},
+			}
+		}},
+		{collection: "grade_appeals", description: "grade appeals awaiting the professor's decision", policy: CascadeRestrict, filter: func(id primitive.ObjectID) bson.M {
+			return bson.M{"$or": []bson.M{
+				{"stage": AppealProfessorReview, "professor_id": id},
+				{"stage": AppealHeadReview, "department_head_id": id},
+			}}
+		}},
+		{collection: "timetable_slots", description: "lecture timetable slots taught by the professor", policy: CascadeRestrict, filter: refersTo("teacher_id")},
+		{collection: "office_hour_bookings", description: "consultations booked with the professor", policy: CascadeDelete, filter: refersTo("staff_id")},
+		{collection: "office_hour_occurrences", description: "places taken in the professor's office hours", policy: CascadeDelete, filter: refersTo("staff_id")},
+		{collection: "office_hour_slots", description: "office hours held by the professor", policy: CascadeDelete, filter: refersTo("staff_id")},
+		{collection: "subjects", description: "subjects taught by the professor", policy: CascadeNullify, filter: refersTo("professor_ids"), nullify: pullID("professor_ids")},
+		{collection: "department", description: "department staff lists", policy: CascadeNullify, filter: refersTo("staff"), nullify: pullID("staff")},
+		{collection: "assistant", description: "assistants working with the professor", policy: CascadeNullify, filter: refersTo("professor._id"), nullify: unsetField("professor")},
+	},
+	"assistant": {
+		{collection: "office_hour_bookings", description: "consultations booked with the assistant", policy: CascadeDelete, filter: refersTo("staff_id")},
+		{collection: "office_hour_occurrences", description: "places taken in the assistant's office hours", policy: CascadeDelete, filter: refersTo("staff_id")},
+		{collection: "office_hour_slots", description: "office hours held by the assistant", policy: CascadeDelete, filter: refersTo("staff_id")},
+	},
+	"student": {
+		// Academic and financial records are kept for as long as the university must keep them;
+		// a student who has any cannot be deleted.
+		{collection: "diplomas", description: "issued diplomas", policy: CascadeRestrict, filter: refersTo("student_id")},
+		{collection: "exam_grades", description: "exam grades", policy: CascadeRestrict, filter: refersTo("student._id")},
+		{collection: "enrollments", description: "enrollment history", policy: CascadeRestrict, filter: refersTo("student_id")},
+		{collection: "tuitionPayments", description: "tuition payments", policy: CascadeRestrict, filter: refersTo("student_id")},
+		{collection: "ledger", description: "ledger entries", policy: CascadeRestrict, filter: refersTo("student_id")},
+		{collection: "transcripts", description: "issued transcripts", policy: CascadeRestrict, filter: refersTo("student_id")},
+		{collection: "exam_registrations", description: "exam registrations", policy: CascadeDelete, filter: refersTo("student._id")},
+		{collection: "exam_waitlist", description: "exam waitlist entries", policy: CascadeDelete, filter: refersTo("student._id")},
+		{collection: "grade_appeals", description: "grade appeals", policy: CascadeDelete, filter: refersTo("student_id")},
+		{collection: "office_hour_bookings", description: "office-hour bookings", policy: CascadeDelete, filter: refersTo("student_id")},
+		{collection: "office_hour_occurrences", description: "places taken in office hours", policy: CascadeNullify, filter: refersTo("students"), nullify: pullID("students")},
+		{collection: "component_points", description: "points in subject components", policy: CascadeDelete, filter: refersTo("student_id")},
+		{collection: "graduation_requests", description: "graduation requests", policy: CascadeDelete, filter: refersTo("student_id")},
+		{collection: "internship_applications", description: "internship applications", policy: CascadeDelete, filter: refersTo("applicant_id")},
+		{collection: "notifications", description: "notifications", policy: CascadeDelete, filter: refersTo("recipient_id")},
+	},
+	"exam_sessions": {
+		{collection: "exam_grades", description: "grades recorded for the exam", policy: CascadeRestrict, filter: refersTo("exam_session_id")},
+		{collection: "exam_registrations", description: "registrations for the exam", policy: CascadeDelete, filter: refersTo("exam_session_id")},
+		{collection: "room_bookings", description: "room bookings for the exam", policy: CascadeDelete, filter: refersTo("exam_session_id")},
+		{collection: "exam_waitlist", description: "students waiting for a seat", policy: CascadeDelete, filter: refersTo("exam_session_id")},
+	},
+}
+
+// PreviewDelete is a dry run of DeleteWithCascade: it reports every document the delete would
+// touch and whether a restrict policy blocks it, without changing anything.
+func (r *Repository) PreviewDelete(entityType string, id primitive.ObjectID) (*DeleteImpact, error) {
+	if err := r.ensureEntityExists(entityType, id); err != nil {
+		return nil, err
+	}
+	impact := &DeleteImpact{
+		EntityType: entityType,
+		EntityID:   id.Hex(),
+		BlockedBy:  []CascadeEffect{},
+		Effects:    []CascadeEffect{},
+	}
+	if err := r.collectDeleteImpact(entityType, id, impact); err != nil {
+		return nil, err
+	}
+	impact.Allowed = len(impact.BlockedBy) == 0
+	return impact, nil
+}
+
+// DeleteWithCascade deletes an entity and applies its cascade policy to referencing documents.
+// Nothing is changed when a restrict policy applies; a *DeleteRestrictedError is returned instead.
+//
+// The restrict check (PreviewDelete) and the deletes (applyCascade) are separate passes and do
+// not run in a transaction, since the service does not require a replica set. A referencing
+// document written between the two passes is not checked, and a failed write leaves the earlier
+// ones applied; re-running the delete finishes it.
+func (r *Repository) DeleteWithCascade(entityType string, id primitive.ObjectID) (*DeleteImpact, error) {
+	impact, err := r.PreviewDelete(entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	if !impact.Allowed {
+		return impact, &DeleteRestrictedError{Impact: impact}
+	}
+	if err := r.applyCascade(entityType, id); err != nil {
+		return impact, err
+	}
+	r.logger.Printf("Deleted %s %s with cascade: %+v", entityType, id.Hex(), impact.Effects)
+	return impact, nil
+}
+
+func (r *Repository) ensureEntityExists(entityType string, id primitive.ObjectID) error {
+	if _, ok := cascadePolicies[entityType]; !ok {
+		return fmt.Errorf("invalid entity type: %s", entityType)
+	}
+	count, err := r.getCollection(entityType).CountDocuments(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s %s", ErrEntityNotFound, entityType, id.Hex())
+	}
+	return nil
+}
+
+func (r *Repository) collectDeleteImpact(entityType string, id primitive.ObjectID, impact *DeleteImpact) error {
+	for _, rule := range cascadePolicies[entityType] {
+		collection := r.getCollection(rule.collection)
+		filter := rule.filter(id)
+		count, err := collection.CountDocuments(context.TODO(), filter)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			continue
+		}
+		effect := CascadeEffect{Collection: rule.collection, Policy: rule.policy, Description: rule.description, Count: count}
+		if rule.policy == CascadeRestrict {
+			impact.BlockedBy = append(impact.BlockedBy, effect)
+			continue
+		}
+		impact.Effects = append(impact.Effects, effect)
+
+		if rule.policy == CascadeDelete && rule.childEntity != "" {
+			childIDs, err := r.findIDs(rule.collection, filter)
+			if err != nil {
+				return err
+			}
+			for _, childID := range childIDs {
+				if err := r.collectDeleteImpact(rule.childEntity, childID, impact); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) applyCascade(entityType string, id primitive.ObjectID) error {
+	for _, rule := range cascadePolicies[entityType] {
+		collection := r.getCollection(rule.collection)
+		filter := rule.filter(id)
+		switch rule.policy {
+		case CascadeDelete:
+			if rule.childEntity != "" {
+				childIDs, err := r.findIDs(rule.collection, filter)
+				if err != nil {
+					return err
+				}
+				for _, childID := range childIDs {
+					if err := r.applyCascade(rule.childEntity, childID); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if _, err := collection.DeleteMany(context.TODO(), filter); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", rule.collection, err)
+			}
+		case CascadeNullify:
+			if _, err := collection.UpdateMany(context.TODO(), filter, rule.nullify(id)); err != nil {
+				return fmt.Errorf("failed to update %s: %w", rule.collection, err)
+			}
+		}
+	}
+	_, err := r.getCollection(entityType).DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
+func (r *Repository) findIDs(collectionName string, filter bson.M) ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.getCollection(collectionName).Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.TODO(), &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
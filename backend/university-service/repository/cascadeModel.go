@@ -0,0 +1,42 @@
+package repositories
+
+import "fmt"
+
+// CascadePolicy decides what happens to documents that reference an entity being deleted.
+type CascadePolicy string
+
+const (
+	CascadeRestrict CascadePolicy = "restrict" // referencing documents block the delete
+	CascadeDelete   CascadePolicy = "cascade"  // referencing documents are deleted too
+	CascadeNullify  CascadePolicy = "nullify"  // the reference is removed from referencing documents
+)
+
+// CascadeEffect reports how many documents of a collection a delete touches and how.
+type CascadeEffect struct {
+	Collection  string        `json:"collection"`
+	Policy      CascadePolicy `json:"policy"`
+	Description string        `json:"description"`
+	Count       int64         `json:"count"`
+}
+
+// DeleteImpact is the dry-run result of deleting an entity.
+type DeleteImpact struct {
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Allowed    bool            `json:"allowed"`
+	BlockedBy  []CascadeEffect `json:"blocked_by"`
+	Effects    []CascadeEffect `json:"effects"`
+}
+
+// DeleteRestrictedError is returned when a restrict policy blocks a delete.
+type DeleteRestrictedError struct {
+	Impact *DeleteImpact
+}
+
+func (e *DeleteRestrictedError) Error() string {
+	if len(e.Impact.BlockedBy) == 0 {
+		return "delete is restricted"
+	}
+	b := e.Impact.BlockedBy[0]
+	return fmt.Sprintf("cannot delete %s: %d %s (%s)", e.Impact.EntityType, b.Count, b.Collection, b.Description)
+}
@@ -7,6 +7,7 @@ type ExamStatus string
 const (
 	StudentType        UserType = "STUDENT"
 	ProfessorType      UserType = "PROFESSOR"
+	AssistantType      UserType = "ASSISTANT"
 	AdministratorType  UserType = "ADMIN"
 	StudentServiceType UserType = "STUDENTSKA_SLUZBA"
 )
@@ -25,3 +26,10 @@ const (
 	Completed   ExamStatus = "Completed"
 	PendingExam ExamStatus = "Pending"
 )
+
+// Statuses of an ExamRegistration.
+const (
+	RegistrationRegistered ExamStatus = "registered"
+	RegistrationAttended   ExamStatus = "attended"
+	RegistrationAbsent     ExamStatus = "missed"
+)
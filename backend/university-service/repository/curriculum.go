@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidElectiveGroups = errors.New("invalid elective groups")
+	ErrInvalidElectiveChoice = errors.New("invalid elective choice")
+	ErrInvalidPrerequisites  = errors.New("invalid prerequisites")
+)
+
+// ElectiveGroupOf returns the elective group the subject belongs to, or nil for a mandatory subject.
+func (m *Major) ElectiveGroupOf(subjectID primitive.ObjectID) *ElectiveGroup {
+	for i := range m.ElectiveGroups {
+		if m.ElectiveGroups[i].Contains(subjectID) {
+			return &m.ElectiveGroups[i]
+		}
+	}
+	return nil
+}
+
+// CurriculumFor returns the major's mandatory subjects and the electives the student has already
+// chosen, i.e. those among the student's current subjects.
+func (m *Major) CurriculumFor(current []Subject) []Subject {
+	chosen := subjectIDSet(current)
+	curriculum := make([]Subject, 0, len(m.Subjects))
+	for _, subject := range m.Subjects {
+		if m.ElectiveGroupOf(subject.ID) == nil || chosen[subject.ID] {
+			curriculum = append(curriculum, subject)
+		}
+	}
+	return curriculum
+}
+
+// RequiredSubjectCount is how many subjects a student of the major takes: every mandatory subject
+// and the required number from each elective group.
+func (m *Major) RequiredSubjectCount() int {
+	count := len(m.Subjects)
+	for _, group := range m.ElectiveGroups {
+		count += group.Choose - len(group.SubjectIDs)
+	}
+	return count
+}
+
+// subjectIDSet returns the IDs of the subjects, used as the electives a student has chosen.
+func subjectIDSet(subjects []Subject) map[primitive.ObjectID]bool {
+	ids := make(map[primitive.ObjectID]bool, len(subjects))
+	for _, s := range subjects {
+		ids[s.ID] = true
+	}
+	return ids
+}
+
+func validateElectiveGroups(major *Major, groups []ElectiveGroup) error {
+	curriculum := make(map[primitive.ObjectID]Subject, len(major.Subjects))
+	for _, s := range major.Subjects {
+		curriculum[s.ID] = s
+	}
+	grouped := map[primitive.ObjectID]string{}
+	for i := range groups {
+		group := &groups[i]
+		if group.Name == "" {
+			return fmt.Errorf("%w: group %d has no name", ErrInvalidElectiveGroups, i+1)
+		}
+		if group.ID.IsZero() {
+			group.ID = primitive.NewObjectID()
+		}
+		if group.Choose < 1 || group.Choose > len(group.SubjectIDs) {
+			return fmt.Errorf("%w: %s must have choose between 1 and its %d subjects", ErrInvalidElectiveGroups, group.Name, len(group.SubjectIDs))
+		}
+		for _, id := range group.SubjectIDs {
+			subject, ok := curriculum[id]
+			if !ok {
+				return fmt.Errorf("%w: subject %s of %s is not in the curriculum of %s", ErrInvalidElectiveGroups, id.Hex(), group.Name, major.Name)
+			}
+			if other, taken := grouped[id]; taken {
+				return fmt.Errorf("%w: %s is in both %s and %s", ErrInvalidElectiveGroups, subject.Name, other, group.Name)
+			}
+			grouped[id] = group.Name
+			if group.Year == 0 {
+				group.Year = subject.Year
+			}
+			if subject.Year != group.Year {
+				return fmt.Errorf("%w: %s is a year %d subject, %s is for year %d", ErrInvalidElectiveGroups, subject.Name, subject.Year, group.Name, group.Year)
+			}
+		}
+	}
+	return nil
+}
+
+// SetElectiveGroups replaces the major's elective groups. Students keep the electives they
+// already have; the groups apply to choices made from now on.
+func (r *Repository) SetElectiveGroups(majorID primitive.ObjectID, groups []ElectiveGroup) (*Major, error) {
+	major, err := r.GetMajorByID(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+	if err := validateElectiveGroups(major, groups); err != nil {
+		return nil, err
+	}
+	_, err = r.getCollection("majors").UpdateOne(context.TODO(),
+		bson.M{"_id": majorID},
+		bson.M{"$set": bson.M{"elective_groups": groups}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	major.ElectiveGroups = groups
+	return major, nil
+}
+
+// chooseElectives applies a student's elective picks for one year of study to their subjects.
+// Every group of that year needs exactly Choose picks, unless nothing is picked for a group the
+// student has already chosen from (e.g. when repeating a year). Unpassed electives the student
+// no longer picks are dropped; passed ones are kept.
+func chooseElectives(subjects []Subject, major *Major, year int, picks []primitive.ObjectID) ([]Subject, error) {
+	picked := make(map[primitive.ObjectID]bool, len(picks))
+	for _, id := range picks {
+		group := major.ElectiveGroupOf(id)
+		if group == nil || group.Year != year {
+			return nil, fmt.Errorf("%w: subject %s is not an elective of year %d", ErrInvalidElectiveChoice, id.Hex(), year)
+		}
+		picked[id] = true
+	}
+	has := subjectIDSet(subjects)
+
+	for _, group := range major.ElectiveGroups {
+		if group.Year != year {
+			continue
+		}
+		pickedInGroup, hasInGroup := 0, 0
+		for _, id := range group.SubjectIDs {
+			if picked[id] {
+				pickedInGroup++
+			}
+			if has[id] {
+				hasInGroup++
+			}
+		}
+		if pickedInGroup == 0 && hasInGroup >= group.Choose {
+			continue
+		}
+		if pickedInGroup != group.Choose {
+			return nil, fmt.Errorf("%w: choose %d subjects from %s, %d chosen", ErrInvalidElectiveChoice, group.Choose, group.Name, pickedInGroup)
+		}
+
+		kept := subjects[:0:0]
+		for _, s := range subjects {
+			if group.Contains(s.ID) && !s.HasPassed && !picked[s.ID] {
+				continue
+			}
+			kept = append(kept, s)
+		}
+		subjects = kept
+		has = subjectIDSet(subjects)
+		for _, s := range major.Subjects {
+			if picked[s.ID] && group.Contains(s.ID) && !has[s.ID] {
+				s.HasPassed = false
+				s.ExamGrade = ExamGrade{}
+				subjects = append(subjects, s)
+			}
+		}
+	}
+	return subjects, nil
+}
+
+// SetPrerequisites replaces the subjects that must be passed before a student may register for
+// the subject's exam. Prerequisites must exist and must not lead back to the subject.
+func (r *Repository) SetPrerequisites(subjectID primitive.ObjectID, prerequisiteIDs []primitive.ObjectID) (*Subject, error) {
+	subject, err := r.GetSubjectByID(subjectID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if prerequisiteIDs == nil {
+		prerequisiteIDs = []primitive.ObjectID{}
+	}
+	found, err := r.subjectsByID(prerequisiteIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range prerequisiteIDs {
+		if id == subjectID {
+			return nil, fmt.Errorf("%w: %s cannot be its own prerequisite", ErrInvalidPrerequisites, subject.Name)
+		}
+		if _, ok := found[id]; !ok {
+			return nil, fmt.Errorf("%w: subject %s not found", ErrInvalidPrerequisites, id.Hex())
+		}
+	}
+
+	// Walk the prerequisites of the prerequisites; reaching the subject again would be a cycle
+	visited := map[primitive.ObjectID]bool{}
+	frontier := prerequisiteIDs
+	for len(frontier) > 0 {
+		next := []primitive.ObjectID{}
+		level, err := r.subjectsByID(frontier)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range level {
+			if visited[s.ID] {
+				continue
+			}
+			visited[s.ID] = true
+			for _, id := range s.PrerequisiteIDs {
+				if id == subjectID {
+					return nil, fmt.Errorf("%w: %s already requires %s", ErrInvalidPrerequisites, s.Name, subject.Name)
+				}
+				next = append(next, id)
+			}
+		}
+		frontier = next
+	}
+
+	_, err = r.getCollection("subjects").UpdateOne(context.TODO(),
+		bson.M{"_id": subjectID},
+		bson.M{"$set": bson.M{"prerequisite_ids": prerequisiteIDs}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	subject.PrerequisiteIDs = prerequisiteIDs
+	return subject, nil
+}
+
+// missingPrerequisites returns the prerequisites of the subject the student has not passed.
+func (r *Repository) missingPrerequisites(student *Student, subjectID primitive.ObjectID) ([]Subject, error) {
+	subjects, err := r.subjectsByID([]primitive.ObjectID{subjectID})
+	if err != nil {
+		return nil, err
+	}
+	subject, ok := subjects[subjectID]
+	if !ok || len(subject.PrerequisiteIDs) == 0 {
+		return nil, nil
+	}
+	passed := map[primitive.ObjectID]bool{}
+	for _, s := range student.Subjects {
+		if s.HasPassed {
+			passed[s.ID] = true
+		}
+	}
+	prerequisites, err := r.subjectsByID(subject.PrerequisiteIDs)
+	if err != nil {
+		return nil, err
+	}
+	var missing []Subject
+	for _, id := range subject.PrerequisiteIDs {
+		if !passed[id] {
+			missing = append(missing, prerequisites[id])
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ElectiveGroup is a set of subjects of one year of a major from which students choose Choose,
+// e.g. "choose 2 of these 5". Its subjects are part of Major.Subjects but only the chosen ones
+// are copied to Student.Subjects.
+type ElectiveGroup struct {
+	ID         primitive.ObjectID   `bson:"_id" json:"id"`
+	Name       string               `bson:"name" json:"name"`
+	Year       int                  `bson:"year" json:"year"`
+	Choose     int                  `bson:"choose" json:"choose"`
+	SubjectIDs []primitive.ObjectID `bson:"subject_ids" json:"subject_ids"`
+}
+
+// Contains reports whether the subject belongs to the group.
+func (g *ElectiveGroup) Contains(subjectID primitive.ObjectID) bool {
+	for _, id := range g.SubjectIDs {
+		if id == subjectID {
+			return true
+		}
+	}
+	return false
+}
+
+type SetElectiveGroupsRequest struct {
+	ElectiveGroups []ElectiveGroup `json:"elective_groups" binding:"required"`
+}
+
+type SetPrerequisitesRequest struct {
+	PrerequisiteIDs []primitive.ObjectID `json:"prerequisite_ids"`
+}
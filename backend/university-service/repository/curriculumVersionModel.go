@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CurriculumVersion is a published, immutable snapshot of a major's curriculum (accredited study
+// plan). Students are pinned to the version they enrolled under via Student.CurriculumVersion, so
+// later edits of Major.Subjects only reach generations enrolled under a newer version.
+type CurriculumVersion struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MajorID        primitive.ObjectID `bson:"major_id" json:"major_id"`
+	Version        int                `bson:"version" json:"version"`
+	Name           string             `bson:"name,omitempty" json:"name,omitempty"`             // e.g. "Akreditacija 2025"
+	Generation     int                `bson:"generation,omitempty" json:"generation,omitempty"` // academic year of the first generation it is meant for
+	Subjects       []Subject          `bson:"subjects" json:"subjects"`
+	ElectiveGroups []ElectiveGroup    `bson:"elective_groups,omitempty" json:"elective_groups,omitempty"`
+	PublishedBy    primitive.ObjectID `bson:"published_by,omitempty" json:"published_by,omitempty"`
+	PublishedAt    time.Time          `bson:"published_at" json:"published_at"`
+	Note           string             `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+type PublishCurriculumVersionRequest struct {
+	Name       string `json:"name"`
+	Generation int    `json:"generation"`
+	Note       string `json:"note"`
+}
+
+// CurriculumMove is a subject that is taught in a different year or semester in the newer version.
+type CurriculumMove struct {
+	SubjectID    primitive.ObjectID `json:"subject_id"`
+	SubjectName  string             `json:"subject_name"`
+	FromYear     int                `json:"from_year"`
+	FromSemester int                `json:"from_semester"`
+	ToYear       int                `json:"to_year"`
+	ToSemester   int                `json:"to_semester"`
+}
+
+// CurriculumDiff compares two curriculum versions of a major. Version 0 stands for the major's
+// current, not yet published subject list.
+type CurriculumDiff struct {
+	MajorID primitive.ObjectID `json:"major_id"`
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Added   []Subject          `json:"added"`
+	Removed []Subject          `json:"removed"`
+	Moved   []CurriculumMove   `json:"moved"`
+}
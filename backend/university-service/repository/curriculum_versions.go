@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrCurriculumVersionNotFound = errors.New("curriculum version not found")
+	ErrInvalidCurriculumVersion  = errors.New("invalid curriculum version")
+)
+
+// GetCurriculumVersions returns the major's published curriculum versions, oldest first.
+func (r *Repository) GetCurriculumVersions(majorID primitive.ObjectID) ([]CurriculumVersion, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := r.getCollection("curriculum_versions").Find(context.TODO(), bson.M{"major_id": majorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	versions := []CurriculumVersion{}
+	err = cursor.All(context.TODO(), &versions)
+	return versions, err
+}
+
+func (r *Repository) findCurriculumVersion(filter bson.M, opts ...*options.FindOneOptions) (*CurriculumVersion, error) {
+	var version CurriculumVersion
+	err := r.getCollection("curriculum_versions").FindOne(context.TODO(), filter, opts...).Decode(&version)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetCurriculumVersion returns nil, nil when the major has no such version.
+func (r *Repository) GetCurriculumVersion(majorID primitive.ObjectID, version int) (*CurriculumVersion, error) {
+	return r.findCurriculumVersion(bson.M{"major_id": majorID, "version": version})
+}
+
+// GetLatestCurriculumVersion returns the major's newest curriculum version, or nil if none was published.
+func (r *Repository) GetLatestCurriculumVersion(majorID primitive.ObjectID) (*CurriculumVersion, error) {
+	return r.findCurriculumVersion(bson.M{"major_id": majorID}, options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}))
+}
+
+// PublishCurriculumVersion snapshots the major's current subjects and elective groups as its next
+// curriculum version. Students already pinned to an earlier version keep it; students of the
+// generation the version is meant for, and later ones, are pinned to it when they enroll. The
+// first version of a major also pins the students already following the major's current subjects,
+// so later edits of the major no longer reach them.
+func (r *Repository) PublishCurriculumVersion(majorID primitive.ObjectID, req PublishCurriculumVersionRequest, publishedBy primitive.ObjectID) (*CurriculumVersion, error) {
+	major, err := r.GetMajorByID(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+	if len(major.Subjects) == 0 {
+		return nil, fmt.Errorf("%w: %s has no subjects", ErrInvalidCurriculumVersion, major.Name)
+	}
+	if req.Generation < 0 {
+		return nil, fmt.Errorf("%w: generation cannot be negative", ErrInvalidCurriculumVersion)
+	}
+	latest, err := r.GetLatestCurriculumVersion(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && req.Generation != 0 && req.Generation < latest.Generation {
+		return nil, fmt.Errorf("%w: version %d is already meant for the %s generation", ErrInvalidCurriculumVersion, latest.Version, formatAcademicYear(latest.Generation))
+	}
+
+	seq, err := r.nextSequence("curriculum_version:" + majorID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	subjects := make([]Subject, 0, len(major.Subjects))
+	for _, s := range major.Subjects {
+		s.HasPassed = false
+		s.ExamGrade = ExamGrade{}
+		subjects = append(subjects, s)
+	}
+	version := &CurriculumVersion{
+		ID:             primitive.NewObjectID(),
+		MajorID:        majorID,
+		Version:        int(seq),
+		Name:           req.Name,
+		Generation:     req.Generation,
+		Subjects:       subjects,
+		ElectiveGroups: major.ElectiveGroups,
+		PublishedBy:    publishedBy,
+		PublishedAt:    time.Now(),
+		Note:           req.Note,
+	}
+	if _, err := r.getCollection("curriculum_versions").InsertOne(context.TODO(), version); err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		result, err := r.getCollection("student").UpdateMany(context.TODO(),
+			bson.M{"major_id": majorID, "curriculum_version": bson.M{"$in": bson.A{0, nil}}},
+			bson.M{"$set": bson.M{"curriculum_version": version.Version}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		r.logger.Printf("Pinned %d students of major %s to curriculum version %d", result.ModifiedCount, majorID.Hex(), version.Version)
+	}
+	return version, nil
+}
+
+// curriculumVersionFor picks the version for a student of the given generation out of the major's
+// versions (oldest first): the newest one meant for that generation or an earlier one. A student
+// older than every version gets the oldest one, which is what the major taught when versions were
+// introduced. Returns 0 when the major has no versions.
+func curriculumVersionFor(versions []CurriculumVersion, generation int) int {
+	if len(versions) == 0 {
+		return 0
+	}
+	chosen := versions[0].Version
+	for _, v := range versions {
+		if v.Generation <= generation {
+			chosen = v.Version
+		}
+	}
+	return chosen
+}
+
+// StudentGeneration returns the academic year the student first enrolled in, or fallback when the
+// student has no enrollments yet.
+func (r *Repository) StudentGeneration(studentID primitive.ObjectID, fallback int) (int, error) {
+	first, err := r.findEnrollment(bson.M{"student_id": studentID}, options.FindOne().SetSort(bson.D{{Key: "academic_year", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	if first == nil {
+		return fallback, nil
+	}
+	return first.AcademicYear, nil
+}
+
+// majorAtVersion returns a copy of the major with the curriculum of the given version. Version 0
+// is the major's current subject list.
+func (r *Repository) majorAtVersion(major *Major, version int) (*Major, error) {
+	if version == 0 {
+		return major, nil
+	}
+	snapshot, err := r.GetCurriculumVersion(major.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("%w: version %d of %s", ErrCurriculumVersionNotFound, version, major.Name)
+	}
+	pinned := *major
+	pinned.Subjects = snapshot.Subjects
+	pinned.ElectiveGroups = snapshot.ElectiveGroups
+	return &pinned, nil
+}
+
+// PinnedCurriculum returns the curriculum version the student follows in the major and the major
+// with that version's curriculum. A student staying in a major they are already pinned in keeps
+// their version; otherwise they are pinned to the version meant for their generation (the
+// academic year they first enrolled in), or 0 if the major has no versions yet.
+func (r *Repository) PinnedCurriculum(student *Student, major *Major, sameMajor bool, generation int) (*Major, int, error) {
+	version := student.CurriculumVersion
+	if !sameMajor || version == 0 {
+		versions, err := r.GetCurriculumVersions(major.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		version = curriculumVersionFor(versions, generation)
+	}
+	curriculum, err := r.majorAtVersion(major, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return curriculum, version, nil
+}
+
+// GetStudentMajor returns the student's major with the curriculum version the student is pinned
+// to, or nil when the student has no major.
+func (r *Repository) GetStudentMajor(student *Student) (*Major, error) {
+	if student.MajorID.IsZero() {
+		return nil, nil
+	}
+	major, err := r.GetMajorByID(student.MajorID)
+	if err != nil || major == nil {
+		return nil, err
+	}
+	return r.majorAtVersion(major, student.CurriculumVersion)
+}
+
+// CompareCurriculumVersions lists the subjects added, removed and moved to another year or
+// semester between two versions of the major's curriculum.
+func (r *Repository) CompareCurriculumVersions(majorID primitive.ObjectID, from, to int) (*CurriculumDiff, error) {
+	major, err := r.GetMajorByID(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+	before, err := r.majorAtVersion(major, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.majorAtVersion(major, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &CurriculumDiff{MajorID: majorID, From: from, To: to, Added: []Subject{}, Removed: []Subject{}, Moved: []CurriculumMove{}}
+	old := make(map[primitive.ObjectID]Subject, len(before.Subjects))
+	for _, s := range before.Subjects {
+		old[s.ID] = s
+	}
+	kept := make(map[primitive.ObjectID]bool, len(after.Subjects))
+	for _, s := range after.Subjects {
+		kept[s.ID] = true
+		previous, ok := old[s.ID]
+		if !ok {
+			diff.Added = append(diff.Added, s)
+			continue
+		}
+		if previous.Year != s.Year || previous.Semester != s.Semester {
+			diff.Moved = append(diff.Moved, CurriculumMove{
+				SubjectID:    s.ID,
+				SubjectName:  s.Name,
+				FromYear:     previous.Year,
+				FromSemester: previous.Semester,
+				ToYear:       s.Year,
+				ToSemester:   s.Semester,
+			})
+		}
+	}
+	for _, s := range before.Subjects {
+		if !kept[s.ID] {
+			diff.Removed = append(diff.Removed, s)
+		}
+	}
+	return diff, nil
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurriculumVersionFor(t *testing.T) {
+	accreditation2021 := CurriculumVersion{Version: 1}
+	accreditation2024 := CurriculumVersion{Version: 2, Generation: 2024}
+	accreditation2026 := CurriculumVersion{Version: 3, Generation: 2026}
+
+	tests := []struct {
+		name       string
+		versions   []CurriculumVersion
+		generation int
+		want       int
+	}{
+		{
+			name:       "major without versions follows its current subjects",
+			generation: 2024,
+			want:       0,
+		},
+		{
+			name:       "version without a generation applies to every student",
+			versions:   []CurriculumVersion{accreditation2021},
+			generation: 2019,
+			want:       1,
+		},
+		{
+			name:       "student gets the version of their generation",
+			versions:   []CurriculumVersion{accreditation2021, accreditation2024, accreditation2026},
+			generation: 2024,
+			want:       2,
+		},
+		{
+			name:       "version published for a later generation is not used",
+			versions:   []CurriculumVersion{accreditation2021, accreditation2024, accreditation2026},
+			generation: 2025,
+			want:       2,
+		},
+		{
+			name:       "student older than every version gets the oldest one",
+			versions:   []CurriculumVersion{accreditation2024, accreditation2026},
+			generation: 2022,
+			want:       2,
+		},
+		{
+			name:       "newest generation gets the newest version",
+			versions:   []CurriculumVersion{accreditation2021, accreditation2024, accreditation2026},
+			generation: 2027,
+			want:       3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, curriculumVersionFor(tt.versions, tt.generation))
+		})
+	}
+}
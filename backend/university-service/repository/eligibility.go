@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetEligibilityRules returns the rule toggles of a major, with every rule listed.
+// Majors that were never configured have every rule that is enabled by default.
+func (r *Repository) GetEligibilityRules(majorID primitive.ObjectID) (*EligibilityRuleSet, error) {
+	var stored EligibilityRuleSet
+	err := r.getCollection("eligibility_rules").FindOne(context.TODO(), bson.M{"major_id": majorID}).Decode(&stored)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	ruleSet := &EligibilityRuleSet{
+		ID:        stored.ID,
+		MajorID:   majorID,
+		Rules:     make(map[EligibilityRule]bool, len(AllEligibilityRules)),
+		UpdatedAt: stored.UpdatedAt,
+	}
+	for _, rule := range AllEligibilityRules {
+		ruleSet.Rules[rule] = stored.Enabled(rule)
+	}
+	return ruleSet, nil
+}
+
+// SetEligibilityRules updates the given rule toggles of a major; rules not mentioned keep their value.
+func (r *Repository) SetEligibilityRules(majorID primitive.ObjectID, rules map[EligibilityRule]bool) (*EligibilityRuleSet, error) {
+	set := bson.M{"updated_at": time.Now()}
+	for rule, enabled := range rules {
+		if !rule.IsValid() {
+			return nil, fmt.Errorf("unknown eligibility rule: %s", rule)
+		}
+		set["rules."+string(rule)] = enabled
+	}
+	_, err := r.getCollection("eligibility_rules").UpdateOne(context.TODO(),
+		bson.M{"major_id": majorID},
+		bson.M{"$set": set, "$setOnInsert": bson.M{"major_id": majorID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetEligibilityRules(majorID)
+}
+
+// CheckExamEligibility runs the rules enabled for the student's major and returns the ones that failed.
+// An empty result means the student may register for the session.
+func (r *Repository) CheckExamEligibility(student *Student, examSession *ExamSession, now time.Time) ([]EligibilityFailure, error) {
+	ruleSet, err := r.GetEligibilityRules(student.MajorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer the student's own copy of the subject, it carries their progress
+	subject := examSession.Subject
+	inCurriculum := false
+	for _, s := range student.Subjects {
+		if s.ID == examSession.Subject.ID {
+			subject = s
+			inCurriculum = true
+			break
+		}
+	}
+
+	failures := []EligibilityFailure{}
+	fail := func(rule EligibilityRule, format string, args ...interface{}) {
+		failures = append(failures, EligibilityFailure{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ruleSet.Enabled(RuleSubjectInCurriculum) && !inCurriculum {
+		fail(RuleSubjectInCurriculum, "%s is not part of the student's curriculum", subject.Name)
+	}
+	if ruleSet.Enabled(RuleNotAlreadyPassed) && subject.HasPassed {
+		fail(RuleNotAlreadyPassed, "%s has already been passed", subject.Name)
+	}
+	if ruleSet.Enabled(RuleYearReached) && subject.Year > student.Year {
+		fail(RuleYearReached, "%s is a year %d subject, the student is in year %d", subject.Name, subject.Year, student.Year)
+	}
+	if ruleSet.Enabled(RulePrerequisitesPassed) {
+		missing, err := r.missingPrerequisites(student, subject.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			names := make([]string, len(missing))
+			for i, s := range missing {
+				names[i] = s.Name
+			}
+			fail(RulePrerequisitesPassed, "%s requires passing %s first", subject.Name, strings.Join(names, ", "))
+		}
+	}
+	if ruleSet.Enabled(RuleExamPeriodVisible) {
+		visible, err := r.isExamPeriodVisible(examSession.ExamPeriodID, now)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			fail(RuleExamPeriodVisible, "the exam period of this session is not open for registration")
+		}
+	}
+	if ruleSet.Enabled(RuleTuitionPaid) {
+		year, err := r.CurrentAcademicYear(now)
+		if err != nil {
+			return nil, err
+		}
+		budget, err := r.isBudgetFinanced(student.ID, year)
+		if err != nil {
+			return nil, err
+		}
+		if !budget {
+			outstanding, err := r.OutstandingTuition(student.ID, year, now)
+			if err != nil {
+				return nil, err
+			}
+			if outstanding > 0 {
+				fail(RuleTuitionPaid, "%.2f of tuition for %s has not been paid", outstanding, formatAcademicYear(year))
+			}
+		}
+	}
+	if ruleSet.Enabled(RuleBalanceNotNegative) {
+		balance, err := r.GetStudentBalance(student.ID)
+		if err != nil {
+			return nil, err
+		}
+		if balance < 0 {
+			fail(RuleBalanceNotNegative, "the student's account balance is %.2f, outstanding charges must be paid first", balance)
+		}
+	}
+	if ruleSet.Enabled(RulePreExamPoints) {
+		points, err := r.GetStudentPoints(subject.ID, student.ID)
+		if err != nil {
+			return nil, err
+		}
+		if points != nil && !points.PreExamMet {
+			fail(RulePreExamPoints, "pre-exam obligations for %s are not met: %s", subject.Name, strings.Join(points.PreExamMissing, "; "))
+		}
+	}
+	return failures, nil
+}
+
+func (r *Repository) isExamPeriodVisible(examPeriodID *primitive.ObjectID, now time.Time) (bool, error) {
+	if examPeriodID == nil {
+		return false, nil
+	}
+	visibleIDs, err := r.GetVisibleExamPeriodIDs(now)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range visibleIDs {
+		if id == *examPeriodID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isBudgetFinanced reports whether the student's enrollment for the academic year is financed by the
+// state. Students without an enrollment for the year are treated as paying tuition.
+func (r *Repository) isBudgetFinanced(studentID primitive.ObjectID, academicYear int) (bool, error) {
+	enrollment, err := r.findEnrollment(bson.M{"student_id": studentID, "academic_year": academicYear})
+	if err != nil || enrollment == nil {
+		return false, err
+	}
+	return enrollment.Financing == FinancingBudget, nil
+}
+
+// academicYearStart returns October 1st of the academic year that contains t.
+func academicYearStart(t time.Time) time.Time {
+	year := t.Year()
+	if t.Month() < time.October {
+		year--
+	}
+	return time.Date(year, time.October, 1, 0, 0, 0, 0, t.Location())
+}
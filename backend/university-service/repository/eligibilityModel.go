@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EligibilityRule identifies one check RegisterForExam runs before a student may register.
+type EligibilityRule string
+
+const (
+	RuleSubjectInCurriculum EligibilityRule = "subject_in_curriculum" // subject is listed in Student.Subjects
+	RuleNotAlreadyPassed    EligibilityRule = "not_already_passed"    // subject is not marked HasPassed
+	RuleYearReached         EligibilityRule = "year_reached"          // subject year <= student year
+	RulePrerequisitesPassed EligibilityRule = "prerequisites_passed"  // every prerequisite of the subject is passed
+	RuleExamPeriodVisible   EligibilityRule = "exam_period_visible"   // session belongs to a period visible to students
+	RuleTuitionPaid         EligibilityRule = "tuition_paid"          // tuition charged for the current academic year is settled; opt-in
+	RuleBalanceNotNegative  EligibilityRule = "balance_not_negative"  // no outstanding debt on the student's ledger
+	RulePreExamPoints       EligibilityRule = "pre_exam_points"       // pre-exam obligations of the subject's grading scheme are met
+)
+
+// AllEligibilityRules lists every rule in the order it is evaluated.
+var AllEligibilityRules = []EligibilityRule{
+	RuleSubjectInCurriculum,
+	RuleNotAlreadyPassed,
+	RuleYearReached,
+	RulePrerequisitesPassed,
+	RuleExamPeriodVisible,
+	RuleTuitionPaid,
+	RuleBalanceNotNegative,
+	RulePreExamPoints,
+}
+
+// IsValid reports whether the rule is one of AllEligibilityRules.
+func (r EligibilityRule) IsValid() bool {
+	for _, rule := range AllEligibilityRules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledByDefault reports whether the rule is checked for majors that did not configure it.
+// Tuition only applies where the major charges it, so that rule has to be switched on.
+func (r EligibilityRule) EnabledByDefault() bool {
+	return r != RuleTuitionPaid
+}
+
+// EligibilityFailure is a rule the student did not satisfy.
+type EligibilityFailure struct {
+	Rule    EligibilityRule `json:"rule"`
+	Message string          `json:"message"`
+}
+
+// EligibilityRuleSet holds which rules are enabled for a major. Rules missing from Rules take
+// their EnabledByDefault value.
+type EligibilityRuleSet struct {
+	ID        primitive.ObjectID       `bson:"_id,omitempty" json:"id,omitempty"`
+	MajorID   primitive.ObjectID       `bson:"major_id" json:"major_id"`
+	Rules     map[EligibilityRule]bool `bson:"rules" json:"rules"`
+	UpdatedAt time.Time                `bson:"updated_at" json:"updated_at"`
+}
+
+// Enabled reports whether the rule should be checked for the major.
+func (s *EligibilityRuleSet) Enabled(rule EligibilityRule) bool {
+	enabled, ok := s.Rules[rule]
+	if !ok {
+		return rule.EnabledByDefault()
+	}
+	return enabled
+}
+
+type UpdateEligibilityRulesRequest struct {
+	Rules map[EligibilityRule]bool `json:"rules" binding:"required"`
+}
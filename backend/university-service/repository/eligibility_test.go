@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcademicYearStart(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"first day of the academic year", time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)},
+		{"autumn", time.Date(2025, time.November, 15, 12, 0, 0, 0, time.UTC), time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)},
+		{"new year belongs to the previous autumn", time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC), time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)},
+		{"last day of september", time.Date(2026, time.September, 30, 23, 59, 0, 0, time.UTC), time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, academicYearStart(tt.t))
+		})
+	}
+}
+
+func TestEligibilityRuleSetEnabled(t *testing.T) {
+	unconfigured := &EligibilityRuleSet{}
+	for _, rule := range AllEligibilityRules {
+		assert.Equal(t, rule != RuleTuitionPaid, unconfigured.Enabled(rule), rule)
+	}
+
+	configured := &EligibilityRuleSet{Rules: map[EligibilityRule]bool{
+		RuleTuitionPaid:   true,
+		RuleYearReached:   false,
+		RulePreExamPoints: true,
+	}}
+	assert.True(t, configured.Enabled(RuleTuitionPaid))
+	assert.False(t, configured.Enabled(RuleYearReached))
+	assert.True(t, configured.Enabled(RulePreExamPoints))
+	assert.True(t, configured.Enabled(RuleSubjectInCurriculum))
+}
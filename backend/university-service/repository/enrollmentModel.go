@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FinancingStatus tells who pays for a year of study.
+type FinancingStatus string
+
+const (
+	FinancingBudget       FinancingStatus = "budget"        // financed by the state (budžet)
+	FinancingSelfFinanced FinancingStatus = "self_financed" // the student pays tuition (samofinansiranje)
+)
+
+func (f FinancingStatus) IsValid() bool {
+	return f == FinancingBudget || f == FinancingSelfFinanced
+}
+
+// SemesterCertification records that studentska služba certified (overila) a semester.
+type SemesterCertification struct {
+	Semester    int                `bson:"semester" json:"semester"`
+	CertifiedBy primitive.ObjectID `bson:"certified_by,omitempty" json:"certified_by,omitempty"`
+	CertifiedAt time.Time          `bson:"certified_at" json:"certified_at"`
+}
+
+// Enrollment is a student's enrollment (upis) in one academic year. AcademicYear is the calendar
+// year the academic year starts in, like AcademicYear.Year. A student who did not meet the
+// requirements to advance re-enrolls in the same year of study with Repeat set (obnova godine).
+type Enrollment struct {
+	ID                primitive.ObjectID      `bson:"_id,omitempty" json:"id"`
+	StudentID         primitive.ObjectID      `bson:"student_id" json:"student_id"`
+	MajorID           primitive.ObjectID      `bson:"major_id,omitempty" json:"major_id,omitempty"`
+	AcademicYear      int                     `bson:"academic_year" json:"academic_year"`
+	YearOfStudy       int                     `bson:"year_of_study" json:"year_of_study"`
+	Financing         FinancingStatus         `bson:"financing" json:"financing"`
+	Repeat            bool                    `bson:"repeat" json:"repeat"`
+	ESPBAtEnrollment  int                     `bson:"espb_at_enrollment" json:"espb_at_enrollment"`
+	TransferredESPB   int                     `bson:"transferred_espb,omitempty" json:"transferred_espb,omitempty"` // credits recognised from another programme or institution
+	Electives         []primitive.ObjectID    `bson:"electives,omitempty" json:"electives,omitempty"`               // elective subjects chosen for this year
+	CurriculumVersion int                     `bson:"curriculum_version,omitempty" json:"curriculum_version,omitempty"`
+	Certifications    []SemesterCertification `bson:"certifications" json:"certifications"`
+	EnrolledBy        primitive.ObjectID      `bson:"enrolled_by,omitempty" json:"enrolled_by,omitempty"`
+	EnrolledAt        time.Time               `bson:"enrolled_at" json:"enrolled_at"`
+	Note              string                  `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// IsCertified reports whether the given semester of the enrollment has been certified.
+func (e *Enrollment) IsCertified(semester int) bool {
+	for _, c := range e.Certifications {
+		if c.Semester == semester {
+			return true
+		}
+	}
+	return false
+}
+
+// EnrollmentRequest is the payload for enrolling a student. AcademicYear defaults to the current
+// academic year for a first enrollment and to the following one when advancing; Financing
+// defaults to the previous enrollment's.
+type EnrollmentRequest struct {
+	AcademicYear    int                  `json:"academic_year"`
+	Financing       FinancingStatus      `json:"financing"`
+	TransferredESPB int                  `json:"transferred_espb"`
+	Electives       []primitive.ObjectID `json:"electives"` // one pick per required subject of each elective group of the year
+	Note            string               `json:"note"`
+}
+
+type CertifySemesterRequest struct {
+	Semester int `json:"semester" binding:"required"`
+}
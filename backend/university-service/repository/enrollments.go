@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrEnrollmentNotFound       = errors.New("enrollment not found")
+	ErrInvalidEnrollment        = errors.New("invalid enrollment")
+	ErrAlreadyEnrolled          = errors.New("the student is already enrolled in this academic year")
+	ErrSemesterNotCertified     = errors.New("the previous year has semesters that are not certified")
+	ErrSemesterAlreadyCertified = errors.New("the semester is already certified")
+)
+
+// SemestersPerYear is how many semesters every year of study has.
+const SemestersPerYear = 2
+
+// CurrentAcademicYear returns the calendar year the academic year containing now starts in,
+// taken from the academic calendar when one is defined.
+func (r *Repository) CurrentAcademicYear(now time.Time) (int, error) {
+	year, err := r.GetAcademicYearForDate(now)
+	if err != nil {
+		return 0, err
+	}
+	if year != nil {
+		return year.Year, nil
+	}
+	return academicYearStart(now).Year(), nil
+}
+
+// GetEnrollmentsByStudent returns the student's enrollment history, oldest first.
+func (r *Repository) GetEnrollmentsByStudent(studentID primitive.ObjectID) ([]Enrollment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "academic_year", Value: 1}})
+	cursor, err := r.getCollection("enrollments").Find(context.TODO(), bson.M{"student_id": studentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	enrollments := []Enrollment{}
+	err = cursor.All(context.TODO(), &enrollments)
+	return enrollments, err
+}
+
+func (r *Repository) findEnrollment(filter bson.M, opts ...*options.FindOneOptions) (*Enrollment, error) {
+	var enrollment Enrollment
+	err := r.getCollection("enrollments").FindOne(context.TODO(), filter, opts...).Decode(&enrollment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// GetEnrollmentByID returns nil, nil when the enrollment does not exist.
+func (r *Repository) GetEnrollmentByID(id primitive.ObjectID) (*Enrollment, error) {
+	return r.findEnrollment(bson.M{"_id": id})
+}
+
+// GetLatestEnrollment returns the student's most recent enrollment, or nil if there is none.
+func (r *Repository) GetLatestEnrollment(studentID primitive.ObjectID) (*Enrollment, error) {
+	return r.findEnrollment(bson.M{"student_id": studentID}, options.FindOne().SetSort(bson.D{{Key: "academic_year", Value: -1}}))
+}
+
+// EnrollStudent records the student's enrollment and moves the student to its year of study.
+// An enrollment must come after the student's previous one, and both semesters of the previous
+// one must be certified. Financing defaults to the previous enrollment's. The electives chosen
+// for the year are added to the student's subjects. A student not yet pinned to a curriculum
+// version is pinned to the major's latest one. On their first enrollment the student is given an
+// index number, which never changes afterwards. A self-financed enrollment is charged the major's
+// tuition, when it has one.
+func (r *Repository) EnrollStudent(student *Student, enrollment *Enrollment) error {
+	latest, err := r.GetLatestEnrollment(student.ID)
+	if err != nil {
+		return err
+	}
+	if enrollment.Financing == "" && latest != nil {
+		enrollment.Financing = latest.Financing
+	}
+	if !enrollment.Financing.IsValid() {
+		return fmt.Errorf("%w: financing must be %q or %q", ErrInvalidEnrollment, FinancingBudget, FinancingSelfFinanced)
+	}
+	if enrollment.YearOfStudy < 1 {
+		return fmt.Errorf("%w: year of study must be at least 1", ErrInvalidEnrollment)
+	}
+	if enrollment.TransferredESPB < 0 {
+		return fmt.Errorf("%w: transferred_espb cannot be negative", ErrInvalidEnrollment)
+	}
+	if latest != nil {
+		if latest.AcademicYear == enrollment.AcademicYear {
+			return fmt.Errorf("%w: %s", ErrAlreadyEnrolled, formatAcademicYear(enrollment.AcademicYear))
+		}
+		if latest.AcademicYear > enrollment.AcademicYear {
+			return fmt.Errorf("%w: the student is already enrolled in %s", ErrInvalidEnrollment, formatAcademicYear(latest.AcademicYear))
+		}
+		for semester := 1; semester <= SemestersPerYear; semester++ {
+			if !latest.IsCertified(semester) {
+				return fmt.Errorf("%w: semester %d of %s", ErrSemesterNotCertified, semester, formatAcademicYear(latest.AcademicYear))
+			}
+		}
+	}
+
+	// Electives of the year being enrolled are chosen now and added to the student's subjects
+	subjects := student.Subjects
+	version := student.CurriculumVersion
+	var major *Major
+	if !student.MajorID.IsZero() {
+		if major, err = r.GetMajorByID(student.MajorID); err != nil {
+			return err
+		}
+		if major != nil {
+			generation, err := r.StudentGeneration(student.ID, enrollment.AcademicYear)
+			if err != nil {
+				return err
+			}
+			curriculum, pinned, err := r.PinnedCurriculum(student, major, true, generation)
+			if err != nil {
+				return err
+			}
+			// A student not yet pinned follows the version they enroll under from now on
+			if pinned != version {
+				subjects = MergeCurriculum(subjects, curriculum.CurriculumFor(subjects), true)
+				version = pinned
+			}
+			if subjects, err = chooseElectives(subjects, curriculum, enrollment.YearOfStudy, enrollment.Electives); err != nil {
+				return err
+			}
+		}
+	}
+
+	enrollment.ID = primitive.NewObjectID()
+	enrollment.StudentID = student.ID
+	enrollment.MajorID = student.MajorID
+	enrollment.CurriculumVersion = version
+	enrollment.Certifications = []SemesterCertification{}
+	enrollment.EnrolledAt = time.Now()
+	// The writes below are not transactional; when one fails the earlier ones are undone, so the
+	// enrollment can simply be retried
+	if _, err := r.getCollection("enrollments").InsertOne(context.TODO(), enrollment); err != nil {
+		return err
+	}
+	if _, err := r.getCollection("student").UpdateOne(context.TODO(),
+		bson.M{"_id": student.ID},
+		bson.M{"$set": bson.M{"year": enrollment.YearOfStudy, "subjects": subjects, "curriculum_version": version}},
+	); err != nil {
+		r.undoEnrollment(enrollment, student, nil)
+		return err
+	}
+	if err := r.assignIndexNumber(student, major, enrollment.AcademicYear); err != nil {
+		r.undoEnrollment(enrollment, student, nil)
+		return err
+	}
+	if enrollment.Financing == FinancingSelfFinanced && major != nil && major.TuitionFee > 0 {
+		if charged, err := r.ChargeTuition(enrollment, major.TuitionFee, major.TuitionInstalments); err != nil {
+			r.undoEnrollment(enrollment, student, charged)
+			return err
+		}
+	}
+	student.Year = enrollment.YearOfStudy
+	student.Subjects = subjects
+	student.CurriculumVersion = version
+	return nil
+}
+
+// undoEnrollment removes an enrollment whose later steps failed, along with the tuition already
+// charged for it, and puts back the student's year, subjects and curriculum version. An index
+// number already assigned is kept; the retry reuses it. Failures are logged.
+func (r *Repository) undoEnrollment(enrollment *Enrollment, student *Student, charged []LedgerEntry) {
+	for _, entry := range charged {
+		if _, err := r.getCollection("ledger").DeleteOne(context.TODO(), bson.M{"_id": entry.ID}); err != nil {
+			r.logger.Printf("Failed to roll back tuition charge %s of enrollment %s: %v", entry.ID.Hex(), enrollment.ID.Hex(), err)
+		}
+	}
+	if _, err := r.getCollection("student").UpdateOne(context.TODO(),
+		bson.M{"_id": student.ID},
+		bson.M{"$set": bson.M{"year": student.Year, "subjects": student.Subjects, "curriculum_version": student.CurriculumVersion}},
+	); err != nil {
+		r.logger.Printf("Failed to roll back student %s after enrollment %s failed: %v", student.ID.Hex(), enrollment.ID.Hex(), err)
+	}
+	if _, err := r.getCollection("enrollments").DeleteOne(context.TODO(), bson.M{"_id": enrollment.ID}); err != nil {
+		r.logger.Printf("Failed to roll back enrollment %s: %v", enrollment.ID.Hex(), err)
+	}
+}
+
+// CertifySemester marks a semester of the enrollment as certified (overen).
+func (r *Repository) CertifySemester(id primitive.ObjectID, semester int, certifiedBy primitive.ObjectID) (*Enrollment, error) {
+	if semester < 1 || semester > SemestersPerYear {
+		return nil, fmt.Errorf("%w: semester must be 1 or 2", ErrInvalidEnrollment)
+	}
+	enrollment, err := r.GetEnrollmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, ErrEnrollmentNotFound
+	}
+	if enrollment.IsCertified(semester) {
+		return nil, fmt.Errorf("%w: semester %d of %s", ErrSemesterAlreadyCertified, semester, formatAcademicYear(enrollment.AcademicYear))
+	}
+	if semester > 1 && !enrollment.IsCertified(semester-1) {
+		return nil, fmt.Errorf("%w: semester %d must be certified first", ErrInvalidEnrollment, semester-1)
+	}
+
+	certification := SemesterCertification{Semester: semester, CertifiedBy: certifiedBy, CertifiedAt: time.Now()}
+	result, err := r.getCollection("enrollments").UpdateOne(context.TODO(),
+		bson.M{"_id": id, "certifications.semester": bson.M{"$ne": semester}},
+		bson.M{"$push": bson.M{"certifications": certification}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.ModifiedCount == 0 {
+		return nil, fmt.Errorf("%w: semester %d of %s", ErrSemesterAlreadyCertified, semester, formatAcademicYear(enrollment.AcademicYear))
+	}
+	enrollment.Certifications = append(enrollment.Certifications, certification)
+	return enrollment, nil
+}
+
+// transferredESPB returns the credits recognised across all of the student's enrollments.
+func (r *Repository) transferredESPB(studentID primitive.ObjectID) (int, error) {
+	enrollments, err := r.GetEnrollmentsByStudent(studentID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, e := range enrollments {
+		total += e.TransferredESPB
+	}
+	return total, nil
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ESPBPerYear is the standard workload of one year of study.
+const ESPBPerYear = 60
+
+// ESPBToAdvanceFrom returns how many ESPB a student in the given year must have earned to enroll in the next one.
+func (m *Major) ESPBToAdvanceFrom(year int) int {
+	perYear := m.MinESPBPerYear
+	if perYear <= 0 {
+		perYear = ESPBPerYear
+	}
+	return perYear * year
+}
+
+// ESPBToGraduate returns the ESPB of the whole programme.
+func (m *Major) ESPBToGraduate() int {
+	return m.Duration * ESPBPerYear
+}
+
+// CurriculumHasESPB reports whether subjects carry credits. Curricula created before ESPB existed
+// have none, and fall back to the "every subject passed" rules.
+func CurriculumHasESPB(subjects []Subject) bool {
+	for _, s := range subjects {
+		if s.ESPB > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// latestPassedGrades returns the most recent passing grade for each subject.
+func latestPassedGrades(grades []ExamGrade) map[primitive.ObjectID]ExamGrade {
+	latest := make(map[primitive.ObjectID]ExamGrade)
+	for _, g := range grades {
+		if !g.Passed || g.SubjectId.IsZero() {
+			continue
+		}
+		if prev, ok := latest[g.SubjectId]; !ok || g.GradedAt.After(prev.GradedAt) {
+			latest[g.SubjectId] = g
+		}
+	}
+	return latest
+}
+
+// weightedGPA returns the ESPB-weighted average of the grades. When none of the subjects carry
+// credits it falls back to the plain average.
+func weightedGPA(grades, credits []int) float64 {
+	weighted, totalCredits, sum := 0, 0, 0
+	for i, g := range grades {
+		weighted += g * credits[i]
+		totalCredits += credits[i]
+		sum += g
+	}
+	if totalCredits > 0 {
+		return float64(weighted) / float64(totalCredits)
+	}
+	if len(grades) > 0 {
+		return float64(sum) / float64(len(grades))
+	}
+	return 0
+}
+
+// subjectsByID loads subjects from the subjects collection, which holds their current ESPB.
+func (r *Repository) subjectsByID(ids []primitive.ObjectID) (map[primitive.ObjectID]Subject, error) {
+	subjects := make(map[primitive.ObjectID]Subject, len(ids))
+	if len(ids) == 0 {
+		return subjects, nil
+	}
+	cursor, err := r.getCollection("subjects").Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var found []Subject
+	if err := cursor.All(context.TODO(), &found); err != nil {
+		return nil, err
+	}
+	for _, s := range found {
+		subjects[s.ID] = s
+	}
+	return subjects, nil
+}
+
+// RecalculateStudentStanding recomputes the credit-weighted GPA and the earned ESPB from the
+// student's passing grades and stores both on the student. ESPB transferred at enrollment count
+// towards the total but not towards the GPA.
+func (r *Repository) RecalculateStudentStanding(studentID primitive.ObjectID) (float64, int, error) {
+	grades, err := r.GetExamGradesByStudent(studentID)
+	if err != nil {
+		return 0, 0, err
+	}
+	passed := latestPassedGrades(grades)
+	ids := make([]primitive.ObjectID, 0, len(passed))
+	for id := range passed {
+		ids = append(ids, id)
+	}
+	subjects, err := r.subjectsByID(ids)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var gradeValues, credits []int
+	espb := 0
+	for subjectID, g := range passed {
+		gradeValues = append(gradeValues, g.Grade)
+		credits = append(credits, subjects[subjectID].ESPB)
+		espb += subjects[subjectID].ESPB
+	}
+	gpa := weightedGPA(gradeValues, credits)
+	transferred, err := r.transferredESPB(studentID)
+	if err != nil {
+		return 0, 0, err
+	}
+	espb += transferred
+
+	result, err := r.getCollection("student").UpdateOne(context.TODO(),
+		bson.M{"_id": studentID},
+		bson.M{"$set": bson.M{"gpa": gpa, "esbp": espb}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	if result.MatchedCount == 0 {
+		return 0, 0, fmt.Errorf("student not found")
+	}
+	r.logger.Printf("Student %s standing updated: GPA %.2f, %d ESPB", studentID.Hex(), gpa, espb)
+	return gpa, espb, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultGradingDeadline is how long professors have to grade an exam after it is held.
+const DefaultGradingDeadline = 14 * 24 * time.Hour
+
+// GradingReminderLevel is how far the grading reminders for an exam have escalated.
+type GradingReminderLevel int
+
+const (
+	ReminderNone    GradingReminderLevel = iota
+	ReminderFirst                        // half of the grading period has passed
+	ReminderFinal                        // one day before the deadline
+	ReminderOverdue                      // the deadline has passed; studentska služba is notified too
+)
+
+// GradingReminderLevelAt returns the reminder level due at now for an exam held at examDate.
+func GradingReminderLevelAt(examDate, deadline, now time.Time) GradingReminderLevel {
+	switch {
+	case !now.Before(deadline):
+		return ReminderOverdue
+	case !now.Before(deadline.Add(-24 * time.Hour)):
+		return ReminderFinal
+	case !now.Before(examDate.Add(deadline.Sub(examDate) / 2)):
+		return ReminderFirst
+	default:
+		return ReminderNone
+	}
+}
+
+// ExamGradingProgress shows how many registered students of an exam still need a grade.
+type ExamGradingProgress struct {
+	ExamSessionID   primitive.ObjectID `json:"exam_session_id"`
+	Status          ExamStatus         `json:"status"`
+	Registered      int                `json:"registered"`
+	Graded          int                `json:"graded"`
+	Absent          int                `json:"absent"`
+	Remaining       int                `json:"remaining"`
+	GradingDeadline *time.Time         `json:"grading_deadline,omitempty"`
+}
+
+// GradingReminder is a reminder that became due for an exam that is not fully graded.
+type GradingReminder struct {
+	ExamSession ExamSession
+	Level       GradingReminderLevel
+	Deadline    time.Time
+	Remaining   int
+}
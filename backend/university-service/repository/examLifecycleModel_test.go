@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradingReminderLevelAt(t *testing.T) {
+	examDate := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	deadline := examDate.Add(DefaultGradingDeadline)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want GradingReminderLevel
+	}{
+		{"right after the exam", examDate.Add(time.Hour), ReminderNone},
+		{"just before half of the period", examDate.Add(7*24*time.Hour - time.Second), ReminderNone},
+		{"half of the period", examDate.Add(7 * 24 * time.Hour), ReminderFirst},
+		{"just before the last day", deadline.Add(-24*time.Hour - time.Second), ReminderFirst},
+		{"last day", deadline.Add(-24 * time.Hour), ReminderFinal},
+		{"just before the deadline", deadline.Add(-time.Second), ReminderFinal},
+		{"at the deadline", deadline, ReminderOverdue},
+		{"long after the deadline", deadline.AddDate(0, 1, 0), ReminderOverdue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GradingReminderLevelAt(examDate, deadline, tt.now))
+		})
+	}
+}
+
+func TestGradingReminderLevelAtShortDeadline(t *testing.T) {
+	// With a deadline of one day the final reminder is due straight away
+	examDate := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	deadline := examDate.Add(24 * time.Hour)
+
+	assert.Equal(t, ReminderFinal, GradingReminderLevelAt(examDate, deadline, examDate))
+	assert.Equal(t, ReminderOverdue, GradingReminderLevelAt(examDate, deadline, deadline))
+}
@@ -9,15 +9,19 @@ import (
 // ExamPeriod defines a date range during which exams can be scheduled.
 // Optionally scoped to a major (MajorID nil = applies to all majors).
 type ExamPeriod struct {
-	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	Name         string             `bson:"name" json:"name"`                           // e.g. "Winter exam period 2025"
-	StartDate    time.Time          `bson:"start_date" json:"start_date"`                // inclusive
-	EndDate      time.Time          `bson:"end_date" json:"end_date"`                     // inclusive
-	AcademicYear int                `bson:"academic_year" json:"academic_year"`          // e.g. 2025
-	Semester     int                `bson:"semester" json:"semester"`                    // 1 = first, 2 = second semester
-	MajorID      *primitive.ObjectID `bson:"major_id,omitempty" json:"major_id,omitempty"` // nil = all majors
-	IsActive     bool               `bson:"is_active" json:"is_active"`                 // only active periods accept new exams
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name             string              `bson:"name" json:"name"`                                       // e.g. "Winter exam period 2025"
+	StartDate        time.Time           `bson:"start_date" json:"start_date"`                           // inclusive
+	EndDate          time.Time           `bson:"end_date" json:"end_date"`                               // inclusive
+	AcademicYear     int                 `bson:"academic_year" json:"academic_year"`                     // e.g. 2025
+	Semester         int                 `bson:"semester" json:"semester"`                               // 1 = first, 2 = second semester
+	MajorID          *primitive.ObjectID `bson:"major_id,omitempty" json:"major_id,omitempty"`           // nil = all majors
+	IsActive         bool                `bson:"is_active" json:"is_active"`                             // only active periods accept new exams
+	AutoActivate     bool                `bson:"auto_activate,omitempty" json:"auto_activate,omitempty"` // is_active follows ActivateAt and EndDate instead of being toggled by hand
+	ActivateAt       time.Time           `bson:"activate_at,omitempty" json:"activate_at,omitempty"`     // when an auto-activated period opens for scheduling exams
+	TemplateID       *primitive.ObjectID `bson:"template_id,omitempty" json:"template_id,omitempty"`     // template the period was generated from
+	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
+	CalendarWarnings []string            `bson:"-" json:"calendar_warnings,omitempty"` // returned on create/update when the period clashes with the academic calendar
 }
 
 // CreateExamPeriodRequest is the payload for creating an exam period.
@@ -29,26 +33,57 @@ type CreateExamPeriodRequest struct {
 	Semester     int                 `json:"semester"` // 1 or 2
 	MajorID      *primitive.ObjectID `json:"major_id,omitempty"`
 	IsActive     bool                `json:"is_active"`
+	AutoActivate bool                `json:"auto_activate"`
+	ActivateAt   time.Time           `json:"activate_at"` // defaults to start_date when auto_activate is set
 }
 
 // ExamSession represents an exam created by a professor
 type ExamSession struct {
-	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	Subject      Subject             `bson:"subject" json:"subject"`
-	Professor    Professor           `bson:"professor" json:"professor"`
-	ExamDate     time.Time           `bson:"exam_date" json:"exam_date"`
-	ExamPeriodID *primitive.ObjectID `bson:"exam_period_id,omitempty" json:"exam_period_id,omitempty"` // period this exam was scheduled in
-	Location     string              `bson:"location" json:"location"`
-	MaxStudents  int                 `bson:"max_students" json:"max_students"`
-	Status       ExamStatus          `bson:"status" json:"status"` // "scheduled", "completed", "cancelled"
-	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	ID                primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Subject           Subject                `bson:"subject" json:"subject"`
+	Professor         Professor              `bson:"professor" json:"professor"`
+	ExamDate          time.Time              `bson:"exam_date" json:"exam_date"`
+	ExamPeriodID      *primitive.ObjectID    `bson:"exam_period_id,omitempty" json:"exam_period_id,omitempty"` // period this exam was scheduled in
+	Location          string                 `bson:"location" json:"location"`
+	RoomID            *primitive.ObjectID    `bson:"room_id,omitempty" json:"room_id,omitempty"` // reserved room; nil = free-text location only
+	Duration          int                    `bson:"duration_minutes,omitempty" json:"duration_minutes,omitempty"`
+	MaxStudents       int                    `bson:"max_students" json:"max_students"`
+	Registered        int                    `bson:"registered_count" json:"registered_count"`                       // seats taken; updated atomically on (de)registration
+	RegistrationFee   float64                `bson:"registration_fee,omitempty" json:"registration_fee,omitempty"`   // charged to the student's ledger on registration
+	Status            ExamStatus             `bson:"status" json:"status"`                                           // "scheduled", "completed", "cancelled"
+	CalendarConflict  string                 `bson:"calendar_conflict,omitempty" json:"calendar_conflict,omitempty"` // set by the status updater when the date clashes with the academic calendar
+	GradingDeadline   *time.Time             `bson:"grading_deadline,omitempty" json:"grading_deadline,omitempty"`   // set when the exam moves to Pending
+	ReminderLevel     GradingReminderLevel   `bson:"reminder_level,omitempty" json:"reminder_level,omitempty"`       // highest grading reminder sent so far
+	ScheduleConflicts []ExamScheduleConflict `bson:"-" json:"schedule_conflicts,omitempty"`                          // returned on create/update when exams of the same major and year are too close
+	CreatedAt         time.Time              `bson:"created_at" json:"created_at"`
 }
+
+// EndTime returns when the exam is expected to finish (ExamDate + Duration, default 2h).
+func (e *ExamSession) EndTime() time.Time {
+	duration := e.Duration
+	if duration <= 0 {
+		duration = DefaultExamDurationMinutes
+	}
+	return e.ExamDate.Add(time.Duration(duration) * time.Minute)
+}
+
 type ExamRegistration struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Student       Student            `bson:"student" json:"student"`
 	ExamSessionID primitive.ObjectID `bson:"exam_session_id" json:"exam_session_id"`
 	RegisteredAt  time.Time          `bson:"registered_at" json:"registered_at"`
-	Status        ExamStatus         `bson:"status" json:"status"` // "registered", "attended", "missed"
+	Status        ExamStatus         `bson:"status" json:"status"`                                   // "registered", "attended", "missed"
+	CheckedInAt   *time.Time         `bson:"checked_in_at,omitempty" json:"checked_in_at,omitempty"` // set when the professor checks the student in at the exam
+}
+
+// ExamWaitlistEntry is a student waiting for a seat in a full exam session.
+// Entries are promoted in JoinedAt order when a registered student deregisters.
+type ExamWaitlistEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Student       Student            `bson:"student" json:"student"`
+	ExamSessionID primitive.ObjectID `bson:"exam_session_id" json:"exam_session_id"`
+	JoinedAt      time.Time          `bson:"joined_at" json:"joined_at"`
+	Position      int                `bson:"-" json:"position,omitempty"`
 }
 
 // ExamGrade represents a student's grade for an exam
@@ -58,27 +93,34 @@ type ExamGrade struct {
 	ExamRegistrationId primitive.ObjectID `bson:"exam_registration_id" json:"exam_registration_id"`
 	ExamSessionId      primitive.ObjectID `bson:"exam_session_id,omitempty" json:"exam_session_id,omitempty"`
 	SubjectId          primitive.ObjectID `bson:"subject_id,omitempty" json:"subject_id,omitempty"`
-	Grade              int                `bson:"grade" json:"grade"` // 5-10 scale
+	Grade              int                `bson:"grade" json:"grade"`                       // 5-10 scale
+	Points             float64            `bson:"points,omitempty" json:"points,omitempty"` // total points when the grade was computed from the grading scheme
 	Passed             bool               `bson:"passed" json:"passed"`
 	GradedAt           time.Time          `bson:"graded_at" json:"graded_at"`
 	GradedBy           Professor          `bson:"graded_by" json:"graded_by"`
 	Comments           string             `bson:"comments" json:"comments,omitempty"`
 }
 type CreateExamSessionRequest struct {
-	SubjectID   primitive.ObjectID `json:"subject_id" validate:"required"`
-	ProfessorID primitive.ObjectID `json:"professor_id" validate:"required"`
-	ExamDate    time.Time          `json:"exam_date" validate:"required"`
-	Location    string             `json:"location" validate:"required"`
-	MaxStudents int                `json:"max_students" validate:"required,min=1"`
+	SubjectID       primitive.ObjectID  `json:"subject_id" validate:"required"`
+	ProfessorID     primitive.ObjectID  `json:"professor_id" validate:"required"`
+	ExamDate        time.Time           `json:"exam_date" validate:"required"`
+	Location        string              `json:"location"`
+	RoomID          *primitive.ObjectID `json:"room_id,omitempty"` // when set, the room is reserved for the exam
+	Duration        int                 `json:"duration_minutes,omitempty"`
+	MaxStudents     int                 `json:"max_students" validate:"required,min=1"`
+	RegistrationFee float64             `json:"registration_fee,omitempty"`
 }
 type CreateExamRegistrationRequest struct {
-	StudentID     primitive.ObjectID `json:"student_id" validate:"required"`
+	StudentID     primitive.ObjectID `json:"student_id"`
+	IndexNumber   string             `json:"index_number,omitempty"` // identifies the student instead of student_id
 	ExamSessionID primitive.ObjectID `json:"exam_session_id" validate:"required"`
 }
 
 type CreateExamGradeRequest struct {
 	StudentID          primitive.ObjectID `json:"student_id" validate:"required"`
+	IndexNumber        string             `json:"index_number,omitempty"` // identifies the student instead of student_id
 	ExamRegistrationId primitive.ObjectID `json:"exam_registration_id" validate:"required"`
 	Grade              int                `json:"grade" validate:"required,min=5,max=10"`
+	ExamPoints         *float64           `json:"exam_points,omitempty"` // final exam points; the grade is then computed from the subject's grading scheme
 	Comments           string             `json:"comments,omitempty"`
 }
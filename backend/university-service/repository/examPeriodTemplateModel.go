@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultActivationLeadDays is how many days before it starts a generated exam period becomes
+// active, so professors can schedule its exams in advance.
+const DefaultActivationLeadDays = 30
+
+// ExamPeriodTemplate describes an exam period that recurs every academic year, e.g. the January
+// period that starts in the second week of January and lasts three weeks. Months from October on
+// fall in the first calendar year of the academic year, the rest in the second. A period that
+// closes the academic year after it ends, like the October period (oktobarski rok), sets
+// ClosingYear and always falls in the second calendar year.
+type ExamPeriodTemplate struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	NamePattern        string              `bson:"name_pattern" json:"name_pattern"`                     // e.g. "Januarski ispitni rok {year}"; {year} becomes the academic year, e.g. 2025/26
+	Month              time.Month          `bson:"month" json:"month"`                                   // 1 = January
+	WeekOffset         int                 `bson:"week_offset" json:"week_offset"`                       // weeks after the first Monday of Month the period starts
+	Weeks              int                 `bson:"weeks" json:"weeks"`                                   // length of the period, Monday to Sunday
+	Semester           int                 `bson:"semester" json:"semester"`                             // 1 = first, 2 = second semester
+	MajorID            *primitive.ObjectID `bson:"major_id,omitempty" json:"major_id,omitempty"`         // nil = all majors
+	ActivateDaysBefore int                 `bson:"activate_days_before" json:"activate_days_before"`     // 0 = DefaultActivationLeadDays
+	ClosingYear        bool                `bson:"closing_year,omitempty" json:"closing_year,omitempty"` // the period belongs to the academic year that is ending
+	CreatedAt          time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// ExamPeriodTemplateRequest is the payload for creating or replacing an exam period template.
+type ExamPeriodTemplateRequest struct {
+	NamePattern        string              `json:"name_pattern" binding:"required"`
+	Month              time.Month          `json:"month" binding:"required"`
+	WeekOffset         int                 `json:"week_offset"`
+	Weeks              int                 `json:"weeks" binding:"required"`
+	Semester           int                 `json:"semester"`
+	MajorID            *primitive.ObjectID `json:"major_id,omitempty"`
+	ActivateDaysBefore int                 `json:"activate_days_before"`
+	ClosingYear        bool                `json:"closing_year"`
+}
+
+type GenerateExamPeriodsRequest struct {
+	AcademicYear int `json:"academic_year" binding:"required"`
+}
+
+// GeneratedExamPeriods is the result of generating an academic year's exam periods. Templates
+// whose period already exists for the year are listed in Skipped.
+type GeneratedExamPeriods struct {
+	AcademicYear int          `json:"academic_year"`
+	Created      []ExamPeriod `json:"created"`
+	Skipped      []string     `json:"skipped"`
+}
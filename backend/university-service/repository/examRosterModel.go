@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RosterEntry is one registered student on an exam roster.
+type RosterEntry struct {
+	RegistrationID primitive.ObjectID `json:"registration_id"`
+	StudentID      primitive.ObjectID `json:"student_id"`
+	IndexNumber    string             `json:"index_number,omitempty"`
+	FirstName      string             `json:"first_name"`
+	LastName       string             `json:"last_name"`
+	Status         ExamStatus         `json:"status"`
+	CheckedInAt    *time.Time         `json:"checked_in_at,omitempty"`
+	Grade          int                `json:"grade,omitempty"`
+}
+
+// ExamAttendanceStats summarises attendance and results of an exam. Absent students are counted
+// but left out of the pass rate and the average grade.
+type ExamAttendanceStats struct {
+	Registered   int     `json:"registered"`
+	Attended     int     `json:"attended"`
+	Absent       int     `json:"absent"`
+	NotCheckedIn int     `json:"not_checked_in"`
+	Graded       int     `json:"graded"`
+	Passed       int     `json:"passed"`
+	PassRate     float64 `json:"pass_rate"` // share of graded students who passed, 0-1
+	AverageGrade float64 `json:"average_grade,omitempty"`
+}
+
+// ExamRoster lists the students registered for an exam, ordered by index number.
+type ExamRoster struct {
+	ExamSessionID primitive.ObjectID  `json:"exam_session_id"`
+	SubjectName   string              `json:"subject_name"`
+	ProfessorName string              `json:"professor_name"`
+	ExamDate      time.Time           `json:"exam_date"`
+	Location      string              `json:"location,omitempty"`
+	Status        ExamStatus          `json:"status"`
+	Entries       []RosterEntry       `json:"entries"`
+	Statistics    ExamAttendanceStats `json:"statistics"`
+}
+
+// CheckInRequest identifies the student being checked in, usually by index number.
+type CheckInRequest struct {
+	IndexNumber string `json:"index_number,omitempty"`
+	StudentID   string `json:"student_id,omitempty"`
+}
+
+// MarkAbsencesRequest lists the students to mark absent, or asks for everyone who was not
+// checked in to be marked absent.
+type MarkAbsencesRequest struct {
+	IndexNumbers    []string `json:"index_numbers,omitempty"`
+	StudentIDs      []string `json:"student_ids,omitempty"`
+	AllNotCheckedIn bool     `json:"all_not_checked_in,omitempty"`
+}
+
+// RosterUpdateError explains why a student on a bulk roster update was skipped.
+type RosterUpdateError struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// MarkAbsencesResult reports which registrations were marked absent.
+type MarkAbsencesResult struct {
+	Marked []RosterEntry       `json:"marked"`
+	Errors []RosterUpdateError `json:"errors"`
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultExamGapHours is the minimum time between two exams of the same major and year when a
+// major has no scheduling policy of its own.
+const DefaultExamGapHours = 24
+
+// ExamConflictMode decides what happens when a new exam is too close to another one.
+type ExamConflictMode string
+
+const (
+	ExamConflictWarn  ExamConflictMode = "warn"  // the exam is created and the conflicts are returned
+	ExamConflictBlock ExamConflictMode = "block" // the exam is rejected
+)
+
+func (m ExamConflictMode) IsValid() bool {
+	return m == ExamConflictWarn || m == ExamConflictBlock
+}
+
+// ExamSchedulingPolicy is a major's rule for spacing out exams of subjects in the same year.
+type ExamSchedulingPolicy struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	MajorID     primitive.ObjectID `bson:"major_id" json:"major_id"`
+	MinGapHours int                `bson:"min_gap_hours" json:"min_gap_hours"`
+	Mode        ExamConflictMode   `bson:"mode" json:"mode"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// MinGap returns the policy's gap as a duration.
+func (p *ExamSchedulingPolicy) MinGap() time.Duration {
+	return time.Duration(p.MinGapHours) * time.Hour
+}
+
+type UpdateExamSchedulingPolicyRequest struct {
+	MinGapHours *int              `json:"min_gap_hours"`
+	Mode        *ExamConflictMode `json:"mode"`
+}
+
+// ExamScheduleConflict is another exam of the same major and year scheduled too close to an exam.
+type ExamScheduleConflict struct {
+	ExamSessionID primitive.ObjectID `json:"exam_session_id"`
+	SubjectID     primitive.ObjectID `json:"subject_id"`
+	SubjectName   string             `json:"subject_name"`
+	ExamDate      time.Time          `json:"exam_date"`
+	GapHours      float64            `json:"gap_hours"` // time between the two exams; 0 when they overlap
+}
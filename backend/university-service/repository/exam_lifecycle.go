@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrExamRegistrationNotFound = errors.New("exam registration not found")
+	ErrExamNotHeld              = errors.New("the exam has not been held yet")
+	ErrRegistrationGraded       = errors.New("the student has already been graded for this exam")
+)
+
+// GetExamGradingProgress counts the graded, absent and remaining registrations of an exam session.
+// It returns nil if the session does not exist.
+func (r *Repository) GetExamGradingProgress(examSessionID primitive.ObjectID) (*ExamGradingProgress, error) {
+	var session ExamSession
+	err := r.getCollection("exam_sessions").FindOne(context.TODO(), bson.M{"_id": examSessionID}).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	registrations, err := r.GetExamRegistrationsByExamSession(examSessionID)
+	if err != nil {
+		return nil, err
+	}
+	gradedIDs, err := r.getCollection("exam_grades").Distinct(context.TODO(), "exam_registration_id", bson.M{"exam_session_id": examSessionID})
+	if err != nil {
+		return nil, err
+	}
+	graded := make(map[primitive.ObjectID]bool, len(gradedIDs))
+	for _, id := range gradedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			graded[oid] = true
+		}
+	}
+
+	progress := &ExamGradingProgress{
+		ExamSessionID:   examSessionID,
+		Status:          session.Status,
+		Registered:      len(registrations),
+		GradingDeadline: session.GradingDeadline,
+	}
+	for _, registration := range registrations {
+		switch {
+		case graded[registration.ID]:
+			progress.Graded++
+		case registration.Status == RegistrationAbsent:
+			progress.Absent++
+		default:
+			progress.Remaining++
+		}
+	}
+	return progress, nil
+}
+
+// UpdateExamSessionsToPending moves exams whose date has passed from Scheduled to Pending and
+// sets their grading deadline.
+func (r *Repository) UpdateExamSessionsToPending(gradingDeadline time.Duration) error {
+	collection := r.getCollection("exam_sessions")
+	now := time.Now()
+
+	cursor, err := collection.Find(context.TODO(), bson.M{
+		"status":    Scheduled,
+		"exam_date": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return err
+	}
+	var sessions []ExamSession
+	if err := cursor.All(context.TODO(), &sessions); err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, session := range sessions {
+		deadline := session.ExamDate.Add(gradingDeadline)
+		result, err := collection.UpdateOne(context.TODO(),
+			bson.M{"_id": session.ID, "status": Scheduled},
+			bson.M{"$set": bson.M{"status": PendingExam, "grading_deadline": deadline}},
+		)
+		if err != nil {
+			return err
+		}
+		updated += int(result.ModifiedCount)
+	}
+
+	r.logger.Printf("Updated %d exam sessions to pending status", updated)
+	return nil
+}
+
+// CompleteExamSessionIfGraded marks a held exam Completed once every registered student has a
+// grade or is marked absent. It reports whether the session was completed by this call.
+func (r *Repository) CompleteExamSessionIfGraded(examSessionID primitive.ObjectID) (bool, error) {
+	progress, err := r.GetExamGradingProgress(examSessionID)
+	if err != nil || progress == nil {
+		return false, err
+	}
+	if progress.Status == Completed || progress.Remaining > 0 {
+		return false, nil
+	}
+	result, err := r.getCollection("exam_sessions").UpdateOne(context.TODO(),
+		bson.M{
+			"_id":       examSessionID,
+			"status":    bson.M{"$ne": Completed},
+			"exam_date": bson.M{"$lte": time.Now()},
+		},
+		bson.M{"$set": bson.M{"status": Completed}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// CompleteGradedExamSessions completes every pending exam that has been fully graded.
+func (r *Repository) CompleteGradedExamSessions() (int, error) {
+	ids, err := r.getCollection("exam_sessions").Distinct(context.TODO(), "_id", bson.M{"status": PendingExam})
+	if err != nil {
+		return 0, err
+	}
+	completed := 0
+	for _, id := range ids {
+		oid, ok := id.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		done, err := r.CompleteExamSessionIfGraded(oid)
+		if err != nil {
+			return completed, err
+		}
+		if done {
+			completed++
+		}
+	}
+	if completed > 0 {
+		r.logger.Printf("Completed %d fully graded exam sessions", completed)
+	}
+	return completed, nil
+}
+
+// MarkRegistrationAbsent records that the student did not show up for a held exam.
+func (r *Repository) MarkRegistrationAbsent(registrationID primitive.ObjectID) (*ExamRegistration, error) {
+	registration, err := r.GetExamRegistrationById(registrationID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrExamRegistrationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	session, err := r.GetExamSessionByID(registration.ExamSessionID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if session.ExamDate.After(time.Now()) {
+		return nil, ErrExamNotHeld
+	}
+	graded, err := r.getCollection("exam_grades").CountDocuments(context.TODO(), bson.M{"exam_registration_id": registrationID})
+	if err != nil {
+		return nil, err
+	}
+	if graded > 0 {
+		return nil, ErrRegistrationGraded
+	}
+
+	_, err = r.getCollection("exam_registrations").UpdateOne(context.TODO(),
+		bson.M{"_id": registrationID},
+		bson.M{"$set": bson.M{"status": RegistrationAbsent}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	registration.Status = RegistrationAbsent
+	return registration, nil
+}
+
+// DueGradingReminders returns the reminders that became due for pending exams and records them
+// as sent, so each level goes out once per exam even with several instances running.
+func (r *Repository) DueGradingReminders(now time.Time, gradingDeadline time.Duration) ([]GradingReminder, error) {
+	collection := r.getCollection("exam_sessions")
+	cursor, err := collection.Find(context.TODO(), bson.M{"status": PendingExam})
+	if err != nil {
+		return nil, err
+	}
+	var sessions []ExamSession
+	if err := cursor.All(context.TODO(), &sessions); err != nil {
+		return nil, err
+	}
+
+	reminders := []GradingReminder{}
+	for _, session := range sessions {
+		deadline := session.ExamDate.Add(gradingDeadline)
+		if session.GradingDeadline != nil {
+			deadline = *session.GradingDeadline
+		}
+		level := GradingReminderLevelAt(session.ExamDate, deadline, now)
+		if level <= session.ReminderLevel {
+			continue
+		}
+		progress, err := r.GetExamGradingProgress(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		if progress == nil || progress.Remaining == 0 {
+			continue
+		}
+
+		result, err := collection.UpdateOne(context.TODO(),
+			bson.M{"_id": session.ID, "$or": []bson.M{
+				{"reminder_level": bson.M{"$exists": false}},
+				{"reminder_level": bson.M{"$lt": level}},
+			}},
+			bson.M{"$set": bson.M{"reminder_level": level}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		reminders = append(reminders, GradingReminder{
+			ExamSession: session,
+			Level:       level,
+			Deadline:    deadline,
+			Remaining:   progress.Remaining,
+		})
+	}
+	return reminders, nil
+}
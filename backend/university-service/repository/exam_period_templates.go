@@ -0,0 +1,301 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrExamPeriodTemplateNotFound = errors.New("exam period template not found")
+	ErrInvalidExamPeriodTemplate  = errors.New("invalid exam period template")
+	ErrExamPeriodOverlap          = errors.New("exam periods overlap")
+)
+
+// academicMonthOrder sorts months in the order they occur in an academic year, October first.
+func academicMonthOrder(m time.Month) int {
+	return (int(m) + 12 - int(time.October)) % 12
+}
+
+// academicOrder sorts templates in the order their periods occur in an academic year; periods
+// closing the year come after the ones in its months.
+func (t *ExamPeriodTemplate) academicOrder() int {
+	if t.ClosingYear && t.Month >= time.October {
+		return academicMonthOrder(t.Month) + 12
+	}
+	return academicMonthOrder(t.Month)
+}
+
+// PeriodFor returns the template's exam period in the given academic year.
+func (t *ExamPeriodTemplate) PeriodFor(academicYear int) ExamPeriod {
+	year := academicYear
+	if t.Month < time.October || t.ClosingYear {
+		year++
+	}
+	first := time.Date(year, t.Month, 1, 0, 0, 0, 0, time.UTC)
+	toMonday := (int(time.Monday) - int(first.Weekday()) + 7) % 7
+	start := first.AddDate(0, 0, toMonday+7*t.WeekOffset)
+	lead := t.ActivateDaysBefore
+	if lead == 0 {
+		lead = DefaultActivationLeadDays
+	}
+	templateID := t.ID
+	return ExamPeriod{
+		Name:         strings.ReplaceAll(t.NamePattern, "{year}", formatAcademicYear(academicYear)),
+		StartDate:    start,
+		EndDate:      start.AddDate(0, 0, 7*t.Weeks-1),
+		AcademicYear: academicYear,
+		Semester:     t.Semester,
+		MajorID:      t.MajorID,
+		AutoActivate: true,
+		ActivateAt:   start.AddDate(0, 0, -lead),
+		TemplateID:   &templateID,
+	}
+}
+
+// ActiveOn reports whether an auto-activated period should be active at now: from ActivateAt
+// through the last day of the period.
+func (p *ExamPeriod) ActiveOn(now time.Time) bool {
+	return !now.Before(p.ActivateAt) && !startOfDay(now.In(p.EndDate.Location())).After(startOfDay(p.EndDate))
+}
+
+// overlaps reports whether two periods share a day and apply to the same majors. A period for all
+// majors may overlap one for a single major, which takes precedence for that major's exams.
+func (p *ExamPeriod) overlaps(other *ExamPeriod) bool {
+	if (p.MajorID == nil) != (other.MajorID == nil) || (p.MajorID != nil && *p.MajorID != *other.MajorID) {
+		return false
+	}
+	return withinDays(p.StartDate, other.StartDate, other.EndDate) || withinDays(other.StartDate, p.StartDate, p.EndDate)
+}
+
+func (r *Repository) validateExamPeriodTemplate(t *ExamPeriodTemplate) error {
+	if strings.TrimSpace(t.NamePattern) == "" {
+		return fmt.Errorf("%w: name_pattern is required", ErrInvalidExamPeriodTemplate)
+	}
+	if t.Month < time.January || t.Month > time.December {
+		return fmt.Errorf("%w: month must be between 1 and 12", ErrInvalidExamPeriodTemplate)
+	}
+	if t.WeekOffset < 0 || t.WeekOffset > 4 {
+		return fmt.Errorf("%w: week_offset must be between 0 and 4", ErrInvalidExamPeriodTemplate)
+	}
+	if t.Weeks < 1 {
+		return fmt.Errorf("%w: weeks must be at least 1", ErrInvalidExamPeriodTemplate)
+	}
+	if t.Semester != 0 && t.Semester != 1 && t.Semester != 2 {
+		return fmt.Errorf("%w: semester must be 1 or 2", ErrInvalidExamPeriodTemplate)
+	}
+	if t.ActivateDaysBefore < 0 {
+		return fmt.Errorf("%w: activate_days_before cannot be negative", ErrInvalidExamPeriodTemplate)
+	}
+	if t.MajorID != nil {
+		major, err := r.GetMajorByID(*t.MajorID)
+		if err != nil {
+			return err
+		}
+		if major == nil {
+			return ErrMajorNotFound
+		}
+	}
+	return nil
+}
+
+func templateFromRequest(req ExamPeriodTemplateRequest) *ExamPeriodTemplate {
+	return &ExamPeriodTemplate{
+		NamePattern:        req.NamePattern,
+		Month:              req.Month,
+		WeekOffset:         req.WeekOffset,
+		Weeks:              req.Weeks,
+		Semester:           req.Semester,
+		MajorID:            req.MajorID,
+		ActivateDaysBefore: req.ActivateDaysBefore,
+		ClosingYear:        req.ClosingYear,
+	}
+}
+
+// GetExamPeriodTemplates returns all templates in the order their periods occur in the academic year.
+func (r *Repository) GetExamPeriodTemplates() ([]ExamPeriodTemplate, error) {
+	cursor, err := r.getCollection("exam_period_templates").Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	templates := []ExamPeriodTemplate{}
+	if err := cursor.All(context.TODO(), &templates); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		a, b := templates[i], templates[j]
+		if a.academicOrder() != b.academicOrder() {
+			return a.academicOrder() < b.academicOrder()
+		}
+		return a.WeekOffset < b.WeekOffset
+	})
+	return templates, nil
+}
+
+// GetExamPeriodTemplateByID returns nil, nil when the template does not exist.
+func (r *Repository) GetExamPeriodTemplateByID(id primitive.ObjectID) (*ExamPeriodTemplate, error) {
+	var template ExamPeriodTemplate
+	err := r.getCollection("exam_period_templates").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *Repository) CreateExamPeriodTemplate(req ExamPeriodTemplateRequest) (*ExamPeriodTemplate, error) {
+	template := templateFromRequest(req)
+	if err := r.validateExamPeriodTemplate(template); err != nil {
+		return nil, err
+	}
+	template.ID = primitive.NewObjectID()
+	template.CreatedAt = time.Now()
+	if _, err := r.getCollection("exam_period_templates").InsertOne(context.TODO(), template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// UpdateExamPeriodTemplate replaces the template. Periods already generated from it are not changed.
+func (r *Repository) UpdateExamPeriodTemplate(id primitive.ObjectID, req ExamPeriodTemplateRequest) (*ExamPeriodTemplate, error) {
+	existing, err := r.GetExamPeriodTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrExamPeriodTemplateNotFound
+	}
+	template := templateFromRequest(req)
+	if err := r.validateExamPeriodTemplate(template); err != nil {
+		return nil, err
+	}
+	template.ID = id
+	template.CreatedAt = existing.CreatedAt
+	if _, err := r.getCollection("exam_period_templates").ReplaceOne(context.TODO(), bson.M{"_id": id}, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (r *Repository) DeleteExamPeriodTemplate(id primitive.ObjectID) error {
+	result, err := r.getCollection("exam_period_templates").DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrExamPeriodTemplateNotFound
+	}
+	return nil
+}
+
+// GenerateExamPeriods creates the academic year's exam periods from the templates. Periods are
+// checked against the academic calendar and must not overlap each other or existing periods of
+// the same majors; if any check fails nothing is created. Templates already generated for the
+// year are skipped, so the action can be repeated after adding a template.
+func (r *Repository) GenerateExamPeriods(academicYear int, now time.Time) (*GeneratedExamPeriods, error) {
+	templates, err := r.GetExamPeriodTemplates()
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%w: no templates are defined", ErrExamPeriodTemplateNotFound)
+	}
+	existing, err := r.GetAllExamPeriods()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GeneratedExamPeriods{AcademicYear: academicYear, Created: []ExamPeriod{}, Skipped: []string{}}
+	generated := make(map[primitive.ObjectID]bool)
+	for _, p := range existing {
+		if p.TemplateID != nil && p.AcademicYear == academicYear {
+			generated[*p.TemplateID] = true
+		}
+	}
+	for i := range templates {
+		template := &templates[i]
+		period := template.PeriodFor(academicYear)
+		if generated[template.ID] {
+			result.Skipped = append(result.Skipped, period.Name)
+			continue
+		}
+		warnings, err := r.CheckExamPeriodCalendar(&period)
+		if template.ClosingYear && errors.Is(err, ErrOutsideAcademicCalendar) {
+			// A period closing the year may fall after its calendar ends
+			warnings, err = []string{fmt.Sprintf("The exam period falls after the calendar of academic year %s, which it closes", formatAcademicYear(academicYear))}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", period.Name, err)
+		}
+		for _, others := range [][]ExamPeriod{existing, result.Created} {
+			for j := range others {
+				if period.overlaps(&others[j]) {
+					return nil, fmt.Errorf("%w: %s (%s - %s) overlaps %s", ErrExamPeriodOverlap, period.Name,
+						period.StartDate.Format("02.01.2006."), period.EndDate.Format("02.01.2006."), others[j].Name)
+				}
+			}
+		}
+		period.ID = primitive.NewObjectID()
+		period.IsActive = period.ActiveOn(now)
+		period.CreatedAt = now
+		period.CalendarWarnings = warnings
+		result.Created = append(result.Created, period)
+	}
+
+	if len(result.Created) == 0 {
+		return result, nil
+	}
+	documents := make([]interface{}, len(result.Created))
+	for i := range result.Created {
+		documents[i] = result.Created[i]
+	}
+	if _, err := r.getCollection("exam_periods").InsertMany(context.TODO(), documents); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SyncExamPeriodActivation activates auto-activated periods whose activation date has come and
+// deactivates those that have ended. It returns how many periods were activated and deactivated.
+func (r *Repository) SyncExamPeriodActivation(now time.Time) (int, int, error) {
+	cursor, err := r.getCollection("exam_periods").Find(context.TODO(), bson.M{"auto_activate": true})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.TODO())
+	var periods []ExamPeriod
+	if err := cursor.All(context.TODO(), &periods); err != nil {
+		return 0, 0, err
+	}
+
+	activated, deactivated := 0, 0
+	for i := range periods {
+		active := periods[i].ActiveOn(now)
+		if active == periods[i].IsActive {
+			continue
+		}
+		_, err := r.getCollection("exam_periods").UpdateOne(context.TODO(),
+			bson.M{"_id": periods[i].ID, "auto_activate": true},
+			bson.M{"$set": bson.M{"is_active": active}},
+		)
+		if err != nil {
+			return activated, deactivated, err
+		}
+		if active {
+			activated++
+		} else {
+			deactivated++
+		}
+	}
+	return activated, deactivated, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamPeriodTemplatePeriodFor(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		template  ExamPeriodTemplate
+		wantName  string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "January falls in the second calendar year",
+			template:  ExamPeriodTemplate{NamePattern: "Januarski rok {year}", Month: time.January, WeekOffset: 1, Weeks: 3},
+			wantName:  "Januarski rok 2025/26",
+			wantStart: date(2026, time.January, 12),
+			wantEnd:   date(2026, time.February, 1),
+		},
+		{
+			name:      "June starts on the first Monday of the month",
+			template:  ExamPeriodTemplate{NamePattern: "Junski rok {year}", Month: time.June, Weeks: 4},
+			wantName:  "Junski rok 2025/26",
+			wantStart: date(2026, time.June, 1),
+			wantEnd:   date(2026, time.June, 28),
+		},
+		{
+			name:      "September falls in the second calendar year",
+			template:  ExamPeriodTemplate{NamePattern: "Septembarski rok {year}", Month: time.September, Weeks: 2},
+			wantName:  "Septembarski rok 2025/26",
+			wantStart: date(2026, time.September, 7),
+			wantEnd:   date(2026, time.September, 20),
+		},
+		{
+			name:      "October closing the year falls after it ends",
+			template:  ExamPeriodTemplate{NamePattern: "Oktobarski rok {year}", Month: time.October, Weeks: 2, ClosingYear: true},
+			wantName:  "Oktobarski rok 2025/26",
+			wantStart: date(2026, time.October, 5),
+			wantEnd:   date(2026, time.October, 18),
+		},
+		{
+			name:      "October not closing the year falls in the first calendar year",
+			template:  ExamPeriodTemplate{NamePattern: "Oktobarski rok {year}", Month: time.October, Weeks: 2},
+			wantName:  "Oktobarski rok 2025/26",
+			wantStart: date(2025, time.October, 6),
+			wantEnd:   date(2025, time.October, 19),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := tt.template.PeriodFor(2025)
+			assert.Equal(t, tt.wantName, period.Name)
+			assert.Equal(t, tt.wantStart, period.StartDate)
+			assert.Equal(t, tt.wantEnd, period.EndDate)
+			assert.Equal(t, 2025, period.AcademicYear)
+			assert.Equal(t, tt.wantStart.AddDate(0, 0, -DefaultActivationLeadDays), period.ActivateAt)
+		})
+	}
+}
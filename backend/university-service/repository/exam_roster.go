@@ -0,0 +1,348 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrCheckInClosed = errors.New("check-in is open on the day of the exam, from one hour before it starts")
+
+// examRegistrations is the roster of an exam loaded for lookups by student ID or index number.
+type examRegistrations struct {
+	list      []ExamRegistration
+	byStudent map[primitive.ObjectID]*ExamRegistration
+	byIndex   map[string]primitive.ObjectID
+	students  map[primitive.ObjectID]Student
+	grades    map[primitive.ObjectID]ExamGrade // by registration ID
+}
+
+// loadExamRegistrations loads the session's registrations together with the current student
+// records and the grades given so far. Registrations hold a copy of the student from when they
+// registered, so the current records are used for index numbers.
+func (r *Repository) loadExamRegistrations(examSessionID primitive.ObjectID) (*examRegistrations, error) {
+	registrations, err := r.GetExamRegistrationsByExamSession(examSessionID)
+	if err != nil {
+		return nil, err
+	}
+	loaded := &examRegistrations{
+		list:      registrations,
+		byStudent: make(map[primitive.ObjectID]*ExamRegistration, len(registrations)),
+		byIndex:   make(map[string]primitive.ObjectID, len(registrations)),
+		students:  make(map[primitive.ObjectID]Student, len(registrations)),
+		grades:    make(map[primitive.ObjectID]ExamGrade),
+	}
+	studentIDs := make([]primitive.ObjectID, 0, len(registrations))
+	for i := range loaded.list {
+		loaded.byStudent[loaded.list[i].Student.ID] = &loaded.list[i]
+		studentIDs = append(studentIDs, loaded.list[i].Student.ID)
+	}
+
+	if len(studentIDs) > 0 {
+		cursor, err := r.getCollection("student").Find(context.TODO(), bson.M{"_id": bson.M{"$in": studentIDs}})
+		if err != nil {
+			return nil, err
+		}
+		var found []Student
+		if err := cursor.All(context.TODO(), &found); err != nil {
+			return nil, err
+		}
+		for _, student := range found {
+			loaded.students[student.ID] = student
+			if student.IndexNumber != "" {
+				loaded.byIndex[student.IndexNumber] = student.ID
+			}
+		}
+	}
+
+	grades, err := r.GetExamGradesByExamSession(examSessionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, grade := range grades {
+		loaded.grades[grade.ExamRegistrationId] = grade
+	}
+	return loaded, nil
+}
+
+// student returns the current record of a registered student, falling back to the copy on the
+// registration if the record is gone.
+func (e *examRegistrations) student(registration *ExamRegistration) Student {
+	if student, ok := e.students[registration.Student.ID]; ok {
+		return student
+	}
+	return registration.Student
+}
+
+// find looks up a registration by student ID or index number. If both are given they must
+// refer to the same student.
+func (e *examRegistrations) find(studentID, indexNumber string) (*ExamRegistration, error) {
+	var id primitive.ObjectID
+	switch {
+	case studentID == "" && indexNumber == "":
+		return nil, errors.New("student_id or index_number is required")
+	case studentID != "":
+		parsed, err := primitive.ObjectIDFromHex(studentID)
+		if err != nil {
+			return nil, errors.New("invalid student_id")
+		}
+		id = parsed
+		if indexNumber != "" && e.byIndex[indexNumber] != id {
+			return nil, errors.New("student_id and index_number refer to different students")
+		}
+	default:
+		parsed, ok := e.byIndex[indexNumber]
+		if !ok {
+			return nil, fmt.Errorf("no student with index number %s is registered for this exam", indexNumber)
+		}
+		id = parsed
+	}
+	registration, ok := e.byStudent[id]
+	if !ok {
+		return nil, errors.New("the student is not registered for this exam")
+	}
+	return registration, nil
+}
+
+func (e *examRegistrations) entry(registration *ExamRegistration) RosterEntry {
+	student := e.student(registration)
+	entry := RosterEntry{
+		RegistrationID: registration.ID,
+		StudentID:      student.ID,
+		IndexNumber:    student.IndexNumber,
+		Status:         registration.Status,
+		CheckedInAt:    registration.CheckedInAt,
+	}
+	if student.FirstName != nil {
+		entry.FirstName = *student.FirstName
+	}
+	if student.LastName != nil {
+		entry.LastName = *student.LastName
+	}
+	if grade, ok := e.grades[registration.ID]; ok {
+		entry.Grade = grade.Grade
+	}
+	if entry.Status == "" {
+		entry.Status = RegistrationRegistered
+	}
+	return entry
+}
+
+// naturalLess orders strings with embedded numbers by value, so SW-2/2025 comes before SW-12/2025.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		na, restA := leadingNumber(a)
+		nb, restB := leadingNumber(b)
+		if restA != a && restB != b {
+			if na != nb {
+				return na < nb
+			}
+			a, b = restA, restB
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingNumber(s string) (int, string) {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, s
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n, s[end:]
+}
+
+// GetExamRoster returns the students registered for the exam with their attendance and grades,
+// ordered by index number. It returns nil if the session does not exist.
+func (r *Repository) GetExamRoster(examSessionID primitive.ObjectID) (*ExamRoster, error) {
+	session, err := r.GetExamSessionByID(examSessionID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := r.loadExamRegistrations(examSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := &ExamRoster{
+		ExamSessionID: session.ID,
+		SubjectName:   session.Subject.Name,
+		ExamDate:      session.ExamDate,
+		Location:      session.Location,
+		Status:        session.Status,
+		Entries:       make([]RosterEntry, 0, len(loaded.list)),
+	}
+	if session.Professor.FirstName != nil && session.Professor.LastName != nil {
+		roster.ProfessorName = *session.Professor.FirstName + " " + *session.Professor.LastName
+	}
+
+	stats := &roster.Statistics
+	gradeSum := 0
+	for i := range loaded.list {
+		entry := loaded.entry(&loaded.list[i])
+		roster.Entries = append(roster.Entries, entry)
+
+		stats.Registered++
+		switch entry.Status {
+		case RegistrationAttended:
+			stats.Attended++
+		case RegistrationAbsent:
+			stats.Absent++
+		default:
+			stats.NotCheckedIn++
+		}
+		if grade, ok := loaded.grades[entry.RegistrationID]; ok && entry.Status != RegistrationAbsent {
+			stats.Graded++
+			gradeSum += grade.Grade
+			if grade.Passed {
+				stats.Passed++
+			}
+		}
+	}
+	if stats.Graded > 0 {
+		stats.PassRate = float64(stats.Passed) / float64(stats.Graded)
+		stats.AverageGrade = float64(gradeSum) / float64(stats.Graded)
+	}
+
+	sort.SliceStable(roster.Entries, func(i, j int) bool {
+		a, b := roster.Entries[i], roster.Entries[j]
+		if a.IndexNumber != b.IndexNumber {
+			if a.IndexNumber == "" || b.IndexNumber == "" {
+				return b.IndexNumber == ""
+			}
+			return naturalLess(a.IndexNumber, b.IndexNumber)
+		}
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+	return roster, nil
+}
+
+// CheckInStudent marks a registered student as present at the exam. Check-in opens an hour
+// before the exam starts and closes at the end of the exam day.
+func (r *Repository) CheckInStudent(session *ExamSession, req CheckInRequest) (*RosterEntry, error) {
+	now := time.Now()
+	if now.Before(session.ExamDate.Add(-time.Hour)) || !now.Before(startOfDay(session.ExamDate).AddDate(0, 0, 1)) {
+		return nil, ErrCheckInClosed
+	}
+	loaded, err := r.loadExamRegistrations(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	registration, err := loaded.find(req.StudentID, req.IndexNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExamRegistrationNotFound, err)
+	}
+	if _, graded := loaded.grades[registration.ID]; graded {
+		return nil, ErrRegistrationGraded
+	}
+	if registration.Status == RegistrationAttended {
+		entry := loaded.entry(registration)
+		return &entry, nil
+	}
+
+	_, err = r.getCollection("exam_registrations").UpdateOne(context.TODO(),
+		bson.M{"_id": registration.ID},
+		bson.M{"$set": bson.M{"status": RegistrationAttended, "checked_in_at": now}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	registration.Status = RegistrationAttended
+	registration.CheckedInAt = &now
+	entry := loaded.entry(registration)
+	return &entry, nil
+}
+
+// MarkAbsences marks several students of a held exam absent at once. Students who were checked
+// in or already graded are reported and left alone.
+func (r *Repository) MarkAbsences(session *ExamSession, req MarkAbsencesRequest) (*MarkAbsencesResult, error) {
+	if session.ExamDate.After(time.Now()) {
+		return nil, ErrExamNotHeld
+	}
+	loaded, err := r.loadExamRegistrations(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MarkAbsencesResult{Marked: []RosterEntry{}, Errors: []RosterUpdateError{}}
+	selected := []*ExamRegistration{}
+	add := func(key string, registration *ExamRegistration) {
+		switch _, graded := loaded.grades[registration.ID]; {
+		case graded:
+			result.Errors = append(result.Errors, RosterUpdateError{Key: key, Error: ErrRegistrationGraded.Error()})
+		case registration.Status == RegistrationAttended:
+			result.Errors = append(result.Errors, RosterUpdateError{Key: key, Error: "the student was checked in"})
+		default:
+			selected = append(selected, registration)
+		}
+	}
+	for _, indexNumber := range req.IndexNumbers {
+		registration, err := loaded.find("", indexNumber)
+		if err != nil {
+			result.Errors = append(result.Errors, RosterUpdateError{Key: indexNumber, Error: err.Error()})
+			continue
+		}
+		add(indexNumber, registration)
+	}
+	for _, studentID := range req.StudentIDs {
+		registration, err := loaded.find(studentID, "")
+		if err != nil {
+			result.Errors = append(result.Errors, RosterUpdateError{Key: studentID, Error: err.Error()})
+			continue
+		}
+		add(studentID, registration)
+	}
+	if req.AllNotCheckedIn {
+		for i := range loaded.list {
+			registration := &loaded.list[i]
+			if _, graded := loaded.grades[registration.ID]; graded || registration.Status == RegistrationAttended {
+				continue
+			}
+			selected = append(selected, registration)
+		}
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	ids := []primitive.ObjectID{}
+	for _, registration := range selected {
+		if seen[registration.ID] {
+			continue
+		}
+		seen[registration.ID] = true
+		ids = append(ids, registration.ID)
+		registration.Status = RegistrationAbsent
+		result.Marked = append(result.Marked, loaded.entry(registration))
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	_, err = r.getCollection("exam_registrations").UpdateMany(context.TODO(),
+		bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$ne": RegistrationAttended}},
+		bson.M{"$set": bson.M{"status": RegistrationAbsent}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrExamScheduleConflict = errors.New("another exam of the same major and year is scheduled too close")
+	ErrExamTimeOverlap      = errors.New("the exam overlaps an exam the student is already registered for")
+)
+
+// GetExamSchedulingPolicy returns the major's scheduling policy, or the defaults if none is stored.
+func (r *Repository) GetExamSchedulingPolicy(majorID primitive.ObjectID) (*ExamSchedulingPolicy, error) {
+	var policy ExamSchedulingPolicy
+	err := r.getCollection("exam_scheduling_policies").FindOne(context.TODO(), bson.M{"major_id": majorID}).Decode(&policy)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &ExamSchedulingPolicy{MajorID: majorID, MinGapHours: DefaultExamGapHours, Mode: ExamConflictWarn}, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetExamSchedulingPolicy updates the given fields of the major's policy; omitted fields keep their value.
+func (r *Repository) SetExamSchedulingPolicy(majorID primitive.ObjectID, req UpdateExamSchedulingPolicyRequest) (*ExamSchedulingPolicy, error) {
+	policy, err := r.GetExamSchedulingPolicy(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if req.MinGapHours != nil {
+		if *req.MinGapHours < 0 {
+			return nil, fmt.Errorf("min_gap_hours cannot be negative")
+		}
+		policy.MinGapHours = *req.MinGapHours
+	}
+	if req.Mode != nil {
+		if !req.Mode.IsValid() {
+			return nil, fmt.Errorf("mode must be %q or %q", ExamConflictWarn, ExamConflictBlock)
+		}
+		policy.Mode = *req.Mode
+	}
+	_, err = r.getCollection("exam_scheduling_policies").UpdateOne(context.TODO(),
+		bson.M{"major_id": majorID},
+		bson.M{"$set": bson.M{
+			"min_gap_hours": policy.MinGapHours,
+			"mode":          policy.Mode,
+			"updated_at":    time.Now(),
+		}, "$setOnInsert": bson.M{"major_id": majorID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetExamSchedulingPolicy(majorID)
+}
+
+// examGap returns the time between two exams, or 0 if they overlap.
+func examGap(a, b *ExamSession) time.Duration {
+	if a.ExamDate.Before(b.ExamDate) {
+		if gap := b.ExamDate.Sub(a.EndTime()); gap > 0 {
+			return gap
+		}
+		return 0
+	}
+	if gap := a.ExamDate.Sub(b.EndTime()); gap > 0 {
+		return gap
+	}
+	return 0
+}
+
+// CheckExamSchedule finds exams of subjects in the same major and year that are closer to the
+// session than the major's minimum gap. The policy is returned so the caller can decide whether
+// to warn or block.
+func (r *Repository) CheckExamSchedule(session *ExamSession) ([]ExamScheduleConflict, *ExamSchedulingPolicy, error) {
+	conflicts := []ExamScheduleConflict{}
+	if session.Subject.MajorID.IsZero() {
+		return conflicts, nil, nil
+	}
+	policy, err := r.GetExamSchedulingPolicy(session.Subject.MajorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy.MinGapHours == 0 {
+		return conflicts, policy, nil
+	}
+
+	// Exams last at most a day, so widening the window by one day catches every candidate
+	window := policy.MinGap() + 24*time.Hour
+	cursor, err := r.getCollection("exam_sessions").Find(context.TODO(), bson.M{
+		"_id":              bson.M{"$ne": session.ID},
+		"subject.major_id": session.Subject.MajorID,
+		"subject.year":     session.Subject.Year,
+		"exam_date": bson.M{
+			"$gt": session.ExamDate.Add(-window),
+			"$lt": session.EndTime().Add(policy.MinGap()),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var others []ExamSession
+	if err := cursor.All(context.TODO(), &others); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range others {
+		gap := examGap(session, &others[i])
+		if gap >= policy.MinGap() {
+			continue
+		}
+		conflicts = append(conflicts, ExamScheduleConflict{
+			ExamSessionID: others[i].ID,
+			SubjectID:     others[i].Subject.ID,
+			SubjectName:   others[i].Subject.Name,
+			ExamDate:      others[i].ExamDate,
+			GapHours:      gap.Hours(),
+		})
+	}
+	return conflicts, policy, nil
+}
+
+// FindOverlappingExam returns an exam the student is registered or waitlisted for whose time
+// overlaps the given session, or nil if there is none.
+func (r *Repository) FindOverlappingExam(studentID primitive.ObjectID, session *ExamSession) (*ExamSession, error) {
+	sessionIDs := []primitive.ObjectID{}
+	for _, name := range []string{"exam_registrations", "exam_waitlist"} {
+		ids, err := r.getCollection(name).Distinct(context.TODO(), "exam_session_id", bson.M{
+			"student._id":     studentID,
+			"exam_session_id": bson.M{"$ne": session.ID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				sessionIDs = append(sessionIDs, oid)
+			}
+		}
+	}
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.getCollection("exam_sessions").Find(context.TODO(), bson.M{
+		"_id":       bson.M{"$in": sessionIDs},
+		"status":    bson.M{"$ne": Completed},
+		"exam_date": bson.M{"$lt": session.EndTime()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sessions []ExamSession
+	if err := cursor.All(context.TODO(), &sessions); err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		if sessions[i].EndTime().After(session.ExamDate) {
+			return &sessions[i], nil
+		}
+	}
+	return nil, nil
+}
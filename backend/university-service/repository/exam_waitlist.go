@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrExamSessionFull    = errors.New("exam session is full")
+	ErrAlreadyOnWaitlist  = errors.New("student is already on the waitlist for this exam")
+	ErrExamSessionClosed  = errors.New("the exam has already taken place or is no longer scheduled")
+	ErrAlreadyRegistered  = errors.New("student is already registered for this exam")
+	ErrCapacityBelowSeats = errors.New("max_students cannot be lower than the number of students already registered")
+)
+
+// EnsureExamRegistrationIndex creates the unique index that keeps a student from being registered
+// for the same exam session twice, e.g. by two concurrent requests.
+func (r *Repository) EnsureExamRegistrationIndex() error {
+	_, err := r.getCollection("exam_registrations").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "student._id", Value: 1}, {Key: "exam_session_id", Value: 1}},
+		Options: options.Index().SetName("student_exam_session_unique").SetUnique(true),
+	})
+	return err
+}
+
+// examSessionOpen reports whether students may still register for, or deregister from, the
+// session: it is still scheduled and its date has not passed. Sessions are stored with a
+// lower-case "scheduled" status, so the comparison ignores case.
+func examSessionOpen(examSession *ExamSession, now time.Time) bool {
+	return strings.EqualFold(string(examSession.Status), string(Scheduled)) && now.Before(examSession.ExamDate)
+}
+
+// ensureRegisteredCount initialises registered_count for sessions created before capacity
+// tracking existed, using the number of registrations already stored.
+func (r *Repository) ensureRegisteredCount(examSessionID primitive.ObjectID) error {
+	sessions := r.getCollection("exam_sessions")
+	missing, err := sessions.CountDocuments(context.TODO(), bson.M{"_id": examSessionID, "registered_count": bson.M{"$exists": false}})
+	if err != nil || missing == 0 {
+		return err
+	}
+	count, err := r.getCollection("exam_registrations").CountDocuments(context.TODO(), bson.M{"exam_session_id": examSessionID})
+	if err != nil {
+		return err
+	}
+	_, err = sessions.UpdateOne(context.TODO(),
+		bson.M{"_id": examSessionID, "registered_count": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"registered_count": count}},
+	)
+	return err
+}
+
+// reserveExamSeat atomically takes a seat in the session. The capacity check and the increment
+// happen in a single conditional update, so two concurrent registrations cannot both take the
+// last seat. max_students <= 0 means the session has no limit.
+func (r *Repository) reserveExamSeat(examSessionID primitive.ObjectID) (bool, error) {
+	if err := r.ensureRegisteredCount(examSessionID); err != nil {
+		return false, err
+	}
+	filter := bson.M{
+		"_id": examSessionID,
+		"$expr": bson.M{"$or": bson.A{
+			bson.M{"$lte": bson.A{"$max_students", 0}},
+			bson.M{"$lt": bson.A{"$registered_count", "$max_students"}},
+		}},
+	}
+	result, err := r.getCollection("exam_sessions").UpdateOne(context.TODO(), filter, bson.M{"$inc": bson.M{"registered_count": 1}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *Repository) releaseExamSeat(examSessionID primitive.ObjectID) error {
+	_, err := r.getCollection("exam_sessions").UpdateOne(context.TODO(),
+		bson.M{"_id": examSessionID, "registered_count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"registered_count": -1}},
+	)
+	return err
+}
+
+// AddToExamWaitlist puts the student at the end of the session's waitlist and returns the entry
+// with its 1-based position.
+func (r *Repository) AddToExamWaitlist(student Student, examSessionID primitive.ObjectID) (*ExamWaitlistEntry, error) {
+	collection := r.getCollection("exam_waitlist")
+	existing, err := collection.CountDocuments(context.TODO(), bson.M{"student._id": student.ID, "exam_session_id": examSessionID})
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrAlreadyOnWaitlist
+	}
+	entry := ExamWaitlistEntry{
+		ID:            primitive.NewObjectID(),
+		Student:       student,
+		ExamSessionID: examSessionID,
+		JoinedAt:      time.Now(),
+	}
+	if _, err := collection.InsertOne(context.TODO(), entry); err != nil {
+		return nil, err
+	}
+	position, err := collection.CountDocuments(context.TODO(), bson.M{
+		"exam_session_id": examSessionID,
+		"joined_at":       bson.M{"$lte": entry.JoinedAt},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = int(position)
+	return &entry, nil
+}
+
+// GetExamWaitlist returns the waitlist of a session in promotion order.
+func (r *Repository) GetExamWaitlist(examSessionID primitive.ObjectID) ([]ExamWaitlistEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.getCollection("exam_waitlist").Find(context.TODO(), bson.M{"exam_session_id": examSessionID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var entries []ExamWaitlistEntry
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries, nil
+}
+
+// RemoveFromExamWaitlist removes the student from the session's waitlist. Returns whether an entry was removed.
+func (r *Repository) RemoveFromExamWaitlist(studentID, examSessionID primitive.ObjectID) (bool, error) {
+	result, err := r.getCollection("exam_waitlist").DeleteOne(context.TODO(), bson.M{
+		"student._id":     studentID,
+		"exam_session_id": examSessionID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// PromoteFromWaitlist registers the first waitlisted student if a seat is free. The checks
+// RegisterForExam runs are repeated, since the student only passed them when joining the waitlist;
+// a student who no longer passes them is dropped from the waitlist and the next one is tried.
+// Returns nil when nobody is waiting, the session is still full or the exam has already taken place.
+func (r *Repository) PromoteFromWaitlist(examSessionID primitive.ObjectID) (*ExamRegistration, error) {
+	waitlist := r.getCollection("exam_waitlist")
+	examSession, err := r.GetExamSessionByID(examSessionID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if !examSessionOpen(examSession, time.Now()) {
+		return nil, nil
+	}
+	for {
+		seated, err := r.reserveExamSeat(examSessionID)
+		if err != nil || !seated {
+			return nil, err
+		}
+
+		var entry ExamWaitlistEntry
+		opts := options.FindOneAndDelete().SetSort(bson.D{{Key: "joined_at", Value: 1}, {Key: "_id", Value: 1}})
+		err = waitlist.FindOneAndDelete(context.TODO(), bson.M{"exam_session_id": examSessionID}, opts).Decode(&entry)
+		if err != nil {
+			_ = r.releaseExamSeat(examSessionID)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		// Skip students who registered some other way while waiting
+		alreadyRegistered, err := r.CheckExamRegistration(entry.Student.ID, examSessionID)
+		if err != nil {
+			_ = r.releaseExamSeat(examSessionID)
+			return nil, err
+		}
+		if alreadyRegistered {
+			_ = r.releaseExamSeat(examSessionID)
+			continue
+		}
+		student, reason, err := r.waitlistedStudentEligible(entry.Student.ID, examSession)
+		if err != nil {
+			_ = r.releaseExamSeat(examSessionID)
+			return nil, err
+		}
+		if student == nil {
+			_ = r.releaseExamSeat(examSessionID)
+			r.logger.Printf("Skipped student %s on the waitlist of exam session %s: %s", entry.Student.ID.Hex(), examSessionID.Hex(), reason)
+			continue
+		}
+
+		registration := ExamRegistration{
+			ID:            primitive.NewObjectID(),
+			Student:       *student,
+			ExamSessionID: examSessionID,
+			RegisteredAt:  time.Now(),
+			Status:        "registered",
+		}
+		if _, err := r.getCollection("exam_registrations").InsertOne(context.TODO(), registration); err != nil {
+			_ = r.releaseExamSeat(examSessionID)
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return nil, err
+		}
+		r.chargeRegistrationFee(&registration)
+		r.logger.Printf("Promoted student %s from waitlist of exam session %s", entry.Student.ID.Hex(), examSessionID.Hex())
+		return &registration, nil
+	}
+}
+
+// waitlistedStudentEligible reloads a waitlisted student and checks that they may still register for
+// the session. Returns the student, or nil and the reason they may not.
+func (r *Repository) waitlistedStudentEligible(studentID primitive.ObjectID, examSession *ExamSession) (*Student, string, error) {
+	student, err := r.GetStudentByID(studentID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, "the student no longer exists", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	student.ID = studentID
+
+	failures, err := r.CheckExamEligibility(student, examSession, time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	if len(failures) > 0 {
+		return nil, failures[0].Message, nil
+	}
+	overlapping, err := r.FindOverlappingExam(studentID, examSession)
+	if err != nil {
+		return nil, "", err
+	}
+	if overlapping != nil {
+		return nil, fmt.Sprintf("%s (%s)", ErrExamTimeOverlap, overlapping.Subject.Name), nil
+	}
+	return student, "", nil
+}
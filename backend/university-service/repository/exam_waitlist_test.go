@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamSessionOpen(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		status   ExamStatus
+		examDate time.Time
+		want     bool
+	}{
+		{name: "scheduled exam in the future", status: "scheduled", examDate: now.Add(24 * time.Hour), want: true},
+		{name: "status is compared without case", status: Scheduled, examDate: now.Add(time.Hour), want: true},
+		{name: "exam date has passed", status: "scheduled", examDate: now.Add(-time.Hour), want: false},
+		{name: "exam starts now", status: "scheduled", examDate: now, want: false},
+		{name: "pending exam", status: PendingExam, examDate: now.Add(time.Hour), want: false},
+		{name: "completed exam", status: Completed, examDate: now.Add(time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &ExamSession{Status: tt.status, ExamDate: tt.examDate}
+			assert.Equal(t, tt.want, examSessionOpen(session, now))
+		})
+	}
+}
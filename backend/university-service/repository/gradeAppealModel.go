@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// By default a student has 36 hours after a grade is recorded to appeal it, and each reviewer
+// has 72 hours to decide before the appeal moves on.
+const (
+	DefaultAppealWindowHours = 36
+	DefaultAppealReviewHours = 72
+)
+
+// AppealStage is where a grade appeal (žalba) currently is. An appeal is reviewed by the
+// professor who gave the grade, then by the head of the department if the professor does not
+// resolve it, and ends with the grade upheld or changed.
+type AppealStage string
+
+const (
+	AppealProfessorReview AppealStage = "professor_review"
+	AppealHeadReview      AppealStage = "head_review"
+	AppealUpheld          AppealStage = "upheld"
+	AppealChanged         AppealStage = "changed"
+)
+
+// IsFinal reports whether the appeal has been resolved.
+func (s AppealStage) IsFinal() bool {
+	return s == AppealUpheld || s == AppealChanged
+}
+
+// AppealDecision is what a reviewer does with an appeal.
+type AppealDecision string
+
+const (
+	AppealDecisionUphold   AppealDecision = "uphold"
+	AppealDecisionChange   AppealDecision = "change"
+	AppealDecisionEscalate AppealDecision = "escalate" // the professor passes the appeal on to the department head
+)
+
+// GradeAppealPolicy is a major's deadlines for filing and reviewing grade appeals.
+type GradeAppealPolicy struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	MajorID     primitive.ObjectID `bson:"major_id" json:"major_id"`
+	WindowHours int                `bson:"window_hours" json:"window_hours"` // time after grading in which an appeal can be filed
+	ReviewHours int                `bson:"review_hours" json:"review_hours"` // time each reviewer has before the appeal escalates
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Window returns the filing window as a duration.
+func (p *GradeAppealPolicy) Window() time.Duration {
+	return time.Duration(p.WindowHours) * time.Hour
+}
+
+// ReviewPeriod returns the review period as a duration.
+func (p *GradeAppealPolicy) ReviewPeriod() time.Duration {
+	return time.Duration(p.ReviewHours) * time.Hour
+}
+
+type UpdateGradeAppealPolicyRequest struct {
+	WindowHours *int `json:"window_hours"`
+	ReviewHours *int `json:"review_hours"`
+}
+
+// AppealEvent is one step in the history of a grade appeal.
+type AppealEvent struct {
+	Stage   AppealStage         `bson:"stage" json:"stage"`
+	ActorID *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // empty when the system escalated the appeal
+	Grade   int                 `bson:"grade,omitempty" json:"grade,omitempty"`
+	Note    string              `bson:"note,omitempty" json:"note,omitempty"`
+	At      time.Time           `bson:"at" json:"at"`
+}
+
+// GradeAppeal is a student's appeal against an exam grade.
+type GradeAppeal struct {
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ExamGradeID      primitive.ObjectID  `bson:"exam_grade_id" json:"exam_grade_id"`
+	ExamSessionID    primitive.ObjectID  `bson:"exam_session_id" json:"exam_session_id"`
+	SubjectID        primitive.ObjectID  `bson:"subject_id" json:"subject_id"`
+	SubjectName      string              `bson:"subject_name" json:"subject_name"`
+	MajorID          primitive.ObjectID  `bson:"major_id,omitempty" json:"major_id,omitempty"`
+	StudentID        primitive.ObjectID  `bson:"student_id" json:"student_id"`
+	ProfessorID      primitive.ObjectID  `bson:"professor_id" json:"professor_id"`
+	DepartmentHeadID *primitive.ObjectID `bson:"department_head_id,omitempty" json:"department_head_id,omitempty"` // set on escalation; empty if the department has no head
+	Reason           string              `bson:"reason" json:"reason"`
+	OriginalGrade    int                 `bson:"original_grade" json:"original_grade"`
+	FinalGrade       int                 `bson:"final_grade,omitempty" json:"final_grade,omitempty"`
+	Stage            AppealStage         `bson:"stage" json:"stage"`
+	ReviewDeadline   *time.Time          `bson:"review_deadline,omitempty" json:"review_deadline,omitempty"`
+	FiledAt          time.Time           `bson:"filed_at" json:"filed_at"`
+	ResolvedAt       *time.Time          `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	History          []AppealEvent       `bson:"history" json:"history"`
+}
+
+// Reviewer returns who has to decide the appeal at its current stage. ok is false when nobody
+// is assigned, i.e. the appeal is resolved or the department has no head; studentska služba
+// decides in the latter case.
+func (a *GradeAppeal) Reviewer() (id primitive.ObjectID, ok bool) {
+	switch a.Stage {
+	case AppealProfessorReview:
+		return a.ProfessorID, true
+	case AppealHeadReview:
+		if a.DepartmentHeadID != nil {
+			return *a.DepartmentHeadID, true
+		}
+	}
+	return primitive.NilObjectID, false
+}
+
+type FileGradeAppealRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type DecideGradeAppealRequest struct {
+	Decision AppealDecision `json:"decision" binding:"required"`
+	Grade    int            `json:"grade,omitempty"` // the new grade when the decision is change
+	Note     string         `json:"note,omitempty"`
+}
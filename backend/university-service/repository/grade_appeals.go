@@ -0,0 +1,330 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrGradeAppealNotFound     = errors.New("grade appeal not found")
+	ErrGradeAppealExists       = errors.New("the grade has already been appealed")
+	ErrAppealWindowClosed      = errors.New("the appeal window for this grade has closed")
+	ErrInvalidAppealTransition = errors.New("invalid grade appeal transition")
+	ErrInvalidAppealDecision   = errors.New("invalid grade appeal decision")
+	ErrGradeAppealPending      = errors.New("the grade has an appeal under review")
+	ErrExamGradeNotFound       = errors.New("exam grade not found")
+)
+
+// GetGradeAppealPolicy returns the major's appeal deadlines, or the defaults if none are stored.
+func (r *Repository) GetGradeAppealPolicy(majorID primitive.ObjectID) (*GradeAppealPolicy, error) {
+	var policy GradeAppealPolicy
+	err := r.getCollection("grade_appeal_policies").FindOne(context.TODO(), bson.M{"major_id": majorID}).Decode(&policy)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &GradeAppealPolicy{MajorID: majorID, WindowHours: DefaultAppealWindowHours, ReviewHours: DefaultAppealReviewHours}, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetGradeAppealPolicy updates the given fields of the major's policy; omitted fields keep their value.
+func (r *Repository) SetGradeAppealPolicy(majorID primitive.ObjectID, req UpdateGradeAppealPolicyRequest) (*GradeAppealPolicy, error) {
+	policy, err := r.GetGradeAppealPolicy(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if req.WindowHours != nil {
+		if *req.WindowHours <= 0 {
+			return nil, fmt.Errorf("window_hours must be positive")
+		}
+		policy.WindowHours = *req.WindowHours
+	}
+	if req.ReviewHours != nil {
+		if *req.ReviewHours <= 0 {
+			return nil, fmt.Errorf("review_hours must be positive")
+		}
+		policy.ReviewHours = *req.ReviewHours
+	}
+	_, err = r.getCollection("grade_appeal_policies").UpdateOne(context.TODO(),
+		bson.M{"major_id": majorID},
+		bson.M{"$set": bson.M{
+			"window_hours": policy.WindowHours,
+			"review_hours": policy.ReviewHours,
+			"updated_at":   time.Now(),
+		}, "$setOnInsert": bson.M{"major_id": majorID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetGradeAppealPolicy(majorID)
+}
+
+// GetGradeAppealByID returns the appeal, or nil if it does not exist.
+func (r *Repository) GetGradeAppealByID(id primitive.ObjectID) (*GradeAppeal, error) {
+	var appeal GradeAppeal
+	err := r.getCollection("grade_appeals").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&appeal)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+func (r *Repository) findGradeAppeals(filter bson.M) ([]GradeAppeal, error) {
+	cursor, err := r.getCollection("grade_appeals").Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "filed_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	appeals := []GradeAppeal{}
+	if err := cursor.All(context.TODO(), &appeals); err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+// GetGradeAppealsByStudent returns every appeal the student has filed, newest first.
+func (r *Repository) GetGradeAppealsByStudent(studentID primitive.ObjectID) ([]GradeAppeal, error) {
+	return r.findGradeAppeals(bson.M{"student_id": studentID})
+}
+
+// GetGradeAppealsForReviewer returns the unresolved appeals waiting for the professor's decision,
+// either as the grading professor or as the head of the department.
+func (r *Repository) GetGradeAppealsForReviewer(professorID primitive.ObjectID) ([]GradeAppeal, error) {
+	return r.findGradeAppeals(bson.M{"$or": []bson.M{
+		{"stage": AppealProfessorReview, "professor_id": professorID},
+		{"stage": AppealHeadReview, "department_head_id": professorID},
+	}})
+}
+
+// HasPendingGradeAppeal reports whether the grade has an appeal that is still under review.
+func (r *Repository) HasPendingGradeAppeal(gradeID primitive.ObjectID) (bool, error) {
+	count, err := r.getCollection("grade_appeals").CountDocuments(context.TODO(), bson.M{
+		"exam_grade_id": gradeID,
+		"stage":         bson.M{"$in": []AppealStage{AppealProfessorReview, AppealHeadReview}},
+	})
+	return count > 0, err
+}
+
+// FileGradeAppeal opens an appeal against a grade. It must be filed within the major's window
+// after grading, and each grade can be appealed once.
+func (r *Repository) FileGradeAppeal(gradeID primitive.ObjectID, reason string) (*GradeAppeal, error) {
+	grade, err := r.GetExamGradeByID(gradeID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrExamGradeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	majorID := grade.Student.MajorID
+	subjectName := ""
+	if subject, err := r.GetSubjectByID(grade.SubjectId.Hex()); err == nil {
+		subjectName = subject.Name
+		if !subject.MajorID.IsZero() {
+			majorID = subject.MajorID
+		}
+	}
+	policy, err := r.GetGradeAppealPolicy(majorID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.After(grade.GradedAt.Add(policy.Window())) {
+		return nil, fmt.Errorf("%w: appeals had to be filed within %d hours of grading", ErrAppealWindowClosed, policy.WindowHours)
+	}
+	existing, err := r.getCollection("grade_appeals").CountDocuments(context.TODO(), bson.M{"exam_grade_id": gradeID})
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrGradeAppealExists
+	}
+
+	studentID := grade.Student.ID
+	deadline := now.Add(policy.ReviewPeriod())
+	appeal := &GradeAppeal{
+		ID:             primitive.NewObjectID(),
+		ExamGradeID:    grade.ID,
+		ExamSessionID:  grade.ExamSessionId,
+		SubjectID:      grade.SubjectId,
+		SubjectName:    subjectName,
+		MajorID:        majorID,
+		StudentID:      studentID,
+		ProfessorID:    grade.GradedBy.ID,
+		Reason:         reason,
+		OriginalGrade:  grade.Grade,
+		Stage:          AppealProfessorReview,
+		ReviewDeadline: &deadline,
+		FiledAt:        now,
+		History: []AppealEvent{{
+			Stage:   AppealProfessorReview,
+			ActorID: &studentID,
+			Grade:   grade.Grade,
+			Note:    reason,
+			At:      now,
+		}},
+	}
+	if _, err := r.getCollection("grade_appeals").InsertOne(context.TODO(), appeal); err != nil {
+		return nil, err
+	}
+	return appeal, nil
+}
+
+// transitionGradeAppeal moves an appeal out of its current stage. The stage is part of the update
+// filter, so two reviewers deciding at the same time cannot both apply.
+func (r *Repository) transitionGradeAppeal(appeal *GradeAppeal, to AppealStage, set bson.M, event AppealEvent) (*GradeAppeal, error) {
+	if set == nil {
+		set = bson.M{}
+	}
+	set["stage"] = to
+	event.Stage = to
+	event.At = time.Now()
+	result, err := r.getCollection("grade_appeals").UpdateOne(context.TODO(),
+		bson.M{"_id": appeal.ID, "stage": appeal.Stage},
+		bson.M{"$set": set, "$push": bson.M{"history": event}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("%w: the appeal was changed concurrently", ErrInvalidAppealTransition)
+	}
+	return r.GetGradeAppealByID(appeal.ID)
+}
+
+// departmentHead returns the head of the department the major belongs to, or nil if there is none.
+func (r *Repository) departmentHead(majorID primitive.ObjectID) (*primitive.ObjectID, error) {
+	major, err := r.GetMajorByID(majorID)
+	if err != nil || major == nil || major.DepartmentID == nil {
+		return nil, err
+	}
+	department, err := r.GetDepartmentByID(major.DepartmentID.Hex())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if department.Head.IsZero() {
+		return nil, nil
+	}
+	return &department.Head, nil
+}
+
+// EscalateGradeAppeal hands an appeal the professor has not resolved to the department head.
+// actorID is nil when the review period ran out.
+func (r *Repository) EscalateGradeAppeal(appeal *GradeAppeal, actorID *primitive.ObjectID, note string) (*GradeAppeal, error) {
+	if appeal.Stage != AppealProfessorReview {
+		return nil, fmt.Errorf("%w: only appeals with the professor can be escalated", ErrInvalidAppealTransition)
+	}
+	head, err := r.departmentHead(appeal.MajorID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := r.GetGradeAppealPolicy(appeal.MajorID)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{"review_deadline": time.Now().Add(policy.ReviewPeriod())}
+	if head != nil {
+		set["department_head_id"] = *head
+	}
+	return r.transitionGradeAppeal(appeal, AppealHeadReview, set, AppealEvent{ActorID: actorID, Note: note})
+}
+
+// ResolveGradeAppeal ends an appeal. Upholding keeps the grade; changing it rewrites the exam
+// grade and the subject on the student's record. The caller recomputes the student's GPA.
+func (r *Repository) ResolveGradeAppeal(appeal *GradeAppeal, actorID primitive.ObjectID, decision AppealDecision, newGrade int, note string) (*GradeAppeal, error) {
+	if appeal.Stage.IsFinal() {
+		return nil, fmt.Errorf("%w: the appeal is already %s", ErrInvalidAppealTransition, appeal.Stage)
+	}
+	now := time.Now()
+	set := bson.M{"resolved_at": now, "review_deadline": nil}
+
+	switch decision {
+	case AppealDecisionUphold:
+		set["final_grade"] = appeal.OriginalGrade
+		return r.transitionGradeAppeal(appeal, AppealUpheld, set, AppealEvent{ActorID: &actorID, Grade: appeal.OriginalGrade, Note: note})
+	case AppealDecisionChange:
+		if newGrade < 5 || newGrade > 10 {
+			return nil, fmt.Errorf("%w: grade must be between 5 and 10", ErrInvalidAppealDecision)
+		}
+		if newGrade == appeal.OriginalGrade {
+			return nil, fmt.Errorf("%w: the new grade is the same as the original; uphold the grade instead", ErrInvalidAppealDecision)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAppealDecision, decision)
+	}
+
+	// The grade is rewritten before the appeal is closed, so a Changed appeal always means the
+	// new grade is on record. If either step fails the previous grade is put back.
+	grade, err := r.GetExamGradeByID(appeal.ExamGradeID)
+	if err != nil {
+		return nil, err
+	}
+	previous := grade.Grade
+	if err := r.setAppealedGrade(grade, newGrade); err != nil {
+		r.restoreAppealedGrade(appeal, grade, previous)
+		return nil, err
+	}
+	set["final_grade"] = newGrade
+	resolved, err := r.transitionGradeAppeal(appeal, AppealChanged, set, AppealEvent{ActorID: &actorID, Grade: newGrade, Note: note})
+	if err != nil {
+		r.restoreAppealedGrade(appeal, grade, previous)
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// setAppealedGrade writes the grade to the exam grade and the subject on the student's record.
+func (r *Repository) setAppealedGrade(grade *ExamGrade, value int) error {
+	grade.Grade = value
+	grade.Passed = value >= 6
+	if err := r.UpdateExamGrade(grade); err != nil {
+		return err
+	}
+	return r.recordSubjectResult(grade)
+}
+
+// restoreAppealedGrade puts back the grade an unresolved appeal had already changed. A failure is logged.
+func (r *Repository) restoreAppealedGrade(appeal *GradeAppeal, grade *ExamGrade, previous int) {
+	if err := r.setAppealedGrade(grade, previous); err != nil {
+		r.logger.Printf("Failed to restore grade %d of exam grade %s after appeal %s was not resolved: %v",
+			previous, grade.ID.Hex(), appeal.ID.Hex(), err)
+	}
+}
+
+// EscalateOverdueGradeAppeals passes appeals the professor did not decide in time on to the
+// department head and returns them.
+func (r *Repository) EscalateOverdueGradeAppeals(now time.Time) ([]GradeAppeal, error) {
+	overdue, err := r.findGradeAppeals(bson.M{
+		"stage":           AppealProfessorReview,
+		"review_deadline": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+	escalated := []GradeAppeal{}
+	for i := range overdue {
+		appeal, err := r.EscalateGradeAppeal(&overdue[i], nil, "The professor did not decide within the review period")
+		if errors.Is(err, ErrInvalidAppealTransition) {
+			continue
+		}
+		if err != nil {
+			return escalated, err
+		}
+		escalated = append(escalated, *appeal)
+	}
+	return escalated, nil
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GradingSchemeTotalPoints is what the component maxima of a grading scheme must add up to.
+const GradingSchemeTotalPoints = 100
+
+// ComponentType is the kind of work a grade component measures.
+type ComponentType string
+
+const (
+	ComponentColloquium ComponentType = "colloquium"
+	ComponentLab        ComponentType = "lab"
+	ComponentProject    ComponentType = "project"
+	ComponentFinalExam  ComponentType = "final_exam" // the only component not counted as a pre-exam obligation
+)
+
+func (t ComponentType) IsValid() bool {
+	switch t {
+	case ComponentColloquium, ComponentLab, ComponentProject, ComponentFinalExam:
+		return true
+	}
+	return false
+}
+
+// GradeComponent is one graded part of a subject. Its maximum is also its weight in the final grade.
+type GradeComponent struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Type      ComponentType      `bson:"type" json:"type"`
+	MaxPoints float64            `bson:"max_points" json:"max_points"`
+	MinPoints float64            `bson:"min_points,omitempty" json:"min_points,omitempty"` // required in this component alone; 0 = none
+}
+
+// IsPreExam reports whether the component is a pre-exam obligation (predispitna obaveza).
+func (c *GradeComponent) IsPreExam() bool {
+	return c.Type != ComponentFinalExam
+}
+
+// GradingScheme splits a subject's 100 points into components. Students need MinPreExamPoints
+// from the pre-exam components before they can register for the exam.
+type GradingScheme struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	SubjectID        primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	Components       []GradeComponent   `bson:"components" json:"components"`
+	MinPreExamPoints float64            `bson:"min_pre_exam_points" json:"min_pre_exam_points"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Component returns the component with the given ID, or nil.
+func (s *GradingScheme) Component(id primitive.ObjectID) *GradeComponent {
+	for i := range s.Components {
+		if s.Components[i].ID == id {
+			return &s.Components[i]
+		}
+	}
+	return nil
+}
+
+// FinalExam returns the final exam component, or nil if the subject has none.
+func (s *GradingScheme) FinalExam() *GradeComponent {
+	for i := range s.Components {
+		if s.Components[i].Type == ComponentFinalExam {
+			return &s.Components[i]
+		}
+	}
+	return nil
+}
+
+type SetGradingSchemeRequest struct {
+	Components       []GradeComponent `json:"components" binding:"required"`
+	MinPreExamPoints float64          `json:"min_pre_exam_points"`
+}
+
+// ComponentPoints are the points a student earned in one component of a subject.
+type ComponentPoints struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SubjectID   primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	StudentID   primitive.ObjectID `bson:"student_id" json:"student_id"`
+	ComponentID primitive.ObjectID `bson:"component_id" json:"component_id"`
+	Points      float64            `bson:"points" json:"points"`
+	EnteredBy   primitive.ObjectID `bson:"entered_by,omitempty" json:"entered_by,omitempty"`
+	EnteredAt   time.Time          `bson:"entered_at" json:"entered_at"`
+}
+
+type RecordPointsRequest struct {
+	StudentID   primitive.ObjectID `json:"student_id" binding:"required"`
+	ComponentID primitive.ObjectID `json:"component_id" binding:"required"`
+	Points      float64            `json:"points"`
+}
+
+// ComponentResult is a component with the points the student has in it, if any were entered.
+type ComponentResult struct {
+	GradeComponent
+	Points *float64 `json:"points,omitempty"`
+}
+
+// StudentPoints is a student's standing in a subject's grading scheme.
+type StudentPoints struct {
+	SubjectID        primitive.ObjectID `json:"subject_id"`
+	StudentID        primitive.ObjectID `json:"student_id"`
+	Components       []ComponentResult  `json:"components"`
+	PreExamPoints    float64            `json:"pre_exam_points"`
+	MinPreExamPoints float64            `json:"min_pre_exam_points"`
+	PreExamMet       bool               `json:"pre_exam_met"`               // the student may register for the exam
+	PreExamMissing   []string           `json:"pre_exam_missing,omitempty"` // why PreExamMet is false
+	TotalPoints      float64            `json:"total_points"`
+	Grade            int                `json:"grade,omitempty"` // computed once final exam points are entered, or right away if the subject has no final exam
+}
+
+// GradeFromPoints converts total points to a grade using the standard bands:
+// 0-50 → 5, 51-60 → 6, 61-70 → 7, 71-80 → 8, 81-90 → 9, 91-100 → 10.
+func GradeFromPoints(points float64) int {
+	switch {
+	case points >= 91:
+		return 10
+	case points >= 81:
+		return 9
+	case points >= 71:
+		return 8
+	case points >= 61:
+		return 7
+	case points >= 51:
+		return 6
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradeFromPoints(t *testing.T) {
+	tests := []struct {
+		points float64
+		want   int
+	}{
+		{0, 5},
+		{50, 5},
+		{50.99, 5},
+		{51, 6},
+		{60, 6},
+		{61, 7},
+		{70.5, 7},
+		{71, 8},
+		{80, 8},
+		{81, 9},
+		{90, 9},
+		{91, 10},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.points), func(t *testing.T) {
+			assert.Equal(t, tt.want, GradeFromPoints(tt.points))
+		})
+	}
+}
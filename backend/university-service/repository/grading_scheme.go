@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrInvalidGradingScheme  = errors.New("invalid grading scheme")
+	ErrGradingSchemeNotFound = errors.New("the subject has no grading scheme")
+	ErrUnknownComponent      = errors.New("the subject has no such grade component")
+	ErrPointsOutOfRange      = errors.New("points are out of range for the component")
+)
+
+// GetGradingScheme returns the subject's grading scheme, or nil if it has none.
+func (r *Repository) GetGradingScheme(subjectID primitive.ObjectID) (*GradingScheme, error) {
+	var scheme GradingScheme
+	err := r.getCollection("grading_schemes").FindOne(context.TODO(), bson.M{"subject_id": subjectID}).Decode(&scheme)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+func validateGradingScheme(req *SetGradingSchemeRequest) error {
+	if len(req.Components) == 0 {
+		return fmt.Errorf("%w: at least one component is required", ErrInvalidGradingScheme)
+	}
+	total, preExamMax := 0.0, 0.0
+	finalExams := 0
+	seen := map[primitive.ObjectID]bool{}
+	for i := range req.Components {
+		component := &req.Components[i]
+		if component.Name == "" {
+			return fmt.Errorf("%w: component %d has no name", ErrInvalidGradingScheme, i+1)
+		}
+		if !component.Type.IsValid() {
+			return fmt.Errorf("%w: %s has unknown type %q", ErrInvalidGradingScheme, component.Name, component.Type)
+		}
+		if component.MaxPoints <= 0 {
+			return fmt.Errorf("%w: %s must be worth more than 0 points", ErrInvalidGradingScheme, component.Name)
+		}
+		if component.MinPoints < 0 || component.MinPoints > component.MaxPoints {
+			return fmt.Errorf("%w: the minimum for %s must be between 0 and %g", ErrInvalidGradingScheme, component.Name, component.MaxPoints)
+		}
+		// Components keep their IDs across edits so the points entered for them stay attached
+		if component.ID.IsZero() {
+			component.ID = primitive.NewObjectID()
+		}
+		if seen[component.ID] {
+			return fmt.Errorf("%w: component %s is listed more than once", ErrInvalidGradingScheme, component.ID.Hex())
+		}
+		seen[component.ID] = true
+
+		total += component.MaxPoints
+		if component.IsPreExam() {
+			preExamMax += component.MaxPoints
+		} else {
+			finalExams++
+		}
+	}
+	if math.Abs(total-GradingSchemeTotalPoints) > 1e-9 {
+		return fmt.Errorf("%w: component maxima add up to %g, they must add up to %d", ErrInvalidGradingScheme, total, GradingSchemeTotalPoints)
+	}
+	if finalExams > 1 {
+		return fmt.Errorf("%w: a subject can have only one final exam component", ErrInvalidGradingScheme)
+	}
+	if req.MinPreExamPoints < 0 || req.MinPreExamPoints > preExamMax {
+		return fmt.Errorf("%w: min_pre_exam_points must be between 0 and %g", ErrInvalidGradingScheme, preExamMax)
+	}
+	return nil
+}
+
+// SetGradingScheme replaces the subject's grading scheme.
+func (r *Repository) SetGradingScheme(subjectID primitive.ObjectID, req SetGradingSchemeRequest) (*GradingScheme, error) {
+	if err := validateGradingScheme(&req); err != nil {
+		return nil, err
+	}
+	_, err := r.getCollection("grading_schemes").UpdateOne(context.TODO(),
+		bson.M{"subject_id": subjectID},
+		bson.M{"$set": bson.M{
+			"components":          req.Components,
+			"min_pre_exam_points": req.MinPreExamPoints,
+			"updated_at":          time.Now(),
+		}, "$setOnInsert": bson.M{"subject_id": subjectID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetGradingScheme(subjectID)
+}
+
+// RecordComponentPoints stores a student's points in a component, replacing earlier points.
+func (r *Repository) RecordComponentPoints(subjectID, studentID, componentID primitive.ObjectID, points float64, enteredBy primitive.ObjectID) (*ComponentPoints, error) {
+	scheme, err := r.GetGradingScheme(subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == nil {
+		return nil, ErrGradingSchemeNotFound
+	}
+	component := scheme.Component(componentID)
+	if component == nil {
+		return nil, ErrUnknownComponent
+	}
+	if points < 0 || points > component.MaxPoints {
+		return nil, fmt.Errorf("%w: %s is worth 0 to %g points", ErrPointsOutOfRange, component.Name, component.MaxPoints)
+	}
+
+	entry := &ComponentPoints{}
+	err = r.getCollection("component_points").FindOneAndUpdate(context.TODO(),
+		bson.M{"subject_id": subjectID, "student_id": studentID, "component_id": componentID},
+		bson.M{"$set": bson.M{
+			"points":     points,
+			"entered_by": enteredBy,
+			"entered_at": time.Now(),
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// pointsSummary works out a student's pre-exam standing, total points and, once the final exam
+// is in, the resulting grade.
+func pointsSummary(scheme *GradingScheme, studentID primitive.ObjectID, entries []ComponentPoints) *StudentPoints {
+	byComponent := make(map[primitive.ObjectID]float64, len(entries))
+	for _, entry := range entries {
+		byComponent[entry.ComponentID] = entry.Points
+	}
+
+	summary := &StudentPoints{
+		SubjectID:        scheme.SubjectID,
+		StudentID:        studentID,
+		Components:       make([]ComponentResult, 0, len(scheme.Components)),
+		MinPreExamPoints: scheme.MinPreExamPoints,
+	}
+	finalEntered, finalMet := false, true
+	for _, component := range scheme.Components {
+		result := ComponentResult{GradeComponent: component}
+		points, entered := byComponent[component.ID]
+		if entered {
+			p := points
+			result.Points = &p
+			summary.TotalPoints += points
+		}
+		summary.Components = append(summary.Components, result)
+
+		if component.IsPreExam() {
+			summary.PreExamPoints += points
+			if component.MinPoints > 0 && points < component.MinPoints {
+				summary.PreExamMissing = append(summary.PreExamMissing,
+					fmt.Sprintf("%s: %g of the required %g points", component.Name, points, component.MinPoints))
+			}
+		} else {
+			finalEntered = entered
+			finalMet = points >= component.MinPoints
+		}
+	}
+	if summary.PreExamPoints < scheme.MinPreExamPoints {
+		summary.PreExamMissing = append(summary.PreExamMissing,
+			fmt.Sprintf("pre-exam obligations: %g of the required %g points", summary.PreExamPoints, scheme.MinPreExamPoints))
+	}
+	summary.PreExamMet = len(summary.PreExamMissing) == 0
+
+	if finalEntered || scheme.FinalExam() == nil {
+		summary.Grade = GradeFromPoints(summary.TotalPoints)
+		// Missing a component's minimum fails the subject regardless of the total
+		if !summary.PreExamMet || !finalMet {
+			summary.Grade = 5
+		}
+	}
+	return summary
+}
+
+// GetStudentPoints returns the student's points in the subject, or nil if the subject has no
+// grading scheme.
+func (r *Repository) GetStudentPoints(subjectID, studentID primitive.ObjectID) (*StudentPoints, error) {
+	scheme, err := r.GetGradingScheme(subjectID)
+	if err != nil || scheme == nil {
+		return nil, err
+	}
+	cursor, err := r.getCollection("component_points").Find(context.TODO(), bson.M{"subject_id": subjectID, "student_id": studentID})
+	if err != nil {
+		return nil, err
+	}
+	var entries []ComponentPoints
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+	return pointsSummary(scheme, studentID, entries), nil
+}
+
+// GetSubjectPoints returns the points of every student who has points entered in the subject.
+func (r *Repository) GetSubjectPoints(subjectID primitive.ObjectID) ([]StudentPoints, error) {
+	scheme, err := r.GetGradingScheme(subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == nil {
+		return nil, ErrGradingSchemeNotFound
+	}
+	cursor, err := r.getCollection("component_points").Find(context.TODO(), bson.M{"subject_id": subjectID})
+	if err != nil {
+		return nil, err
+	}
+	var entries []ComponentPoints
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+
+	byStudent := map[primitive.ObjectID][]ComponentPoints{}
+	order := []primitive.ObjectID{}
+	for _, entry := range entries {
+		if _, ok := byStudent[entry.StudentID]; !ok {
+			order = append(order, entry.StudentID)
+		}
+		byStudent[entry.StudentID] = append(byStudent[entry.StudentID], entry)
+	}
+	summaries := make([]StudentPoints, 0, len(order))
+	for _, studentID := range order {
+		summaries = append(summaries, *pointsSummary(scheme, studentID, byStudent[studentID]))
+	}
+	return summaries, nil
+}
+
+// GradeFromExamPoints records the student's final exam points and returns the grade that
+// follows from their total. It fails if the subject has no final exam component.
+func (r *Repository) GradeFromExamPoints(subjectID, studentID primitive.ObjectID, examPoints float64, enteredBy primitive.ObjectID) (*StudentPoints, error) {
+	scheme, err := r.GetGradingScheme(subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == nil {
+		return nil, ErrGradingSchemeNotFound
+	}
+	final := scheme.FinalExam()
+	if final == nil {
+		return nil, fmt.Errorf("%w: the grading scheme has no final exam", ErrUnknownComponent)
+	}
+	if _, err := r.RecordComponentPoints(subjectID, studentID, final.ID, examPoints, enteredBy); err != nil {
+		return nil, err
+	}
+	return r.GetStudentPoints(subjectID, studentID)
+}
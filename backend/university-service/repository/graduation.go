@@ -0,0 +1,289 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrGraduationRequestNotFound   = errors.New("graduation request not found")
+	ErrInvalidGraduationTransition = errors.New("invalid graduation step")
+	ErrGraduationRequestActive     = errors.New("student already has a graduation request in progress")
+	ErrInvalidThesis               = errors.New("invalid thesis")
+)
+
+func (r *Repository) GetGraduationRequestByID(id primitive.ObjectID) (*GraduationRequest, error) {
+	var request GraduationRequest
+	err := r.getCollection("graduation_requests").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&request)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// HasActiveGraduationRequest reports whether the student has a request that is neither rejected nor completed.
+func (r *Repository) HasActiveGraduationRequest(studentID primitive.ObjectID) (bool, error) {
+	requests, err := r.GetGraduationRequestsByStudentID(studentID)
+	if err != nil {
+		return false, err
+	}
+	for _, request := range requests {
+		if !request.CurrentStage().IsFinal() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// transitionGraduation moves a request to the next stage if it is currently in one of the allowed
+// stages. The stored stage is part of the update filter, so concurrent transitions cannot both apply.
+func (r *Repository) transitionGraduation(id primitive.ObjectID, allowed []GraduationStage, to GraduationStage, set bson.M, comment string) (*GraduationRequest, error) {
+	request, err := r.GetGraduationRequestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrGraduationRequestNotFound
+	}
+	current := request.CurrentStage()
+	permitted := false
+	for _, stage := range allowed {
+		if stage == current {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return nil, fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidGraduationTransition, current, to)
+	}
+
+	if set == nil {
+		set = bson.M{}
+	}
+	set["stage"] = to
+	set["status"] = to.Status()
+	if comment != "" {
+		set["comments"] = comment
+	}
+	filter := bson.M{"_id": id, "stage": request.Stage}
+	if request.Stage == "" {
+		filter["stage"] = bson.M{"$exists": false}
+	}
+	event := GraduationEvent{Stage: to, At: time.Now(), Comment: comment}
+	result, err := r.getCollection("graduation_requests").UpdateOne(context.TODO(), filter, bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": event},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("%w: the request was changed concurrently", ErrInvalidGraduationTransition)
+	}
+	return r.GetGraduationRequestByID(id)
+}
+
+// ReviewGraduationRequest is studentska služba's decision on a new request.
+func (r *Repository) ReviewGraduationRequest(id primitive.ObjectID, approve bool, comments string) (*GraduationRequest, error) {
+	to := GraduationRejected
+	if approve {
+		to = GraduationApproved
+	}
+	return r.transitionGraduation(id, []GraduationStage{GraduationRequested}, to, nil, comments)
+}
+
+// AssignThesis sets the thesis topic, the mentor and the committee. It can be repeated until a
+// defense is scheduled, and is used again after a failed defense.
+func (r *Repository) AssignThesis(id primitive.ObjectID, req AssignThesisRequest) (*GraduationRequest, error) {
+	if len(req.CommitteeIDs) < 2 {
+		return nil, fmt.Errorf("%w: the committee needs at least two members besides the mentor", ErrInvalidThesis)
+	}
+	mentor, err := r.thesisMember(req.MentorID)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[primitive.ObjectID]bool{req.MentorID: true}
+	committee := make([]ThesisMember, 0, len(req.CommitteeIDs))
+	for _, memberID := range req.CommitteeIDs {
+		if seen[memberID] {
+			return nil, fmt.Errorf("%w: professor %s is listed more than once", ErrInvalidThesis, memberID.Hex())
+		}
+		seen[memberID] = true
+		member, err := r.thesisMember(memberID)
+		if err != nil {
+			return nil, err
+		}
+		committee = append(committee, *member)
+	}
+
+	thesis := Thesis{Topic: req.Topic, Mentor: *mentor, Committee: committee}
+	return r.transitionGraduation(id,
+		[]GraduationStage{GraduationApproved, GraduationThesisAssigned},
+		GraduationThesisAssigned,
+		bson.M{"thesis": thesis},
+		"Thesis topic: "+req.Topic,
+	)
+}
+
+func (r *Repository) thesisMember(professorID primitive.ObjectID) (*ThesisMember, error) {
+	professor, err := r.GetProfessorByID(professorID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: professor %s not found", ErrInvalidThesis, professorID.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+	member := &ThesisMember{ID: professor.ID}
+	if professor.FirstName != nil && professor.LastName != nil {
+		member.Name = *professor.FirstName + " " + *professor.LastName
+	}
+	return member, nil
+}
+
+// ScheduleDefense sets or moves the defense date.
+func (r *Repository) ScheduleDefense(id primitive.ObjectID, date time.Time) (*GraduationRequest, error) {
+	if !date.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the defense date must be in the future", ErrInvalidThesis)
+	}
+	return r.transitionGraduation(id,
+		[]GraduationStage{GraduationThesisAssigned, GraduationDefenseScheduled},
+		GraduationDefenseScheduled,
+		bson.M{"thesis.defense_date": date},
+		"Defense scheduled for "+date.Format("02.01.2006. 15:04"),
+	)
+}
+
+// RecordDefense stores the defense grade. A failing grade (5) sends the request back to
+// thesis_assigned so a new defense can be scheduled.
+func (r *Repository) RecordDefense(id primitive.ObjectID, grade int, comments string) (*GraduationRequest, error) {
+	if grade < 5 || grade > 10 {
+		return nil, fmt.Errorf("%w: grade must be between 5 and 10", ErrInvalidThesis)
+	}
+	now := time.Now()
+	to := GraduationDefended
+	set := bson.M{"thesis.grade": grade, "thesis.defended_at": now}
+	if grade < 6 {
+		to = GraduationThesisAssigned
+		set = bson.M{"thesis.grade": grade}
+	}
+	if comments == "" {
+		comments = fmt.Sprintf("Defense graded %d", grade)
+	}
+	return r.transitionGraduation(id, []GraduationStage{GraduationDefenseScheduled}, to, set, comments)
+}
+
+// IssueDiploma creates the diploma for a defended thesis, marks the student as graduated and
+// completes the request.
+func (r *Repository) IssueDiploma(id primitive.ObjectID) (*Diploma, *GraduationRequest, error) {
+	request, err := r.GetGraduationRequestByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request == nil {
+		return nil, nil, ErrGraduationRequestNotFound
+	}
+	if request.CurrentStage() != GraduationDefended || request.Thesis == nil {
+		return nil, nil, fmt.Errorf("%w: the thesis has not been defended", ErrInvalidGraduationTransition)
+	}
+
+	student, err := r.GetStudentByIDObject(request.StudentID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil, ErrStudentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	gpa, espb, err := r.RecalculateStudentStanding(student.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	seq, err := r.nextSequence(fmt.Sprintf("diploma-%d", now.Year()))
+	if err != nil {
+		return nil, nil, err
+	}
+	diploma := &Diploma{
+		ID:                  primitive.NewObjectID(),
+		Number:              fmt.Sprintf("%d/%d", seq, now.Year()),
+		StudentID:           student.ID,
+		StudentName:         studentFullName(student),
+		GraduationRequestID: request.ID,
+		MajorID:             student.MajorID,
+		ThesisTopic:         request.Thesis.Topic,
+		ThesisGrade:         request.Thesis.Grade,
+		GPA:                 gpa,
+		ESPB:                espb,
+		IssuedAt:            now,
+	}
+	if major, err := r.GetMajorByID(student.MajorID); err == nil && major != nil {
+		diploma.MajorName = major.Name
+	}
+
+	// The diploma is stored and the student graduated before the request is completed, so a
+	// completed request always points at an existing diploma. Only the defended → completed
+	// transition is guarded against concurrent calls; the loser removes the diploma it stored.
+	// The winner graduates the student as well, so that update is not undone.
+	if _, err := r.getCollection("diplomas").InsertOne(context.TODO(), diploma); err != nil {
+		return nil, nil, err
+	}
+	if err := r.GraduateStudent(student.ID); err != nil {
+		r.deleteDiploma(diploma.ID)
+		return nil, nil, err
+	}
+	updated, err := r.transitionGraduation(id, []GraduationStage{GraduationDefended}, GraduationCompleted,
+		bson.M{"diploma_id": diploma.ID}, "Diploma "+diploma.Number+" issued")
+	if errors.Is(err, ErrInvalidGraduationTransition) || errors.Is(err, ErrGraduationRequestNotFound) {
+		r.deleteDiploma(diploma.ID)
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return diploma, updated, nil
+}
+
+// deleteDiploma removes a diploma whose request could not be completed. A failed delete is logged.
+func (r *Repository) deleteDiploma(id primitive.ObjectID) {
+	if _, err := r.getCollection("diplomas").DeleteOne(context.TODO(), bson.M{"_id": id}); err != nil {
+		r.logger.Printf("Failed to delete diploma %s of an uncompleted graduation request: %v", id.Hex(), err)
+	}
+}
+
+// GetDiplomaByStudentID returns the student's diploma, or nil if none was issued.
+func (r *Repository) GetDiplomaByStudentID(studentID primitive.ObjectID) (*Diploma, error) {
+	var diploma Diploma
+	opts := options.FindOne().SetSort(bson.D{{Key: "issued_at", Value: -1}})
+	err := r.getCollection("diplomas").FindOne(context.TODO(), bson.M{"student_id": studentID}, opts).Decode(&diploma)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &diploma, nil
+}
+
+// nextSequence atomically increments and returns the named counter, starting at 1.
+func (r *Repository) nextSequence(name string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.getCollection("counters").FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		opts,
+	).Decode(&counter)
+	return counter.Seq, err
+}
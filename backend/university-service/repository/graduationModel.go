@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GraduationStage is a step of the graduation workflow:
+// requested → approved (or rejected) → thesis_assigned → defense_scheduled → defended → graduated.
+type GraduationStage string
+
+const (
+	GraduationRequested        GraduationStage = "requested"
+	GraduationApproved         GraduationStage = "approved"
+	GraduationRejected         GraduationStage = "rejected"
+	GraduationThesisAssigned   GraduationStage = "thesis_assigned"
+	GraduationDefenseScheduled GraduationStage = "defense_scheduled"
+	GraduationDefended         GraduationStage = "defended"
+	GraduationCompleted        GraduationStage = "graduated"
+)
+
+// Status maps the stage to the review status kept for older clients.
+func (s GraduationStage) Status() Status {
+	switch s {
+	case GraduationRequested:
+		return Pending
+	case GraduationRejected:
+		return Rejected
+	default:
+		return Approved
+	}
+}
+
+// IsFinal reports whether the workflow has ended.
+func (s GraduationStage) IsFinal() bool {
+	return s == GraduationRejected || s == GraduationCompleted
+}
+
+// CurrentStage returns the request's stage. Requests created before the workflow existed
+// only have a Status, which is mapped to the matching stage.
+func (g *GraduationRequest) CurrentStage() GraduationStage {
+	if g.Stage != "" {
+		return g.Stage
+	}
+	switch g.Status {
+	case Approved:
+		return GraduationApproved
+	case Rejected:
+		return GraduationRejected
+	default:
+		return GraduationRequested
+	}
+}
+
+// ThesisMember is a professor taking part in a thesis, with the name kept for display.
+type ThesisMember struct {
+	ID   primitive.ObjectID `bson:"_id" json:"id"`
+	Name string             `bson:"name" json:"name"`
+}
+
+// Thesis is the final thesis of a graduation request.
+type Thesis struct {
+	Topic       string         `bson:"topic" json:"topic"`
+	Mentor      ThesisMember   `bson:"mentor" json:"mentor"`
+	Committee   []ThesisMember `bson:"committee" json:"committee"`
+	DefenseDate *time.Time     `bson:"defense_date,omitempty" json:"defense_date,omitempty"`
+	Grade       int            `bson:"grade,omitempty" json:"grade,omitempty"` // 5-10; 5 means the defense failed
+	DefendedAt  *time.Time     `bson:"defended_at,omitempty" json:"defended_at,omitempty"`
+}
+
+// GraduationEvent records one transition of a graduation request.
+type GraduationEvent struct {
+	Stage   GraduationStage `bson:"stage" json:"stage"`
+	At      time.Time       `bson:"at" json:"at"`
+	Comment string          `bson:"comment,omitempty" json:"comment,omitempty"`
+}
+
+// Diploma is issued when the workflow completes. Number is sequential per year, e.g. "42/2026".
+type Diploma struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Number              string             `bson:"number" json:"number"`
+	StudentID           primitive.ObjectID `bson:"student_id" json:"student_id"`
+	StudentName         string             `bson:"student_name" json:"student_name"`
+	GraduationRequestID primitive.ObjectID `bson:"graduation_request_id" json:"graduation_request_id"`
+	MajorID             primitive.ObjectID `bson:"major_id" json:"major_id"`
+	MajorName           string             `bson:"major_name" json:"major_name"`
+	ThesisTopic         string             `bson:"thesis_topic" json:"thesis_topic"`
+	ThesisGrade         int                `bson:"thesis_grade" json:"thesis_grade"`
+	GPA                 float64            `bson:"gpa" json:"gpa"`
+	ESPB                int                `bson:"espb" json:"espb"`
+	IssuedAt            time.Time          `bson:"issued_at" json:"issued_at"`
+}
+
+type ReviewGraduationRequest struct {
+	Approve  bool   `json:"approve"`
+	Comments string `json:"comments"`
+}
+
+type AssignThesisRequest struct {
+	Topic        string               `json:"topic" binding:"required"`
+	MentorID     primitive.ObjectID   `json:"mentor_id" binding:"required"`
+	CommitteeIDs []primitive.ObjectID `json:"committee_ids" binding:"required"`
+}
+
+type ScheduleDefenseRequest struct {
+	DefenseDate time.Time `json:"defense_date" binding:"required"`
+}
+
+type RecordDefenseRequest struct {
+	Grade    int    `json:"grade" binding:"required"`
+	Comments string `json:"comments"`
+}
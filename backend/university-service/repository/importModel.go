@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportKind is what kind of people an import file lists.
+type ImportKind string
+
+const (
+	ImportStudents   ImportKind = "students"
+	ImportProfessors ImportKind = "professors"
+	ImportAssistants ImportKind = "assistants"
+)
+
+// UserType is the auth-service user type the people of the import are registered as.
+func (k ImportKind) UserType() (UserType, bool) {
+	switch k {
+	case ImportStudents:
+		return StudentType, true
+	case ImportProfessors:
+		return ProfessorType, true
+	case ImportAssistants:
+		return AssistantType, true
+	}
+	return "", false
+}
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed" // the job stopped before processing every row
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowPending ImportRowStatus = "pending"
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowSkipped ImportRowStatus = "skipped" // the user already exists
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// ImportRow is one person of an import file and what happened to them.
+type ImportRow struct {
+	Row            int                `bson:"row" json:"row"` // line in the uploaded file
+	FirstName      string             `bson:"first_name" json:"first_name"`
+	LastName       string             `bson:"last_name" json:"last_name"`
+	Email          string             `bson:"email" json:"email"`
+	JMBG           string             `bson:"jmbg" json:"jmbg"`
+	Phone          string             `bson:"phone" json:"phone"`
+	Address        string             `bson:"address" json:"address"`
+	DateOfBirth    string             `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+	Major          string             `bson:"major,omitempty" json:"major,omitempty"` // students: major ID or name
+	MajorID        primitive.ObjectID `bson:"major_id,omitempty" json:"major_id,omitempty"`
+	Password       string             `bson:"-" json:"-"`
+	SealedPassword string             `bson:"sealed_password,omitempty" json:"-"` // generated password, encrypted; removed once the report is downloaded
+	Status         ImportRowStatus    `bson:"status" json:"status"`
+	UserID         string             `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// ImportJob is a bulk import of students or staff. It runs in the background; poll it for
+// progress and download the report once it has completed.
+type ImportJob struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind       ImportKind         `bson:"kind" json:"kind"`
+	FileName   string             `bson:"file_name" json:"file_name"`
+	Status     ImportStatus       `bson:"status" json:"status"`
+	Total      int                `bson:"total" json:"total"`
+	Processed  int                `bson:"processed" json:"processed"`
+	Created    int                `bson:"created" json:"created"`
+	Skipped    int                `bson:"skipped" json:"skipped"`
+	Failed     int                `bson:"failed" json:"failed"`
+	Progress   int                `bson:"-" json:"progress"` // percentage of rows processed
+	Rows       []ImportRow        `bson:"rows" json:"rows,omitempty"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedBy  primitive.ObjectID `bson:"started_by,omitempty" json:"started_by,omitempty"`
+	StartedAt  time.Time          `bson:"started_at" json:"started_at"`
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// BirthDate returns the validated date of birth, or nil if none was given.
+func (row *ImportRow) BirthDate() *time.Time {
+	date, err := time.Parse("2006-01-02", row.DateOfBirth)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
@@ -0,0 +1,357 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// importDateLayouts are the date formats accepted for the date of birth.
+var importDateLayouts = []string{"2006-01-02", "02.01.2006.", "02.01.2006", "2.1.2006."}
+
+// parseImportDate reads a date of birth, including the serial numbers spreadsheets store dates as.
+func parseImportDate(value string) (time.Time, error) {
+	if serial, err := strconv.Atoi(value); err == nil {
+		return time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, serial), nil
+	}
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date_of_birth %q is not a date like 2004-05-17 or 17.05.2004.", value)
+}
+
+// validateImportRow checks a row against what auth-service requires for registration and, for
+// students, resolves their major by ID or name.
+func validateImportRow(kind ImportKind, row *ImportRow, majors []Major) error {
+	for _, name := range []*string{&row.FirstName, &row.LastName} {
+		*name = strings.TrimSpace(*name)
+		if length := utf8.RuneCountInString(*name); length < 2 || length > 100 {
+			return fmt.Errorf("first_name and last_name must have between 2 and 100 characters")
+		}
+	}
+	row.Email = strings.ToLower(strings.TrimSpace(row.Email))
+	if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email {
+		return fmt.Errorf("email %q is not a valid email address", row.Email)
+	}
+	row.JMBG = strings.TrimSpace(row.JMBG)
+	if len(row.JMBG) != 13 || strings.Trim(row.JMBG, "0123456789") != "" {
+		return fmt.Errorf("jmbg must have exactly 13 digits")
+	}
+	if strings.TrimSpace(row.Phone) == "" || strings.TrimSpace(row.Address) == "" {
+		return fmt.Errorf("phone and address are required")
+	}
+	if row.Password != "" && len(row.Password) < 8 {
+		return fmt.Errorf("password must have at least 8 characters")
+	}
+	if row.DateOfBirth != "" {
+		date, err := parseImportDate(strings.TrimSpace(row.DateOfBirth))
+		if err != nil {
+			return err
+		}
+		row.DateOfBirth = date.Format("2006-01-02")
+	}
+
+	if kind != ImportStudents {
+		return nil
+	}
+	major := strings.TrimSpace(row.Major)
+	if major == "" {
+		return fmt.Errorf("major is required for students")
+	}
+	id, idErr := primitive.ObjectIDFromHex(major)
+	for _, m := range majors {
+		if (idErr == nil && m.ID == id) || strings.EqualFold(m.Name, major) {
+			row.MajorID = m.ID
+			return nil
+		}
+	}
+	return fmt.Errorf("major %q does not exist", major)
+}
+
+// CreateImportJob validates the rows and stores the job. Invalid rows and repeated emails are
+// marked failed straight away; the remaining rows are pending until the job processes them.
+func (r *Repository) CreateImportJob(kind ImportKind, fileName string, rows []ImportRow, startedBy primitive.ObjectID) (*ImportJob, error) {
+	if _, ok := kind.UserType(); !ok {
+		return nil, fmt.Errorf("%w: type must be %q, %q or %q", ErrInvalidImport, ImportStudents, ImportProfessors, ImportAssistants)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidImport)
+	}
+	var majors []Major
+	if kind == ImportStudents {
+		var err error
+		if majors, err = r.GetAllMajors(); err != nil {
+			return nil, err
+		}
+	}
+
+	job := &ImportJob{
+		ID:        primitive.NewObjectID(),
+		Kind:      kind,
+		FileName:  fileName,
+		Status:    ImportRunning,
+		Total:     len(rows),
+		Rows:      rows,
+		StartedBy: startedBy,
+		StartedAt: time.Now(),
+	}
+	emails := map[string]int{}
+	for i := range job.Rows {
+		row := &job.Rows[i]
+		row.Status = ImportRowPending
+		err := validateImportRow(kind, row, majors)
+		if err == nil {
+			if first, repeated := emails[row.Email]; repeated {
+				err = fmt.Errorf("%s is already listed in row %d", row.Email, first)
+			}
+			emails[row.Email] = row.Row
+		}
+		if err != nil {
+			row.Status = ImportRowFailed
+			row.Error = err.Error()
+			job.Processed++
+			job.Failed++
+		}
+	}
+	if _, err := r.getCollection("import_jobs").InsertOne(context.TODO(), job); err != nil {
+		return nil, err
+	}
+	job.Progress = job.Processed * 100 / job.Total
+	return job, nil
+}
+
+// RecordImportRow stores the outcome of the job's row at index and updates the job's counters.
+func (r *Repository) RecordImportRow(jobID primitive.ObjectID, index int, row ImportRow) error {
+	counter := map[ImportRowStatus]string{
+		ImportRowCreated: "created",
+		ImportRowSkipped: "skipped",
+		ImportRowFailed:  "failed",
+	}[row.Status]
+	if counter == "" {
+		return fmt.Errorf("%w: row %d has no outcome", ErrInvalidImport, row.Row)
+	}
+	_, err := r.getCollection("import_jobs").UpdateOne(context.TODO(),
+		bson.M{"_id": jobID},
+		bson.M{
+			"$set": bson.M{fmt.Sprintf("rows.%d", index): row},
+			"$inc": bson.M{"processed": 1, counter: 1},
+		},
+	)
+	return err
+}
+
+// FinishImportJob marks the job completed, or failed with the reason it stopped.
+func (r *Repository) FinishImportJob(jobID primitive.ObjectID, jobErr error) error {
+	set := bson.M{"status": ImportCompleted, "finished_at": time.Now()}
+	if jobErr != nil {
+		set["status"] = ImportFailed
+		set["error"] = jobErr.Error()
+	}
+	_, err := r.getCollection("import_jobs").UpdateOne(context.TODO(), bson.M{"_id": jobID}, bson.M{"$set": set})
+	return err
+}
+
+// interruptedImportRow is recorded on rows a job had not processed when the service stopped.
+const interruptedImportRow = "the import was interrupted by a service restart; import the file again to process this row (users already set up are skipped)"
+
+// FailInterruptedImportJobs marks jobs that were still running when the service stopped as failed,
+// along with their pending rows. Jobs only run in the process that started them, so a running job
+// found at startup will never finish on its own.
+func (r *Repository) FailInterruptedImportJobs() (int, error) {
+	jobs := r.getCollection("import_jobs")
+	cursor, err := jobs.Find(context.TODO(), bson.M{"status": ImportRunning})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+	var running []ImportJob
+	if err := cursor.All(context.TODO(), &running); err != nil {
+		return 0, err
+	}
+
+	for _, job := range running {
+		pending := 0
+		for _, row := range job.Rows {
+			if row.Status == ImportRowPending {
+				pending++
+			}
+		}
+		_, err := jobs.UpdateOne(context.TODO(),
+			bson.M{"_id": job.ID, "status": ImportRunning},
+			bson.M{
+				"$set": bson.M{
+					"status":                 ImportFailed,
+					"error":                  "the service restarted before the import finished",
+					"finished_at":            time.Now(),
+					"rows.$[pending].status": ImportRowFailed,
+					"rows.$[pending].error":  interruptedImportRow,
+				},
+				"$inc": bson.M{"processed": pending, "failed": pending},
+			},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"pending.status": ImportRowPending}},
+			}),
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(running), nil
+}
+
+// GetImportJob returns nil, nil when the job does not exist.
+func (r *Repository) GetImportJob(id primitive.ObjectID) (*ImportJob, error) {
+	var job ImportJob
+	err := r.getCollection("import_jobs").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if job.Total > 0 {
+		job.Progress = job.Processed * 100 / job.Total
+	}
+	return &job, nil
+}
+
+// TakeImportReport returns the job with the generated passwords of its processed rows and removes
+// them from the stored job, so each password is handed out only once. Returns nil, nil when the
+// job does not exist.
+func (r *Repository) TakeImportReport(id primitive.ObjectID) (*ImportJob, error) {
+	var job ImportJob
+	err := r.getCollection("import_jobs").FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": id},
+		bson.M{"$unset": bson.M{"rows.$[].sealed_password": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// PurgePlaintextImportPasswords removes the initial passwords that import jobs used to store
+// unencrypted.
+func (r *Repository) PurgePlaintextImportPasswords() error {
+	_, err := r.getCollection("import_jobs").UpdateMany(context.TODO(),
+		bson.M{"rows.initial_password": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"rows.$[].initial_password": ""}},
+	)
+	return err
+}
+
+// GetImportJobs returns all import jobs without their rows, newest first.
+func (r *Repository) GetImportJobs() ([]ImportJob, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetProjection(bson.M{"rows": 0})
+	cursor, err := r.getCollection("import_jobs").Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	jobs := []ImportJob{}
+	if err := cursor.All(context.TODO(), &jobs); err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if jobs[i].Total > 0 {
+			jobs[i].Progress = jobs[i].Processed * 100 / jobs[i].Total
+		}
+	}
+	return jobs, nil
+}
+
+// profileCollection is where university-service keeps the profiles of the import's people.
+func (k ImportKind) profileCollection() string {
+	switch k {
+	case ImportProfessors:
+		return "professor"
+	case ImportAssistants:
+		return "assistant"
+	}
+	return "student"
+}
+
+// ImportedProfileExists reports whether the profile of an imported user exists yet. auth-service
+// creates it in the background after registering the user.
+func (r *Repository) ImportedProfileExists(kind ImportKind, id primitive.ObjectID) (bool, error) {
+	count, err := r.getCollection(kind.profileCollection()).CountDocuments(context.TODO(), bson.M{"_id": id})
+	return count > 0, err
+}
+
+// FindImportedProfileID returns the ID of the profile with the given email, or nil if there is
+// none. Profiles have the ID of the user in auth-service.
+func (r *Repository) FindImportedProfileID(kind ImportKind, email string) (*primitive.ObjectID, error) {
+	var profile struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+	err := r.getCollection(kind.profileCollection()).FindOne(context.TODO(), bson.M{"user.email": email}, opts).Decode(&profile)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile.ID, nil
+}
+
+// ImportedProfileComplete reports whether an imported user's profile has everything the import
+// sets up: for students, registration for the row's major.
+func (r *Repository) ImportedProfileComplete(kind ImportKind, id primitive.ObjectID, row *ImportRow) (bool, error) {
+	filter := bson.M{"_id": id}
+	if kind == ImportStudents {
+		filter["major_id"] = row.MajorID
+	}
+	count, err := r.getCollection(kind.profileCollection()).CountDocuments(context.TODO(), filter)
+	return count > 0, err
+}
+
+// CreateImportedProfile creates the profile of an imported user that auth-service has not created.
+// A profile created in the meantime is left as it is.
+func (r *Repository) CreateImportedProfile(kind ImportKind, id primitive.ObjectID, row *ImportRow) error {
+	userType, _ := kind.UserType()
+	user := User{
+		FirstName: &row.FirstName,
+		LastName:  &row.LastName,
+		Email:     &row.Email,
+		Phone:     &row.Phone,
+		Address:   &row.Address,
+		JMBG:      row.JMBG,
+		UserType:  userType,
+	}
+	if date := row.BirthDate(); date != nil {
+		user.DateOfBirth = *date
+	}
+
+	var err error
+	switch kind {
+	case ImportProfessors:
+		err = r.CreateProfessor(&Professor{ID: id, User: user, Subjects: []Subject{}})
+	case ImportAssistants:
+		err = r.CreateAssistant(&Assistant{ID: id, User: user})
+	default:
+		err = r.CreateStudent(&Student{ID: id, User: user})
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
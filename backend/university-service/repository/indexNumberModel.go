@@ -0,0 +1,12 @@
+package repositories
+
+// DefaultIndexPattern is used for majors without an index pattern of their own.
+const DefaultIndexPattern = "{seq}/{year}"
+
+// SetIndexPatternRequest sets how the index numbers (brojevi indeksa) of a major's students are
+// formed. {seq} is the student's number among those enrolled in the major that year, {year} the
+// calendar year the academic year starts in and {yy} its last two digits, e.g. "SW-{seq}/{year}".
+// An empty pattern restores DefaultIndexPattern.
+type SetIndexPatternRequest struct {
+	IndexPattern string `json:"index_pattern"`
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidIndexPattern = errors.New("invalid index pattern")
+
+var indexPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// validateIndexPattern checks that the pattern numbers students by {seq} within a year and only
+// uses known placeholders.
+func validateIndexPattern(pattern string) error {
+	if strings.Count(pattern, "{seq}") != 1 {
+		return fmt.Errorf("%w: %q must contain {seq} exactly once", ErrInvalidIndexPattern, pattern)
+	}
+	if !strings.Contains(pattern, "{year}") && !strings.Contains(pattern, "{yy}") {
+		return fmt.Errorf("%w: %q must contain {year} or {yy}", ErrInvalidIndexPattern, pattern)
+	}
+	for _, placeholder := range indexPlaceholder.FindAllString(pattern, -1) {
+		if placeholder != "{seq}" && placeholder != "{year}" && placeholder != "{yy}" {
+			return fmt.Errorf("%w: unknown placeholder %s", ErrInvalidIndexPattern, placeholder)
+		}
+	}
+	if strings.TrimSpace(pattern) != pattern {
+		return fmt.Errorf("%w: %q has leading or trailing spaces", ErrInvalidIndexPattern, pattern)
+	}
+	return nil
+}
+
+// formatIndexNumber fills in the pattern for the seq-th student enrolled in the given year.
+func formatIndexNumber(pattern string, seq int64, year int) string {
+	return strings.NewReplacer(
+		"{seq}", strconv.FormatInt(seq, 10),
+		"{year}", strconv.Itoa(year),
+		"{yy}", fmt.Sprintf("%02d", year%100),
+	).Replace(pattern)
+}
+
+// SetIndexPattern sets how the index numbers of the major's students are formed. Index numbers
+// already assigned are not changed.
+func (r *Repository) SetIndexPattern(majorID primitive.ObjectID, pattern string) (*Major, error) {
+	major, err := r.GetMajorByID(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+	update := bson.M{"$unset": bson.M{"index_pattern": ""}}
+	if pattern != "" {
+		if err := validateIndexPattern(pattern); err != nil {
+			return nil, err
+		}
+		update = bson.M{"$set": bson.M{"index_pattern": pattern}}
+	}
+	if _, err := r.getCollection("majors").UpdateOne(context.TODO(), bson.M{"_id": majorID}, update); err != nil {
+		return nil, err
+	}
+	major.IndexPattern = pattern
+	return major, nil
+}
+
+// EnsureIndexNumberIndex creates the unique index that keeps two students from sharing an index
+// number. Students without one are left out of the index.
+func (r *Repository) EnsureIndexNumberIndex() error {
+	_, err := r.getCollection("student").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "index_number", Value: 1}},
+		Options: options.Index().
+			SetName("index_number_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"index_number": bson.M{"$gt": ""}}),
+	})
+	return err
+}
+
+// GetStudentByIndexNumber returns nil, nil when no student has the index number.
+func (r *Repository) GetStudentByIndexNumber(indexNumber string) (*Student, error) {
+	var student Student
+	err := r.getCollection("student").FindOne(context.TODO(), bson.M{"index_number": indexNumber}).Decode(&student)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &student, nil
+}
+
+// assignIndexNumber gives the student an index number following their major's pattern for the
+// academic year they first enroll in. Numbers are counted per major and year and skip any that
+// are already taken, e.g. by another major with the same pattern. A student who already has an
+// index number keeps it.
+func (r *Repository) assignIndexNumber(student *Student, major *Major, academicYear int) error {
+	if student.IndexNumber != "" {
+		return nil
+	}
+	pattern, counter := DefaultIndexPattern, fmt.Sprintf("index-%d", academicYear)
+	if major != nil {
+		counter = fmt.Sprintf("index-%s-%d", major.ID.Hex(), academicYear)
+		if major.IndexPattern != "" {
+			pattern = major.IndexPattern
+		}
+	}
+
+	for {
+		seq, err := r.nextSequence(counter)
+		if err != nil {
+			return err
+		}
+		number := formatIndexNumber(pattern, seq, academicYear)
+		taken, err := r.getCollection("student").CountDocuments(context.TODO(), bson.M{"index_number": number})
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			continue
+		}
+		result, err := r.getCollection("student").UpdateOne(context.TODO(),
+			bson.M{"_id": student.ID, "index_number": bson.M{"$in": bson.A{nil, ""}}},
+			bson.M{"$set": bson.M{"index_number": number}},
+		)
+		if mongo.IsDuplicateKeyError(err) {
+			// Taken by a concurrent enrollment between the check and the update
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			// Assigned in the meantime; keep the number the student already has
+			current, err := r.GetStudentByIDObject(student.ID)
+			if err != nil {
+				return err
+			}
+			student.IndexNumber = current.IndexNumber
+			return nil
+		}
+		student.IndexNumber = number
+		return nil
+	}
+}
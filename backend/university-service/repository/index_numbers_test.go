@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatIndexNumber(t *testing.T) {
+	tests := []struct {
+		pattern string
+		seq     int64
+		year    int
+		want    string
+	}{
+		{DefaultIndexPattern, 12, 2025, "12/2025"},
+		{"SW-{seq}/{year}", 7, 2024, "SW-7/2024"},
+		{"IT{yy}-{seq}", 130, 2025, "IT25-130"},
+		{"{seq}/{yy}", 1, 2009, "1/09"},
+		{"RA {seq}/{year}", 1000, 2030, "RA 1000/2030"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatIndexNumber(tt.pattern, tt.seq, tt.year))
+		})
+	}
+}
+
+func TestValidateIndexPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{DefaultIndexPattern, true},
+		{"SW-{seq}/{year}", true},
+		{"IT{yy}-{seq}", true},
+		{"SW-{year}", false},               // no {seq}
+		{"{seq}-{seq}/{year}", false},      // {seq} twice
+		{"SW-{seq}", false},                // no year
+		{"SW-{seq}/{month}/{year}", false}, // unknown placeholder
+		{" {seq}/{year}", false},           // leading space
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := validateIndexPattern(tt.pattern)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidIndexPattern), "got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrInvalidLedgerEntry    = errors.New("invalid ledger entry")
+	ErrTuitionAlreadyCharged = errors.New("tuition for this academic year has already been charged")
+)
+
+// PostLedgerEntry records a charge, payment or refund on the student's account.
+func (r *Repository) PostLedgerEntry(entry *LedgerEntry) error {
+	if !entry.Type.IsValid() {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidLedgerEntry, entry.Type)
+	}
+	if entry.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidLedgerEntry)
+	}
+	if entry.StudentID.IsZero() {
+		return fmt.Errorf("%w: student_id is required", ErrInvalidLedgerEntry)
+	}
+	entry.ID = primitive.NewObjectID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	_, err := r.getCollection("ledger").InsertOne(context.TODO(), entry)
+	return err
+}
+
+// GetStudentAccount returns the student's ledger in posting order, each entry with the balance after it.
+// Entries dated after now are returned as upcoming.
+func (r *Repository) GetStudentAccount(studentID primitive.ObjectID) (*StudentAccount, error) {
+	now := time.Now()
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.getCollection("ledger").Find(context.TODO(), bson.M{"student_id": studentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	entries := []LedgerEntry{}
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+
+	account := &StudentAccount{StudentID: studentID, Entries: []LedgerEntry{}, Upcoming: []LedgerEntry{}}
+	for _, entry := range entries {
+		if entry.CreatedAt.After(now) {
+			account.Upcoming = append(account.Upcoming, entry)
+		} else {
+			account.Entries = append(account.Entries, entry)
+		}
+	}
+	for i := range account.Entries {
+		entry := &account.Entries[i]
+		switch {
+		case entry.Type.IsCharge():
+			account.TotalCharged += entry.Amount
+		case entry.Type == LedgerRefund:
+			account.TotalRefunded += entry.Amount
+		default:
+			account.TotalPaid += entry.Amount
+		}
+		account.Balance += entry.SignedAmount()
+		entry.BalanceAfter = account.Balance
+	}
+	return account, nil
+}
+
+// GetStudentBalance returns the student's current balance. Negative means the student owes money.
+func (r *Repository) GetStudentBalance(studentID primitive.ObjectID) (float64, error) {
+	account, err := r.GetStudentAccount(studentID)
+	if err != nil {
+		return 0, err
+	}
+	return account.Balance, nil
+}
+
+// OutstandingTuition returns how much of the tuition due for the academic year by now is still
+// unpaid. Only payments made towards that year's tuition count; other payments settle other charges.
+func (r *Repository) OutstandingTuition(studentID primitive.ObjectID, academicYear int, now time.Time) (float64, error) {
+	cursor, err := r.getCollection("ledger").Find(context.TODO(), bson.M{
+		"student_id":    studentID,
+		"academic_year": academicYear,
+		"type":          bson.M{"$in": []LedgerEntryType{LedgerTuitionCharge, LedgerPayment}},
+		"created_at":    bson.M{"$lte": now},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+	var entries []LedgerEntry
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return 0, err
+	}
+
+	var outstanding float64
+	for i := range entries {
+		outstanding -= entries[i].SignedAmount()
+	}
+	return outstanding, nil
+}
+
+// SetMajorTuition sets the tuition charged to the major's self-financed students when they enroll.
+func (r *Repository) SetMajorTuition(majorID primitive.ObjectID, fee float64, instalments int) (*Major, error) {
+	if fee < 0 {
+		return nil, fmt.Errorf("%w: tuition_fee cannot be negative", ErrInvalidLedgerEntry)
+	}
+	if instalments < 0 || instalments > 12 {
+		return nil, fmt.Errorf("%w: tuition_instalments must be between 0 and 12", ErrInvalidLedgerEntry)
+	}
+	major, err := r.GetMajorByID(majorID)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+	_, err = r.getCollection("majors").UpdateOne(context.TODO(),
+		bson.M{"_id": majorID},
+		bson.M{"$set": bson.M{"tuition_fee": fee, "tuition_instalments": instalments}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	major.TuitionFee = fee
+	major.TuitionInstalments = instalments
+	return major, nil
+}
+
+// ChargeTuition posts the tuition of a self-financed enrollment as equal instalments. The first one is
+// due on enrollment and the rest are spread evenly over the following year. Tuition can be charged
+// once per academic year.
+func (r *Repository) ChargeTuition(enrollment *Enrollment, amount float64, instalments int) ([]LedgerEntry, error) {
+	if enrollment.Financing != FinancingSelfFinanced {
+		return nil, fmt.Errorf("%w: the enrollment is not self-financed", ErrInvalidLedgerEntry)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: tuition amount must be greater than 0", ErrInvalidLedgerEntry)
+	}
+	if instalments < 1 {
+		instalments = 1
+	}
+	if instalments > 12 {
+		return nil, fmt.Errorf("%w: tuition can be split into at most 12 instalments", ErrInvalidLedgerEntry)
+	}
+	charged, err := r.getCollection("ledger").CountDocuments(context.TODO(), bson.M{
+		"student_id":    enrollment.StudentID,
+		"type":          LedgerTuitionCharge,
+		"academic_year": enrollment.AcademicYear,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if charged > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTuitionAlreadyCharged, formatAcademicYear(enrollment.AcademicYear))
+	}
+
+	entries := make([]LedgerEntry, 0, instalments)
+	amounts := splitInstalments(amount, instalments)
+	for i, instalment := range amounts {
+		entry := LedgerEntry{
+			StudentID:    enrollment.StudentID,
+			Type:         LedgerTuitionCharge,
+			Amount:       instalment,
+			Description:  fmt.Sprintf("Tuition %s, instalment %d of %d", formatAcademicYear(enrollment.AcademicYear), i+1, instalments),
+			AcademicYear: enrollment.AcademicYear,
+			CreatedAt:    enrollment.EnrolledAt.AddDate(0, i*12/instalments, 0),
+		}
+		if err := r.PostLedgerEntry(&entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// splitInstalments splits amount into n parts rounded to cents; the last part takes the remainder.
+func splitInstalments(amount float64, n int) []float64 {
+	cents := int64(math.Round(amount * 100))
+	part := cents / int64(n)
+	parts := make([]float64, n)
+	for i := range parts {
+		c := part
+		if i == n-1 {
+			c = cents - part*int64(n-1)
+		}
+		parts[i] = float64(c) / 100
+	}
+	return parts
+}
+
+// MigrateTuitionPayments copies payments from the old tuitionPayments collection into the ledger as
+// tuition payments of the academic year they were made in. Ledger entries keep the payment's ID, so
+// running it again does not post a payment twice.
+func (r *Repository) MigrateTuitionPayments() (int, error) {
+	cursor, err := r.getCollection("tuitionPayments").Find(context.TODO(), bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+	var payments []TuitionPayment
+	if err := cursor.All(context.TODO(), &payments); err != nil {
+		return 0, err
+	}
+
+	ledger := r.getCollection("ledger")
+	migrated := 0
+	for _, payment := range payments {
+		if payment.ID.IsZero() || payment.Amount <= 0 {
+			continue
+		}
+		academicYear, err := r.CurrentAcademicYear(payment.Date)
+		if err != nil {
+			return migrated, err
+		}
+		entry := LedgerEntry{
+			ID:           payment.ID,
+			StudentID:    payment.StudentID,
+			Type:         LedgerPayment,
+			Amount:       payment.Amount,
+			Description:  "Tuition payment " + formatAcademicYear(academicYear),
+			AcademicYear: academicYear,
+			CreatedAt:    payment.Date,
+		}
+		result, err := ledger.UpdateOne(context.TODO(),
+			bson.M{"_id": payment.ID},
+			bson.M{"$setOnInsert": entry},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return migrated, err
+		}
+		if result.UpsertedCount > 0 {
+			migrated++
+		}
+	}
+	return migrated, nil
+}
+
+// chargeExamFee posts the session's registration fee, if it has one.
+func (r *Repository) chargeExamFee(studentID primitive.ObjectID, examSession *ExamSession) error {
+	if examSession.RegistrationFee <= 0 {
+		return nil
+	}
+	return r.PostLedgerEntry(&LedgerEntry{
+		StudentID:     studentID,
+		Type:          LedgerExamFee,
+		Amount:        examSession.RegistrationFee,
+		Description:   "Exam registration fee: " + examSession.Subject.Name,
+		ExamSessionID: &examSession.ID,
+	})
+}
+
+// refundExamFee returns the registration fee when the student deregisters at least
+// ExamFeeRefundDeadline before the exam. Returns the refund entry, or nil when nothing is refunded.
+func (r *Repository) refundExamFee(studentID primitive.ObjectID, examSession *ExamSession, now time.Time) (*LedgerEntry, error) {
+	if now.After(examSession.ExamDate.Add(-ExamFeeRefundDeadline)) {
+		return nil, nil
+	}
+	ledger := r.getCollection("ledger")
+	filter := bson.M{"student_id": studentID, "exam_session_id": examSession.ID}
+
+	// Fees and refunds for a session alternate (register, deregister, register again...),
+	// so there is an outstanding fee when there are more fees than refunds
+	var charged, refunded float64
+	cursor, err := ledger.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var entries []LedgerEntry
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		switch e.Type {
+		case LedgerExamFee:
+			charged += e.Amount
+		case LedgerRefund:
+			refunded += e.Amount
+		}
+	}
+	if charged-refunded <= 0 {
+		return nil, nil
+	}
+
+	refund := &LedgerEntry{
+		StudentID:     studentID,
+		Type:          LedgerRefund,
+		Amount:        charged - refunded,
+		Description:   "Refund of exam registration fee: " + examSession.Subject.Name,
+		ExamSessionID: &examSession.ID,
+	}
+	if err := r.PostLedgerEntry(refund); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExamFeeRefundDeadline is how long before the exam a student can deregister and still get the fee back.
+const ExamFeeRefundDeadline = 72 * time.Hour
+
+// LedgerEntryType is the kind of a ledger entry. Charges lower the balance, payments and refunds raise it.
+type LedgerEntryType string
+
+const (
+	LedgerTuitionCharge LedgerEntryType = "tuition_charge" // tuition instalment
+	LedgerExamFee       LedgerEntryType = "exam_fee"       // exam registration fee
+	LedgerPayment       LedgerEntryType = "payment"
+	LedgerRefund        LedgerEntryType = "refund"
+)
+
+// IsValid reports whether t is a known entry type.
+func (t LedgerEntryType) IsValid() bool {
+	switch t {
+	case LedgerTuitionCharge, LedgerExamFee, LedgerPayment, LedgerRefund:
+		return true
+	}
+	return false
+}
+
+// IsCharge reports whether the entry is owed by the student.
+func (t LedgerEntryType) IsCharge() bool {
+	return t == LedgerTuitionCharge || t == LedgerExamFee
+}
+
+// LedgerEntry is one posting on a student's account. Amount is always positive; the type gives the sign.
+// Tuition instalments are posted up front with their due date as CreatedAt and only count once due.
+type LedgerEntry struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	StudentID     primitive.ObjectID  `bson:"student_id" json:"student_id"`
+	Type          LedgerEntryType     `bson:"type" json:"type"`
+	Amount        float64             `bson:"amount" json:"amount"`
+	Description   string              `bson:"description" json:"description"`
+	ExamSessionID *primitive.ObjectID `bson:"exam_session_id,omitempty" json:"exam_session_id,omitempty"`
+	AcademicYear  int                 `bson:"academic_year,omitempty" json:"academic_year,omitempty"` // set on tuition charges and tuition payments
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	BalanceAfter  float64             `bson:"-" json:"balance_after"` // running balance, computed when the ledger is read
+}
+
+// SignedAmount is the entry's effect on the balance.
+func (e *LedgerEntry) SignedAmount() float64 {
+	if e.Type.IsCharge() {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+// StudentAccount is a student's ledger with totals. A negative balance means the student owes money.
+// Upcoming holds entries that are not due yet; they are not part of the balance or the totals.
+type StudentAccount struct {
+	StudentID     primitive.ObjectID `json:"student_id"`
+	Balance       float64            `json:"balance"`
+	TotalCharged  float64            `json:"total_charged"`
+	TotalPaid     float64            `json:"total_paid"`
+	TotalRefunded float64            `json:"total_refunded"`
+	Entries       []LedgerEntry      `json:"entries"`
+	Upcoming      []LedgerEntry      `json:"upcoming"`
+}
+
+type CreateLedgerEntryRequest struct {
+	Type          LedgerEntryType     `json:"type" binding:"required"`
+	Amount        float64             `json:"amount" binding:"required"`
+	Description   string              `json:"description"`
+	ExamSessionID *primitive.ObjectID `json:"exam_session_id,omitempty"`
+	AcademicYear  int                 `json:"academic_year,omitempty"` // tuition charges default to the current academic year
+}
+
+// ChargeTuitionRequest is the payload for charging an enrollment's tuition. Both fields default to
+// the major's tuition settings.
+type ChargeTuitionRequest struct {
+	Amount      float64 `json:"amount"`
+	Instalments int     `json:"instalments"`
+}
+
+// SetTuitionRequest sets the yearly tuition of a major's self-financed students. A fee of 0 means
+// the major charges no tuition.
+type SetTuitionRequest struct {
+	TuitionFee         float64 `json:"tuition_fee"`
+	TuitionInstalments int     `json:"tuition_instalments"`
+}
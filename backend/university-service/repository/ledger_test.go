@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitInstalments(t *testing.T) {
+	tests := []struct {
+		amount float64
+		n      int
+		want   []float64
+	}{
+		{120000, 1, []float64{120000}},
+		{120000, 4, []float64{30000, 30000, 30000, 30000}},
+		{100, 3, []float64{33.33, 33.33, 33.34}},
+		{0.05, 2, []float64{0.02, 0.03}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v in %d", tt.amount, tt.n), func(t *testing.T) {
+			got := splitInstalments(tt.amount, tt.n)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLedgerEntrySignedAmount(t *testing.T) {
+	tests := []struct {
+		entryType LedgerEntryType
+		want      float64
+	}{
+		{LedgerTuitionCharge, -100},
+		{LedgerExamFee, -100},
+		{LedgerPayment, 100},
+		{LedgerRefund, 100},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.entryType), func(t *testing.T) {
+			entry := LedgerEntry{Type: tt.entryType, Amount: 100}
+			assert.Equal(t, tt.want, entry.SignedAmount())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// FilterKind is how the value of a list filter is parsed.
+type FilterKind int
+
+const (
+	StringFilter FilterKind = iota
+	ObjectIDFilter
+	IntFilter
+	BoolFilter
+	DateFilter       // a range given by <param>_from and <param>_to, both inclusive
+	DepartmentFilter // a department ID, matching the IDs the filter's In returns for that department
+)
+
+// ListFilter is a query parameter a list can be filtered by.
+type ListFilter struct {
+	Param string
+	Field string // document field, e.g. "major_id" or "subject.major_id"
+	Kind  FilterKind
+	In    func(*Department) []primitive.ObjectID // DepartmentFilter: the department's IDs Field is matched against
+}
+
+// ListSpec is what a list endpoint can be filtered and sorted by. Sorts maps the values of the
+// sort parameter to the fields they order by; a leading "-" reverses the order.
+type ListSpec struct {
+	Filters     []ListFilter
+	Sorts       map[string][]string
+	DefaultSort string
+}
+
+// ListQuery selects the documents matching Filter in Sort order, one page of Limit documents
+// at a time. Page 0 selects all of them.
+type ListQuery struct {
+	Filter bson.M
+	Sort   bson.D
+	Page   int
+	Limit  int
+}
+
+// ListPage is a page of a list together with how many documents match the query in total.
+type ListPage struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Pages int64       `json:"pages"`
+}
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// parseListDate reads a date filter bound. A bare date as the upper bound includes that whole day.
+func parseListDate(value string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is not a date like 2025-01-31 or 2025-01-31T09:00:00Z", ErrInvalidListQuery, value)
+	}
+	if upper {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// filterValue parses the value of a filter other than a date range.
+func (r *Repository) filterValue(filter ListFilter, value string) (interface{}, error) {
+	switch filter.Kind {
+	case ObjectIDFilter:
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be an ID", ErrInvalidListQuery, filter.Param)
+		}
+		return id, nil
+	case IntFilter:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidListQuery, filter.Param)
+		}
+		return n, nil
+	case BoolFilter:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidListQuery, filter.Param)
+		}
+		return b, nil
+	case DepartmentFilter:
+		if _, err := primitive.ObjectIDFromHex(value); err != nil {
+			return nil, fmt.Errorf("%w: %s must be an ID", ErrInvalidListQuery, filter.Param)
+		}
+		department, err := r.GetDepartmentByID(value)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: department %s does not exist", ErrInvalidListQuery, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids := filter.In(department)
+		if ids == nil {
+			ids = []primitive.ObjectID{}
+		}
+		return bson.M{"$in": ids}, nil
+	}
+	return value, nil
+}
+
+// ParseListQuery reads the page, limit, sort and filters of a list from its query parameters,
+// e.g. ?page=2&limit=50&sort=-date&status=scheduled&date_from=2025-01-01. Without page or limit
+// the query selects everything.
+func (r *Repository) ParseListQuery(values url.Values, spec ListSpec) (ListQuery, error) {
+	q := ListQuery{Filter: bson.M{}}
+
+	for _, name := range []string{"page", "limit"} {
+		if values.Get(name) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(values.Get(name))
+		if err != nil || n < 1 {
+			return q, fmt.Errorf("%w: %s must be a positive number", ErrInvalidListQuery, name)
+		}
+		if name == "page" {
+			q.Page = n
+		} else {
+			q.Limit = n
+		}
+	}
+	if q.Page > 0 || q.Limit > 0 {
+		if q.Page == 0 {
+			q.Page = 1
+		}
+		if q.Limit == 0 {
+			q.Limit = DefaultListLimit
+		}
+		if q.Limit > MaxListLimit {
+			return q, fmt.Errorf("%w: limit cannot be more than %d", ErrInvalidListQuery, MaxListLimit)
+		}
+	}
+
+	sortBy := values.Get("sort")
+	if sortBy == "" {
+		sortBy = spec.DefaultSort
+	}
+	order := 1
+	if strings.HasPrefix(sortBy, "-") {
+		sortBy, order = sortBy[1:], -1
+	}
+	if sortBy != "" {
+		fields, ok := spec.Sorts[sortBy]
+		if !ok {
+			allowed := make([]string, 0, len(spec.Sorts))
+			for name := range spec.Sorts {
+				allowed = append(allowed, name)
+			}
+			slices.Sort(allowed)
+			return q, fmt.Errorf("%w: cannot sort by %q, only by %s", ErrInvalidListQuery, sortBy, strings.Join(allowed, ", "))
+		}
+		for _, field := range fields {
+			q.Sort = append(q.Sort, bson.E{Key: field, Value: order})
+		}
+	}
+	// _id keeps the order of equal documents the same from page to page
+	q.Sort = append(q.Sort, bson.E{Key: "_id", Value: order})
+
+	for _, filter := range spec.Filters {
+		if filter.Kind == DateFilter {
+			between := bson.M{}
+			for suffix, operator := range map[string]string{"_from": "$gte", "_to": "$lte"} {
+				value := values.Get(filter.Param + suffix)
+				if value == "" {
+					continue
+				}
+				t, err := parseListDate(value, suffix == "_to")
+				if err != nil {
+					return q, err
+				}
+				between[operator] = t
+			}
+			if len(between) > 0 {
+				q.Filter[filter.Field] = between
+			}
+			continue
+		}
+		value := values.Get(filter.Param)
+		if value == "" {
+			continue
+		}
+		parsed, err := r.filterValue(filter, value)
+		if err != nil {
+			return q, err
+		}
+		q.Filter[filter.Field] = parsed
+	}
+	return q, nil
+}
+
+// findList decodes the documents of the collection selected by the query into results and
+// returns how many documents match the query's filter in total.
+func (r *Repository) findList(collection string, q ListQuery, results interface{}) (int64, error) {
+	filter := q.Filter
+	if filter == nil {
+		filter = bson.M{}
+	}
+	coll := r.getCollection(collection)
+	total, err := coll.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	opts := options.Find().SetSort(q.Sort)
+	if q.Page > 0 {
+		opts.SetSkip(int64((q.Page - 1) * q.Limit)).SetLimit(int64(q.Limit))
+	}
+	cursor, err := coll.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+	if err := cursor.All(context.TODO(), results); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// NewListPage wraps a page of items selected by the query.
+func NewListPage(q ListQuery, items interface{}, total int64) ListPage {
+	page := ListPage{Items: items, Total: total, Page: q.Page, Limit: q.Limit}
+	if q.Limit > 0 {
+		page.Pages = (total + int64(q.Limit) - 1) / int64(q.Limit)
+	}
+	return page
+}
@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "status", Field: "status", Kind: StringFilter},
+		{Param: "major_id", Field: "subject.major_id", Kind: ObjectIDFilter},
+		{Param: "year", Field: "year", Kind: IntFilter},
+		{Param: "graduated", Field: "graduated", Kind: BoolFilter},
+		{Param: "date", Field: "exam_date", Kind: DateFilter},
+	},
+	Sorts: map[string][]string{
+		"date": {"exam_date"},
+		"name": {"last_name", "first_name"},
+	},
+	DefaultSort: "date",
+}
+
+func TestParseListQuery(t *testing.T) {
+	majorID := primitive.NewObjectID()
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, time.January, 31, 23, 59, 59, 999999999, time.Local)
+
+	tests := []struct {
+		name  string
+		query string
+		want  ListQuery
+	}{
+		{
+			name:  "no parameters selects everything in the default order",
+			query: "",
+			want: ListQuery{
+				Filter: bson.M{},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		},
+		{
+			name:  "page without limit uses the default limit",
+			query: "page=3",
+			want: ListQuery{
+				Filter: bson.M{},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+				Page:   3,
+				Limit:  DefaultListLimit,
+			},
+		},
+		{
+			name:  "limit without page starts at the first page",
+			query: "limit=50",
+			want: ListQuery{
+				Filter: bson.M{},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+				Page:   1,
+				Limit:  50,
+			},
+		},
+		{
+			name:  "descending sort on several fields",
+			query: "sort=-name",
+			want: ListQuery{
+				Filter: bson.M{},
+				Sort:   bson.D{{Key: "last_name", Value: -1}, {Key: "first_name", Value: -1}, {Key: "_id", Value: -1}},
+			},
+		},
+		{
+			name:  "filters are parsed by kind",
+			query: "status=scheduled&major_id=" + majorID.Hex() + "&year=2&graduated=false",
+			want: ListQuery{
+				Filter: bson.M{"status": "scheduled", "subject.major_id": majorID, "year": 2, "graduated": false},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		},
+		{
+			name:  "date range includes the whole last day",
+			query: "date_from=2025-01-01&date_to=2025-01-31",
+			want: ListQuery{
+				Filter: bson.M{"exam_date": bson.M{"$gte": from, "$lte": to}},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		},
+		{
+			name:  "open-ended date range",
+			query: "date_from=2025-01-01",
+			want: ListQuery{
+				Filter: bson.M{"exam_date": bson.M{"$gte": from}},
+				Sort:   bson.D{{Key: "exam_date", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		},
+	}
+	r := &Repository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			q, err := r.ParseListQuery(values, testListSpec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q)
+		})
+	}
+}
+
+func TestParseListQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"page is not a number", "page=two"},
+		{"page is zero", "page=0"},
+		{"negative limit", "limit=-5"},
+		{"limit above the maximum", "limit=1000"},
+		{"unknown sort", "sort=email"},
+		{"unknown descending sort", "sort=-email"},
+		{"invalid ID", "major_id=123"},
+		{"invalid number", "year=second"},
+		{"invalid bool", "graduated=maybe"},
+		{"invalid date", "date_from=31.01.2025."},
+	}
+	r := &Repository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			_, err = r.ParseListQuery(values, testListSpec)
+			assert.True(t, errors.Is(err, ErrInvalidListQuery), "got %v", err)
+		})
+	}
+}
+
+func TestParseListDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		upper bool
+		want  time.Time
+	}{
+		{"bare date as lower bound", "2025-03-10", false, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.Local)},
+		{"bare date as upper bound", "2025-03-10", true, time.Date(2025, time.March, 10, 23, 59, 59, 999999999, time.Local)},
+		{"timestamp as lower bound", "2025-03-10T09:30:00Z", false, time.Date(2025, time.March, 10, 9, 30, 0, 0, time.UTC)},
+		{"timestamp as upper bound is exact", "2025-03-10T09:30:00Z", true, time.Date(2025, time.March, 10, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseListDate(tt.value, tt.upper)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+
+	for _, value := range []string{"", "10.03.2025.", "2025-13-01", "yesterday"} {
+		_, err := parseListDate(value, false)
+		assert.True(t, errors.Is(err, ErrInvalidListQuery), "%q: got %v", value, err)
+	}
+}
+
+func TestNewListPage(t *testing.T) {
+	page := NewListPage(ListQuery{Page: 2, Limit: 20}, []int{}, 41)
+	assert.Equal(t, int64(3), page.Pages)
+
+	all := NewListPage(ListQuery{}, []int{}, 41)
+	assert.Equal(t, int64(0), all.Pages)
+	assert.Equal(t, int64(41), all.Total)
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+func departmentMajors(d *Department) []primitive.ObjectID { return d.MajorIDs }
+func departmentStaff(d *Department) []primitive.ObjectID  { return d.StaffIDs }
+
+// userSorts orders people by name; User is stored as the embedded "user" document.
+var userSorts = map[string][]string{
+	"name":       {"user.lastname", "user.firstname"},
+	"first_name": {"user.firstname"},
+	"email":      {"user.email"},
+}
+
+var StudentListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "major", Field: "major_id", Kind: ObjectIDFilter},
+		{Param: "department", Field: "major_id", Kind: DepartmentFilter, In: departmentMajors},
+		{Param: "year", Field: "year", Kind: IntFilter},
+		{Param: "graduated", Field: "graduated", Kind: BoolFilter},
+		{Param: "index_number", Field: "index_number", Kind: StringFilter},
+	},
+	Sorts: map[string][]string{
+		"name":         userSorts["name"],
+		"index_number": {"index_number"},
+		"year":         {"year"},
+		"gpa":          {"gpa"},
+		"espb":         {"esbp"},
+	},
+	DefaultSort: "name",
+}
+
+var ProfessorListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "department", Field: "_id", Kind: DepartmentFilter, In: departmentStaff},
+		{Param: "subject", Field: "subjects._id", Kind: ObjectIDFilter},
+	},
+	Sorts:       userSorts,
+	DefaultSort: "name",
+}
+
+var AssistantListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "department", Field: "_id", Kind: DepartmentFilter, In: departmentStaff},
+		{Param: "professor", Field: "professor._id", Kind: ObjectIDFilter},
+		{Param: "subject", Field: "subjects._id", Kind: ObjectIDFilter},
+	},
+	Sorts:       userSorts,
+	DefaultSort: "name",
+}
+
+var AdministratorListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "position", Field: "position", Kind: StringFilter},
+	},
+	Sorts: map[string][]string{"position": {"position"}},
+}
+
+var SubjectListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "major", Field: "major_id", Kind: ObjectIDFilter},
+		{Param: "department", Field: "major_id", Kind: DepartmentFilter, In: departmentMajors},
+		{Param: "year", Field: "year", Kind: IntFilter},
+		{Param: "semester", Field: "semester", Kind: IntFilter},
+		{Param: "professor", Field: "professor_ids", Kind: ObjectIDFilter},
+	},
+	Sorts: map[string][]string{
+		"name": {"name"},
+		"year": {"year", "semester", "name"},
+		"espb": {"espb"},
+	},
+	DefaultSort: "year",
+}
+
+var DepartmentListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "head", Field: "head", Kind: ObjectIDFilter},
+	},
+	Sorts:       map[string][]string{"name": {"name"}},
+	DefaultSort: "name",
+}
+
+var UniversityListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "location", Field: "location", Kind: StringFilter},
+	},
+	Sorts: map[string][]string{
+		"name":            {"name"},
+		"foundation_year": {"foundation_year"},
+	},
+	DefaultSort: "name",
+}
+
+var MajorListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "department", Field: "department_id", Kind: ObjectIDFilter},
+		{Param: "duration", Field: "duration", Kind: IntFilter},
+	},
+	Sorts: map[string][]string{
+		"name":     {"name"},
+		"duration": {"duration"},
+	},
+	DefaultSort: "name",
+}
+
+var ExamSessionListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "status", Field: "status", Kind: StringFilter},
+		{Param: "subject", Field: "subject._id", Kind: ObjectIDFilter},
+		{Param: "major", Field: "subject.major_id", Kind: ObjectIDFilter},
+		{Param: "department", Field: "subject.major_id", Kind: DepartmentFilter, In: departmentMajors},
+		{Param: "professor", Field: "professor._id", Kind: ObjectIDFilter},
+		{Param: "exam_period", Field: "exam_period_id", Kind: ObjectIDFilter},
+		{Param: "room", Field: "room_id", Kind: ObjectIDFilter},
+		{Param: "date", Field: "exam_date", Kind: DateFilter},
+	},
+	Sorts: map[string][]string{
+		"date":       {"exam_date"},
+		"created_at": {"created_at"},
+	},
+	DefaultSort: "date",
+}
+
+var ExamPeriodListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "active", Field: "is_active", Kind: BoolFilter},
+		{Param: "academic_year", Field: "academic_year", Kind: IntFilter},
+		{Param: "semester", Field: "semester", Kind: IntFilter},
+		{Param: "major", Field: "major_id", Kind: ObjectIDFilter},
+		{Param: "start_date", Field: "start_date", Kind: DateFilter},
+	},
+	Sorts: map[string][]string{
+		"start_date": {"start_date"},
+		"name":       {"name"},
+	},
+	DefaultSort: "start_date",
+}
+
+var RoomListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "building", Field: "building", Kind: StringFilter},
+		{Param: "equipment", Field: "equipment", Kind: StringFilter},
+	},
+	Sorts: map[string][]string{
+		"building": {"building", "name"},
+		"name":     {"name"},
+		"capacity": {"capacity"},
+	},
+	DefaultSort: "building",
+}
+
+var NotificationListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "recipient", Field: "recipient_id", Kind: ObjectIDFilter},
+		{Param: "recipient_type", Field: "recipienttype", Kind: StringFilter},
+		{Param: "seen", Field: "seen", Kind: BoolFilter},
+		{Param: "created_at", Field: "created_at", Kind: DateFilter},
+	},
+	Sorts:       map[string][]string{"created_at": {"created_at"}},
+	DefaultSort: "created_at",
+}
+
+var OfficeHourSlotListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "staff", Field: "staff_id", Kind: ObjectIDFilter},
+		{Param: "staff_role", Field: "staff_role", Kind: StringFilter},
+		{Param: "day", Field: "day", Kind: IntFilter},
+		{Param: "room", Field: "room_id", Kind: ObjectIDFilter},
+	},
+	Sorts: map[string][]string{
+		"day":   {"day", "start_time"},
+		"staff": {"staff_name", "day", "start_time"},
+	},
+	DefaultSort: "day",
+}
+
+var OfficeHourBookingListSpec = ListSpec{
+	Filters: []ListFilter{
+		{Param: "status", Field: "status", Kind: StringFilter},
+		{Param: "staff", Field: "staff_id", Kind: ObjectIDFilter},
+		{Param: "slot", Field: "slot_id", Kind: ObjectIDFilter},
+		{Param: "start", Field: "start", Kind: DateFilter},
+	},
+	Sorts: map[string][]string{
+		"start":      {"start"},
+		"created_at": {"created_at"},
+	},
+	DefaultSort: "start",
+}
+
+func (r *Repository) ListStudents(q ListQuery) ([]Student, int64, error) {
+	students := []Student{}
+	total, err := r.findList("student", q, &students)
+	return students, total, err
+}
+
+func (r *Repository) ListProfessors(q ListQuery) ([]Professor, int64, error) {
+	professors := []Professor{}
+	total, err := r.findList("professor", q, &professors)
+	return professors, total, err
+}
+
+func (r *Repository) ListAssistants(q ListQuery) ([]Assistant, int64, error) {
+	assistants := []Assistant{}
+	total, err := r.findList("assistant", q, &assistants)
+	return assistants, total, err
+}
+
+func (r *Repository) ListAdministrators(q ListQuery) ([]Administrator, int64, error) {
+	administrators := []Administrator{}
+	total, err := r.findList("administrator", q, &administrators)
+	return administrators, total, err
+}
+
+func (r *Repository) ListSubjects(q ListQuery) ([]Subject, int64, error) {
+	subjects := []Subject{}
+	total, err := r.findList("subjects", q, &subjects)
+	return subjects, total, err
+}
+
+func (r *Repository) ListDepartments(q ListQuery) ([]Department, int64, error) {
+	departments := []Department{}
+	total, err := r.findList("department", q, &departments)
+	return departments, total, err
+}
+
+func (r *Repository) ListUniversities(q ListQuery) ([]University, int64, error) {
+	universities := []University{}
+	total, err := r.findList("university", q, &universities)
+	return universities, total, err
+}
+
+func (r *Repository) ListMajors(q ListQuery) ([]Major, int64, error) {
+	majors := []Major{}
+	total, err := r.findList("majors", q, &majors)
+	return majors, total, err
+}
+
+func (r *Repository) ListExamSessions(q ListQuery) ([]ExamSession, int64, error) {
+	sessions := []ExamSession{}
+	total, err := r.findList("exam_sessions", q, &sessions)
+	return sessions, total, err
+}
+
+func (r *Repository) ListExamPeriods(q ListQuery) ([]ExamPeriod, int64, error) {
+	periods := []ExamPeriod{}
+	total, err := r.findList("exam_periods", q, &periods)
+	return periods, total, err
+}
+
+func (r *Repository) ListRooms(q ListQuery) ([]Room, int64, error) {
+	rooms := []Room{}
+	total, err := r.findList("rooms", q, &rooms)
+	return rooms, total, err
+}
+
+func (r *Repository) ListNotifications(q ListQuery) ([]Notification, int64, error) {
+	notifications := []Notification{}
+	total, err := r.findList("notifications", q, &notifications)
+	return notifications, total, err
+}
+
+func (r *Repository) ListOfficeHourSlots(q ListQuery) ([]OfficeHourSlot, int64, error) {
+	slots := []OfficeHourSlot{}
+	total, err := r.findList("office_hour_slots", q, &slots)
+	return slots, total, err
+}
+
+func (r *Repository) ListOfficeHourBookings(q ListQuery) ([]OfficeHourBooking, int64, error) {
+	bookings := []OfficeHourBooking{}
+	total, err := r.findList("office_hour_bookings", q, &bookings)
+	return bookings, total, err
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultOfficeHourCapacity = 1
+	OfficeHourReminderLead    = 24 * time.Hour // how long before a booked consultation the student is reminded
+)
+
+// OfficeHourSlot is a consultation time (konsultacije) a professor or assistant holds every week,
+// in a room, online or both. Capacity is how many students can book the same week's consultation.
+type OfficeHourSlot struct {
+	ID                     primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	StaffID                primitive.ObjectID  `bson:"staff_id" json:"staff_id"`
+	StaffRole              TeacherRole         `bson:"staff_role" json:"staff_role"`
+	StaffName              string              `bson:"staff_name" json:"staff_name"`
+	Day                    time.Weekday        `bson:"day" json:"day"`               // 1 = Monday ... 6 = Saturday, 0 = Sunday
+	StartTime              string              `bson:"start_time" json:"start_time"` // "HH:MM", inclusive
+	EndTime                string              `bson:"end_time" json:"end_time"`     // "HH:MM", exclusive
+	Capacity               int                 `bson:"capacity" json:"capacity"`
+	RoomID                 *primitive.ObjectID `bson:"room_id,omitempty" json:"room_id,omitempty"`
+	RoomName               string              `bson:"room_name,omitempty" json:"room_name,omitempty"`
+	OnlineLink             string              `bson:"online_link,omitempty" json:"online_link,omitempty"`
+	Note                   string              `bson:"note,omitempty" json:"note,omitempty"`
+	ValidFrom              time.Time           `bson:"valid_from" json:"valid_from"`
+	ValidUntil             *time.Time          `bson:"valid_until,omitempty" json:"valid_until,omitempty"` // last day the slot is held; nil until it is removed
+	BlockDuringExamPeriods bool                `bson:"block_during_exam_periods" json:"block_during_exam_periods"`
+	CreatedAt              time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// At returns the start and end of the slot on the given day.
+func (s *OfficeHourSlot) At(day time.Time) (time.Time, time.Time) {
+	start, _ := parseClock(s.StartTime)
+	end, _ := parseClock(s.EndTime)
+	midnight := startOfDay(day)
+	return midnight.Add(time.Duration(start) * time.Minute), midnight.Add(time.Duration(end) * time.Minute)
+}
+
+// Location describes where the consultation is held, for notifications.
+func (s *OfficeHourSlot) Location() string {
+	switch {
+	case s.RoomName != "" && s.OnlineLink != "":
+		return s.RoomName + " or online at " + s.OnlineLink
+	case s.RoomName != "":
+		return s.RoomName
+	case s.OnlineLink != "":
+		return "online at " + s.OnlineLink
+	default:
+		return ""
+	}
+}
+
+// OfficeHourSlotRequest is the payload for publishing or changing an office-hour slot. Capacity
+// defaults to DefaultOfficeHourCapacity and ValidFrom to today.
+type OfficeHourSlotRequest struct {
+	Day                    time.Weekday        `json:"day"`
+	StartTime              string              `json:"start_time" binding:"required"`
+	EndTime                string              `json:"end_time" binding:"required"`
+	Capacity               int                 `json:"capacity"`
+	RoomID                 *primitive.ObjectID `json:"room_id"`
+	OnlineLink             string              `json:"online_link"`
+	Note                   string              `json:"note"`
+	ValidFrom              *time.Time          `json:"valid_from"`
+	ValidUntil             *time.Time          `json:"valid_until"`
+	BlockDuringExamPeriods bool                `json:"block_during_exam_periods"`
+}
+
+// OfficeHourBookingStatus is the state of a student's booking.
+type OfficeHourBookingStatus string
+
+const (
+	OfficeHourBooked    OfficeHourBookingStatus = "booked"
+	OfficeHourCancelled OfficeHourBookingStatus = "cancelled"
+)
+
+// OfficeHourBooking is a student's place in one week's occurrence of an office-hour slot.
+type OfficeHourBooking struct {
+	ID           primitive.ObjectID      `bson:"_id,omitempty" json:"id"`
+	SlotID       primitive.ObjectID      `bson:"slot_id" json:"slot_id"`
+	StaffID      primitive.ObjectID      `bson:"staff_id" json:"staff_id"`
+	StaffName    string                  `bson:"staff_name" json:"staff_name"`
+	StudentID    primitive.ObjectID      `bson:"student_id" json:"student_id"`
+	StudentName  string                  `bson:"student_name" json:"student_name"`
+	IndexNumber  string                  `bson:"index_number,omitempty" json:"index_number,omitempty"`
+	Date         string                  `bson:"date" json:"date"` // YYYY-MM-DD of the occurrence
+	Start        time.Time               `bson:"start" json:"start"`
+	End          time.Time               `bson:"end" json:"end"`
+	Location     string                  `bson:"location" json:"location"`
+	Topic        string                  `bson:"topic,omitempty" json:"topic,omitempty"`
+	Status       OfficeHourBookingStatus `bson:"status" json:"status"`
+	CancelledBy  *primitive.ObjectID     `bson:"cancelled_by,omitempty" json:"cancelled_by,omitempty"`
+	CancelledAt  *time.Time              `bson:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	ReminderSent bool                    `bson:"reminder_sent" json:"reminder_sent"`
+	CreatedAt    time.Time               `bson:"created_at" json:"created_at"`
+}
+
+// BookOfficeHourRequest is the payload for booking the occurrence of a slot on a date.
+type BookOfficeHourRequest struct {
+	Date  string `json:"date" binding:"required"` // YYYY-MM-DD
+	Topic string `json:"topic"`
+}
+
+// OfficeHourOccurrence is an office-hour slot on a concrete date. Blocked says why it cannot be
+// booked that day, e.g. a holiday or an exam period.
+type OfficeHourOccurrence struct {
+	SlotID    primitive.ObjectID `json:"slot_id"`
+	Date      string             `json:"date"`
+	Start     time.Time          `json:"start"`
+	End       time.Time          `json:"end"`
+	Capacity  int                `json:"capacity"`
+	Booked    int                `json:"booked"`
+	Available int                `json:"available"`
+	Blocked   string             `json:"blocked,omitempty"`
+}
+
+// AgendaEntry is one of the staff member's office hours on the agenda's day with the students
+// who booked it.
+type AgendaEntry struct {
+	Slot     OfficeHourSlot      `json:"slot"`
+	Start    time.Time           `json:"start"`
+	End      time.Time           `json:"end"`
+	Blocked  string              `json:"blocked,omitempty"`
+	Bookings []OfficeHourBooking `json:"bookings"`
+}
+
+// OfficeHourAgenda is a professor's or assistant's office hours on one day.
+type OfficeHourAgenda struct {
+	StaffID primitive.ObjectID `json:"staff_id"`
+	Date    string             `json:"date"`
+	Weekday string             `json:"weekday"`
+	Entries []AgendaEntry      `json:"entries"`
+}
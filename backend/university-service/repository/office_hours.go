@@ -0,0 +1,580 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrOfficeHourSlotNotFound    = errors.New("office-hour slot not found")
+	ErrInvalidOfficeHourSlot     = errors.New("invalid office-hour slot")
+	ErrOfficeHourSlotHasBookings = errors.New("office-hour slot has upcoming bookings")
+	ErrOfficeHourBookingNotFound = errors.New("office-hour booking not found")
+	ErrInvalidOfficeHourBooking  = errors.New("invalid office-hour booking")
+	ErrOfficeHourFull            = errors.New("office hours are fully booked")
+	ErrOfficeHoursBlocked        = errors.New("office hours are not held on that day")
+	ErrAlreadyBookedOfficeHour   = errors.New("student has already booked these office hours")
+)
+
+// heldOn reports whether the slot takes place on the day: the weekday matches and the day is
+// within the slot's validity.
+func (s *OfficeHourSlot) heldOn(day time.Time) bool {
+	if day.Weekday() != s.Day || startOfDay(day).Before(startOfDay(s.ValidFrom.In(day.Location()))) {
+		return false
+	}
+	return s.ValidUntil == nil || !startOfDay(day).After(startOfDay(s.ValidUntil.In(day.Location())))
+}
+
+// buildOfficeHourSlot validates the request and resolves the staff member and room it refers to.
+func (r *Repository) buildOfficeHourSlot(staffID primitive.ObjectID, role TeacherRole, req OfficeHourSlotRequest) (*OfficeHourSlot, error) {
+	if req.Day < time.Sunday || req.Day > time.Saturday {
+		return nil, fmt.Errorf("%w: day must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidOfficeHourSlot)
+	}
+	start, err := parseClock(req.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start_time must be in HH:MM format", ErrInvalidOfficeHourSlot)
+	}
+	end, err := parseClock(req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end_time must be in HH:MM format", ErrInvalidOfficeHourSlot)
+	}
+	if end <= start {
+		return nil, fmt.Errorf("%w: end_time must be after start_time", ErrInvalidOfficeHourSlot)
+	}
+	if req.Capacity < 0 {
+		return nil, fmt.Errorf("%w: capacity must be at least 1", ErrInvalidOfficeHourSlot)
+	}
+	if req.Capacity == 0 {
+		req.Capacity = DefaultOfficeHourCapacity
+	}
+	if req.OnlineLink != "" {
+		link, err := url.Parse(req.OnlineLink)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return nil, fmt.Errorf("%w: online_link must be an http or https URL", ErrInvalidOfficeHourSlot)
+		}
+	}
+
+	slot := &OfficeHourSlot{
+		StaffID:                staffID,
+		StaffRole:              role,
+		Day:                    req.Day,
+		StartTime:              formatClock(start),
+		EndTime:                formatClock(end),
+		Capacity:               req.Capacity,
+		OnlineLink:             req.OnlineLink,
+		Note:                   req.Note,
+		ValidFrom:              startOfDay(time.Now()),
+		BlockDuringExamPeriods: req.BlockDuringExamPeriods,
+	}
+	if req.ValidFrom != nil {
+		slot.ValidFrom = startOfDay(req.ValidFrom.In(time.Local))
+	}
+	if req.ValidUntil != nil {
+		until := startOfDay(req.ValidUntil.In(time.Local))
+		if until.Before(slot.ValidFrom) {
+			return nil, fmt.Errorf("%w: valid_until cannot be before valid_from", ErrInvalidOfficeHourSlot)
+		}
+		slot.ValidUntil = &until
+	}
+	if req.RoomID != nil {
+		room, err := r.GetRoomByID(*req.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if room == nil {
+			return nil, ErrRoomNotFound
+		}
+		slot.RoomID, slot.RoomName = &room.ID, room.Name
+	}
+
+	switch role {
+	case TeacherProfessor:
+		professor, err := r.GetProfessorByID(staffID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("%w: professor not found", ErrInvalidOfficeHourSlot)
+		}
+		slot.StaffName = userFullName(&professor.User)
+	case TeacherAssistant:
+		assistant, err := r.GetAssistantByID(staffID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("%w: assistant not found", ErrInvalidOfficeHourSlot)
+		}
+		slot.StaffName = userFullName(&assistant.User)
+	default:
+		return nil, fmt.Errorf("%w: only professors and assistants hold office hours", ErrInvalidOfficeHourSlot)
+	}
+	return slot, nil
+}
+
+// checkOfficeHourOverlap rejects a slot that overlaps another slot of the same staff member
+// while both are valid.
+func (r *Repository) checkOfficeHourOverlap(slot *OfficeHourSlot) error {
+	// Times are zero-padded, so comparing them as strings compares them in time
+	others, err := r.findOfficeHourSlots(bson.M{
+		"_id":        bson.M{"$ne": slot.ID},
+		"staff_id":   slot.StaffID,
+		"day":        slot.Day,
+		"start_time": bson.M{"$lt": slot.EndTime},
+		"end_time":   bson.M{"$gt": slot.StartTime},
+	})
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ValidUntil != nil && other.ValidUntil.Before(slot.ValidFrom) {
+			continue
+		}
+		if slot.ValidUntil != nil && slot.ValidUntil.Before(other.ValidFrom) {
+			continue
+		}
+		return fmt.Errorf("%w: it overlaps your office hours on %s from %s to %s", ErrInvalidOfficeHourSlot, other.Day, other.StartTime, other.EndTime)
+	}
+	return nil
+}
+
+func (r *Repository) CreateOfficeHourSlot(staffID primitive.ObjectID, role TeacherRole, req OfficeHourSlotRequest) (*OfficeHourSlot, error) {
+	slot, err := r.buildOfficeHourSlot(staffID, role, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkOfficeHourOverlap(slot); err != nil {
+		return nil, err
+	}
+	slot.ID = primitive.NewObjectID()
+	slot.CreatedAt = time.Now()
+	if _, err := r.getCollection("office_hour_slots").InsertOne(context.TODO(), slot); err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// UpdateOfficeHourSlot replaces a slot. Its day and times cannot change while students have
+// upcoming bookings; a new room or link is passed on to those bookings.
+func (r *Repository) UpdateOfficeHourSlot(id primitive.ObjectID, req OfficeHourSlotRequest) (*OfficeHourSlot, error) {
+	existing, err := r.GetOfficeHourSlotByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrOfficeHourSlotNotFound
+	}
+	slot, err := r.buildOfficeHourSlot(existing.StaffID, existing.StaffRole, req)
+	if err != nil {
+		return nil, err
+	}
+	slot.ID = id
+	slot.CreatedAt = existing.CreatedAt
+	if err := r.checkOfficeHourOverlap(slot); err != nil {
+		return nil, err
+	}
+
+	upcoming := bson.M{"slot_id": id, "status": OfficeHourBooked, "start": bson.M{"$gt": time.Now()}}
+	bookings, err := r.findOfficeHourBookings(upcoming)
+	if err != nil {
+		return nil, err
+	}
+	moved := slot.StartTime != existing.StartTime || slot.EndTime != existing.EndTime
+	for _, booking := range bookings {
+		if moved || !slot.heldOn(booking.Start.In(time.Local)) {
+			return nil, fmt.Errorf("%w: a student is booked on %s; cancel the bookings or publish a new slot instead", ErrOfficeHourSlotHasBookings, booking.Date)
+		}
+	}
+	if _, err := r.getCollection("office_hour_slots").ReplaceOne(context.TODO(), bson.M{"_id": id}, slot); err != nil {
+		return nil, err
+	}
+	if slot.Location() != existing.Location() {
+		if _, err := r.getCollection("office_hour_bookings").UpdateMany(context.TODO(), upcoming,
+			bson.M{"$set": bson.M{"location": slot.Location()}}); err != nil {
+			return nil, err
+		}
+	}
+	return slot, nil
+}
+
+// DeleteOfficeHourSlot removes a slot and cancels its upcoming bookings, which it returns so the
+// students can be told.
+func (r *Repository) DeleteOfficeHourSlot(id, cancelledBy primitive.ObjectID) ([]OfficeHourBooking, error) {
+	slot, err := r.GetOfficeHourSlotByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, ErrOfficeHourSlotNotFound
+	}
+	now := time.Now()
+	upcoming := bson.M{"slot_id": id, "status": OfficeHourBooked, "start": bson.M{"$gt": now}}
+	cancelled, err := r.findOfficeHourBookings(upcoming)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.getCollection("office_hour_bookings").UpdateMany(context.TODO(), upcoming, bson.M{"$set": bson.M{
+		"status":       OfficeHourCancelled,
+		"cancelled_by": cancelledBy,
+		"cancelled_at": now,
+	}}); err != nil {
+		return nil, err
+	}
+	if _, err := r.getCollection("office_hour_occurrences").DeleteMany(context.TODO(), bson.M{"slot_id": id}); err != nil {
+		return nil, err
+	}
+	if _, err := r.getCollection("office_hour_slots").DeleteOne(context.TODO(), bson.M{"_id": id}); err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// GetOfficeHourSlotByID returns nil, nil when the slot does not exist.
+func (r *Repository) GetOfficeHourSlotByID(id primitive.ObjectID) (*OfficeHourSlot, error) {
+	var slot OfficeHourSlot
+	err := r.getCollection("office_hour_slots").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&slot)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *Repository) findOfficeHourSlots(filter bson.M) ([]OfficeHourSlot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.getCollection("office_hour_slots").Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	slots := []OfficeHourSlot{}
+	err = cursor.All(context.TODO(), &slots)
+	return slots, err
+}
+
+// officeHoursBlocked returns why the slot cannot be booked on the day, or "" if it can: the
+// faculty is closed, or the slot is blocked during exam periods and one is under way.
+func (r *Repository) officeHoursBlocked(slot *OfficeHourSlot, day time.Time) (string, error) {
+	year, err := r.GetAcademicYearForDate(day)
+	if err != nil {
+		return "", err
+	}
+	if year != nil {
+		if closed, ok := year.ClosedOn(day); ok {
+			return "the faculty is closed (" + closed.Name + ")", nil
+		}
+	}
+	if !slot.BlockDuringExamPeriods {
+		return "", nil
+	}
+	period, err := r.examPeriodOn(day)
+	if err != nil || period == nil {
+		return "", err
+	}
+	return "no office hours during the " + period.Name, nil
+}
+
+// examPeriodOn returns an exam period of any major that includes the day, active or not, or nil.
+func (r *Repository) examPeriodOn(day time.Time) (*ExamPeriod, error) {
+	// Widened by a day on each side, as periods may have been stored in another time zone
+	cursor, err := r.getCollection("exam_periods").Find(context.TODO(), bson.M{
+		"start_date": bson.M{"$lt": startOfDay(day).AddDate(0, 0, 2)},
+		"end_date":   bson.M{"$gte": startOfDay(day).AddDate(0, 0, -1)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var periods []ExamPeriod
+	if err := cursor.All(context.TODO(), &periods); err != nil {
+		return nil, err
+	}
+	for i := range periods {
+		if withinDays(day, periods[i].StartDate, periods[i].EndDate) {
+			return &periods[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// parseOfficeHourDate reads a YYYY-MM-DD date the slot is held on.
+func parseOfficeHourDate(slot *OfficeHourSlot, date string) (time.Time, error) {
+	day, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be in YYYY-MM-DD format", ErrInvalidOfficeHourBooking)
+	}
+	if !slot.heldOn(day) {
+		return time.Time{}, fmt.Errorf("%w: %s does not hold these office hours on %s", ErrInvalidOfficeHourBooking, slot.StaffName, date)
+	}
+	return day, nil
+}
+
+func occurrenceID(slotID primitive.ObjectID, date string) string {
+	return slotID.Hex() + "-" + date
+}
+
+// reserveOfficeHourPlace adds the student to those booked on the slot's date, reporting false
+// when all places are taken. The list is created with the first booking of the date.
+func (r *Repository) reserveOfficeHourPlace(slot *OfficeHourSlot, date string, studentID primitive.ObjectID) (bool, error) {
+	occurrences := r.getCollection("office_hour_occurrences")
+	id := occurrenceID(slot.ID, date)
+	_, err := occurrences.UpdateOne(context.TODO(), bson.M{"_id": id},
+		bson.M{"$setOnInsert": bson.M{"slot_id": slot.ID, "staff_id": slot.StaffID, "date": date, "students": bson.A{}}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	// The list is full when it has an element at index capacity-1
+	result, err := occurrences.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "students": bson.M{"$ne": studentID}, fmt.Sprintf("students.%d", slot.Capacity-1): bson.M{"$exists": false}},
+		bson.M{"$push": bson.M{"students": studentID}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *Repository) releaseOfficeHourPlace(slotID primitive.ObjectID, date string, studentID primitive.ObjectID) error {
+	_, err := r.getCollection("office_hour_occurrences").UpdateOne(context.TODO(),
+		bson.M{"_id": occurrenceID(slotID, date)},
+		bson.M{"$pull": bson.M{"students": studentID}},
+	)
+	return err
+}
+
+// BookOfficeHour reserves the student a place in the slot's occurrence on the requested date.
+func (r *Repository) BookOfficeHour(slotID primitive.ObjectID, student *Student, req BookOfficeHourRequest) (*OfficeHourBooking, error) {
+	slot, err := r.GetOfficeHourSlotByID(slotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, ErrOfficeHourSlotNotFound
+	}
+	day, err := parseOfficeHourDate(slot, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	start, end := slot.At(day)
+	if !start.After(now) {
+		return nil, fmt.Errorf("%w: these office hours have already started", ErrInvalidOfficeHourBooking)
+	}
+	blocked, err := r.officeHoursBlocked(slot, day)
+	if err != nil {
+		return nil, err
+	}
+	if blocked != "" {
+		return nil, fmt.Errorf("%w: %s", ErrOfficeHoursBlocked, blocked)
+	}
+
+	bookings := r.getCollection("office_hour_bookings")
+	existing, err := bookings.CountDocuments(context.TODO(), bson.M{
+		"slot_id": slot.ID, "date": req.Date, "student_id": student.ID, "status": OfficeHourBooked,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrAlreadyBookedOfficeHour
+	}
+	reserved, err := r.reserveOfficeHourPlace(slot, req.Date, student.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		return nil, ErrOfficeHourFull
+	}
+
+	booking := &OfficeHourBooking{
+		ID:          primitive.NewObjectID(),
+		SlotID:      slot.ID,
+		StaffID:     slot.StaffID,
+		StaffName:   slot.StaffName,
+		StudentID:   student.ID,
+		StudentName: userFullName(&student.User),
+		IndexNumber: student.IndexNumber,
+		Date:        req.Date,
+		Start:       start,
+		End:         end,
+		Location:    slot.Location(),
+		Topic:       req.Topic,
+		Status:      OfficeHourBooked,
+		CreatedAt:   now,
+	}
+	if _, err := bookings.InsertOne(context.TODO(), booking); err != nil {
+		if releaseErr := r.releaseOfficeHourPlace(slot.ID, req.Date, student.ID); releaseErr != nil {
+			r.logger.Printf("Failed to release office-hour place %s: %v", occurrenceID(slot.ID, req.Date), releaseErr)
+		}
+		return nil, err
+	}
+	return booking, nil
+}
+
+// CancelOfficeHourBooking cancels a booking that has not started yet and frees its place.
+func (r *Repository) CancelOfficeHourBooking(id, cancelledBy primitive.ObjectID) (*OfficeHourBooking, error) {
+	booking, err := r.GetOfficeHourBookingByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrOfficeHourBookingNotFound
+	}
+	now := time.Now()
+	if !booking.Start.After(now) {
+		return nil, fmt.Errorf("%w: the consultation has already started", ErrInvalidOfficeHourBooking)
+	}
+	result, err := r.getCollection("office_hour_bookings").UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": OfficeHourBooked},
+		bson.M{"$set": bson.M{"status": OfficeHourCancelled, "cancelled_by": cancelledBy, "cancelled_at": now}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.ModifiedCount == 0 {
+		return nil, fmt.Errorf("%w: the booking is already cancelled", ErrInvalidOfficeHourBooking)
+	}
+	if err := r.releaseOfficeHourPlace(booking.SlotID, booking.Date, booking.StudentID); err != nil {
+		return nil, err
+	}
+	booking.Status, booking.CancelledBy, booking.CancelledAt = OfficeHourCancelled, &cancelledBy, &now
+	return booking, nil
+}
+
+// GetOfficeHourBookingByID returns nil, nil when the booking does not exist.
+func (r *Repository) GetOfficeHourBookingByID(id primitive.ObjectID) (*OfficeHourBooking, error) {
+	var booking OfficeHourBooking
+	err := r.getCollection("office_hour_bookings").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&booking)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *Repository) findOfficeHourBookings(filter bson.M) ([]OfficeHourBooking, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.getCollection("office_hour_bookings").Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	bookings := []OfficeHourBooking{}
+	err = cursor.All(context.TODO(), &bookings)
+	return bookings, err
+}
+
+// GetOfficeHourAvailability returns the slot's occurrences in the given number of weeks from the
+// day, with how many places are still free in each.
+func (r *Repository) GetOfficeHourAvailability(slotID primitive.ObjectID, from time.Time, weeks int) ([]OfficeHourOccurrence, error) {
+	slot, err := r.GetOfficeHourSlotByID(slotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, ErrOfficeHourSlotNotFound
+	}
+	from = startOfDay(from)
+	first := from.AddDate(0, 0, (int(slot.Day)-int(from.Weekday())+7)%7)
+
+	occurrences := []OfficeHourOccurrence{}
+	for i := 0; i < weeks; i++ {
+		day := first.AddDate(0, 0, 7*i)
+		if !slot.heldOn(day) {
+			continue
+		}
+		start, end := slot.At(day)
+		date := day.Format(time.DateOnly)
+		booked, err := r.getCollection("office_hour_bookings").CountDocuments(context.TODO(), bson.M{
+			"slot_id": slot.ID, "date": date, "status": OfficeHourBooked,
+		})
+		if err != nil {
+			return nil, err
+		}
+		blocked, err := r.officeHoursBlocked(slot, day)
+		if err != nil {
+			return nil, err
+		}
+		occurrence := OfficeHourOccurrence{
+			SlotID:   slot.ID,
+			Date:     date,
+			Start:    start,
+			End:      end,
+			Capacity: slot.Capacity,
+			Booked:   int(booked),
+			Blocked:  blocked,
+		}
+		if blocked == "" && start.After(time.Now()) {
+			occurrence.Available = max(slot.Capacity-int(booked), 0)
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+// GetOfficeHourAgenda returns the staff member's office hours on the day with the students
+// booked for each, in the order they booked.
+func (r *Repository) GetOfficeHourAgenda(staffID primitive.ObjectID, day time.Time) (*OfficeHourAgenda, error) {
+	slots, err := r.findOfficeHourSlots(bson.M{"staff_id": staffID, "day": day.Weekday()})
+	if err != nil {
+		return nil, err
+	}
+	date := day.Format(time.DateOnly)
+	agenda := &OfficeHourAgenda{
+		StaffID: staffID,
+		Date:    date,
+		Weekday: day.Weekday().String(),
+		Entries: []AgendaEntry{},
+	}
+	for _, slot := range slots {
+		if !slot.heldOn(day) {
+			continue
+		}
+		blocked, err := r.officeHoursBlocked(&slot, day)
+		if err != nil {
+			return nil, err
+		}
+		bookings, err := r.findOfficeHourBookings(bson.M{"slot_id": slot.ID, "date": date, "status": OfficeHourBooked})
+		if err != nil {
+			return nil, err
+		}
+		start, end := slot.At(day)
+		agenda.Entries = append(agenda.Entries, AgendaEntry{Slot: slot, Start: start, End: end, Blocked: blocked, Bookings: bookings})
+	}
+	return agenda, nil
+}
+
+// DueOfficeHourReminders returns the bookings starting within OfficeHourReminderLead that have
+// not been reminded of yet, marking them as reminded.
+func (r *Repository) DueOfficeHourReminders(now time.Time) ([]OfficeHourBooking, error) {
+	due, err := r.findOfficeHourBookings(bson.M{
+		"status":        OfficeHourBooked,
+		"reminder_sent": false,
+		"start":         bson.M{"$gt": now, "$lte": now.Add(OfficeHourReminderLead)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	reminders := []OfficeHourBooking{}
+	for _, booking := range due {
+		result, err := r.getCollection("office_hour_bookings").UpdateOne(context.TODO(),
+			bson.M{"_id": booking.ID, "reminder_sent": false},
+			bson.M{"$set": bson.M{"reminder_sent": true}},
+		)
+		if err != nil {
+			return reminders, err
+		}
+		if result.ModifiedCount == 1 {
+			reminders = append(reminders, booking)
+		}
+	}
+	return reminders, nil
+}
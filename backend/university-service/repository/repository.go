@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,6 +16,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	ErrStudentNotFound = errors.New("student not found")
+	ErrMajorNotFound   = errors.New("major not found")
+)
+
 type Repository struct {
 	cli    *mongo.Client
 	logger *log.Logger
@@ -180,13 +186,12 @@ func (r *Repository) UpdateStudentGPA(studentID primitive.ObjectID, gpa float64)
 
 func (r *Repository) DeleteStudent(userID string) error {
 	r.logger.Println("Deleting student with ID:", userID)
-	collection := r.getCollection("student")
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		r.logger.Println("Invalid student ID format:", err)
 		return err
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err = r.DeleteWithCascade("student", objectID)
 	if err != nil {
 		r.logger.Println("Error deleting student:", err)
 	}
@@ -205,6 +210,14 @@ func (r *Repository) DeleteDynamic(entityID string, entityType string) error {
 		r.logger.Println("Invalid "+entityType+" ID format:", err)
 		return err
 	}
+	// Entities with a cascade policy must not leave dangling references behind
+	if _, ok := cascadePolicies[entityType]; ok {
+		_, err = r.DeleteWithCascade(entityType, objectID)
+		if err != nil {
+			r.logger.Println("Error deleting "+entityType+":", err)
+		}
+		return err
+	}
 	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
 	if err != nil {
 		r.logger.Println("Error deleting "+entityType+":", err)
@@ -310,13 +323,12 @@ func (r *Repository) UpdateDepartment(department *Department) error {
 
 func (r *Repository) DeleteDepartment(departmentID string) error {
 	r.logger.Println("Deleting department with ID:", departmentID)
-	collection := r.getCollection("department")
 	objectID, err := primitive.ObjectIDFromHex(departmentID)
 	if err != nil {
 		r.logger.Println("Invalid department ID format:", err)
 		return err
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err = r.DeleteWithCascade("department", objectID)
 	if err != nil {
 		r.logger.Println("Error deleting department:", err)
 	}
@@ -473,13 +485,12 @@ func (r *Repository) UpdateProfessor(professor *Professor) error {
 
 func (r *Repository) DeleteProfessor(professorID string) error {
 	r.logger.Println("Deleting professor with ID:", professorID)
-	collection := r.getCollection("professor")
 	objectID, err := primitive.ObjectIDFromHex(professorID)
 	if err != nil {
 		r.logger.Println("Invalid professor ID format:", err)
 		return err
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err = r.DeleteWithCascade("professor", objectID)
 	if err != nil {
 		r.logger.Println("Error deleting professor:", err)
 	}
@@ -529,13 +540,12 @@ func (r *Repository) UpdateAssistant(assistant *Assistant) error {
 
 func (r *Repository) DeleteAssistant(assistantID string) error {
 	r.logger.Println("Deleting assistant with ID:", assistantID)
-	collection := r.getCollection("assistant")
 	objectID, err := primitive.ObjectIDFromHex(assistantID)
 	if err != nil {
 		r.logger.Println("Invalid assistant ID format:", err)
 		return err
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err = r.DeleteWithCascade("assistant", objectID)
 	if err != nil {
 		r.logger.Println("Error deleting assistant:", err)
 	}
@@ -801,54 +811,18 @@ func (r *Repository) UpdateSubject(subject *Subject) error {
 }
 
 func (r *Repository) DeleteSubject(subjectID string) error {
-	subjectsCol := r.getCollection("subjects")
-
 	objectID, err := primitive.ObjectIDFromHex(subjectID)
 	if err != nil {
 		return fmt.Errorf("invalid subject ID: %v", err)
 	}
 
-	// Get the subject to know which major to update
-	subject, err := r.GetSubjectByID(subjectID)
-	if err != nil {
-		return err
-	}
-	if subject == nil {
-		return fmt.Errorf("subject not found")
-	}
-
-	// Delete the subject from "subjects" collection
-	_, err = subjectsCol.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	// Removes the subject from majors, students and staff, and deletes its exam sessions
+	_, err = r.DeleteWithCascade("subjects", objectID)
 	if err != nil {
 		r.logger.Println("Error deleting subject:", err)
 		return err
 	}
 
-	// Remove it from the major’s Subjects array
-	major, err := r.GetMajorByID(subject.MajorID)
-	if err != nil {
-		r.logger.Println("Error fetching major for subject deletion:", err)
-		return err
-	}
-	if major == nil {
-		return fmt.Errorf("major with ID %v not found", subject.MajorID.Hex())
-	}
-
-	newSubjects := make([]Subject, 0, len(major.Subjects))
-	for _, s := range major.Subjects {
-		if s.ID != objectID {
-			newSubjects = append(newSubjects, s)
-		}
-	}
-	major.Subjects = newSubjects
-
-	// Update the major in the DB
-	err = r.UpdateMajor(major.ID, major)
-	if err != nil {
-		r.logger.Println("Error updating major after subject deletion:", err)
-		return err
-	}
-
 	return nil
 }
 
@@ -1117,26 +1091,30 @@ func (r *Repository) DeregisterExam(studentID, subjectID primitive.ObjectID) err
 	return err
 }
 
-func (r *Repository) GetLectures() ([]Subject, error) {
-	collection := r.getCollection("subjects")
-	cursor, err := collection.Find(context.TODO(), bson.M{})
+// PayTuition posts a tuition payment to the student's ledger, towards the academic year the
+// payment date falls in.
+func (r *Repository) PayTuition(payment *TuitionPayment) (*LedgerEntry, error) {
+	if payment.Date.IsZero() {
+		payment.Date = time.Now()
+	}
+	academicYear, err := r.CurrentAcademicYear(payment.Date)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.TODO())
-
-	var lectures []Subject
-	if err = cursor.All(context.TODO(), &lectures); err != nil {
+	entry := &LedgerEntry{
+		StudentID:    payment.StudentID,
+		Type:         LedgerPayment,
+		Amount:       payment.Amount,
+		Description:  "Tuition payment " + formatAcademicYear(academicYear),
+		AcademicYear: academicYear,
+		CreatedAt:    payment.Date,
+	}
+	if err := r.PostLedgerEntry(entry); err != nil {
 		return nil, err
 	}
-
-	return lectures, nil
-}
-
-func (r *Repository) PayTuition(payment *TuitionPayment) error {
-	collection := r.getCollection("tuitionPayments")
-	_, err := collection.InsertOne(context.TODO(), payment)
-	return err
+	payment.ID = entry.ID
+	payment.Date = entry.CreatedAt
+	return entry, nil
 }
 
 func (r *Repository) CreateNotification(notification *Notification) error {
@@ -1470,7 +1448,9 @@ func (r *Repository) DeleteExamPeriod(id primitive.ObjectID) error {
 // ExamSession methods
 func (r *Repository) CreateExamSession(examSession *ExamSession) error {
 	collection := r.getCollection("exam_sessions")
-	examSession.ID = primitive.NewObjectID()
+	if examSession.ID.IsZero() {
+		examSession.ID = primitive.NewObjectID()
+	}
 	examSession.CreatedAt = time.Now()
 	examSession.Status = "scheduled"
 
@@ -1564,9 +1544,18 @@ func (r *Repository) UpdateExamSession(examSession *ExamSession) error {
 	if examSession.Location != "" {
 		updateDoc["location"] = examSession.Location
 	}
+	if examSession.RoomID != nil {
+		updateDoc["room_id"] = examSession.RoomID
+	}
+	if examSession.Duration != 0 {
+		updateDoc["duration_minutes"] = examSession.Duration
+	}
 	if examSession.MaxStudents != 0 {
 		updateDoc["max_students"] = examSession.MaxStudents
 	}
+	if examSession.RegistrationFee != 0 {
+		updateDoc["registration_fee"] = examSession.RegistrationFee
+	}
 	if examSession.Status != "" {
 		updateDoc["status"] = examSession.Status
 	}
@@ -1579,41 +1568,29 @@ func (r *Repository) UpdateExamSession(examSession *ExamSession) error {
 		return nil // No fields to update
 	}
 
-	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": examSession.ID}, bson.M{"$set": updateDoc})
-	return err
-}
-func (r *Repository) UpdateExamSessionsToPending() error {
-	collection := r.getCollection("exam_sessions")
-	now := time.Now()
-
-	// Update all scheduled exams where exam_date <= now
-	filter := bson.M{
-		"status":    "Scheduled",
-		"exam_date": bson.M{"$lte": now},
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"status": "Pending",
-		},
+	filter := bson.M{"_id": examSession.ID}
+	if examSession.MaxStudents > 0 {
+		if err := r.ensureRegisteredCount(examSession.ID); err != nil {
+			return err
+		}
+		// Checked in the update itself, so a concurrent registration cannot take a seat the new limit removes
+		filter["registered_count"] = bson.M{"$lte": examSession.MaxStudents}
 	}
-
-	result, err := collection.UpdateMany(context.TODO(), filter, update)
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateDoc})
 	if err != nil {
 		return err
 	}
-
-	r.logger.Printf("Updated %d exam sessions to pending status", result.ModifiedCount)
+	if examSession.MaxStudents > 0 && result.MatchedCount == 0 {
+		return ErrCapacityBelowSeats
+	}
 	return nil
 }
-
 func (r *Repository) DeleteExamSession(examSessionID string) error {
-	collection := r.getCollection("exam_sessions")
 	objectID, err := primitive.ObjectIDFromHex(examSessionID)
 	if err != nil {
 		return err
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err = r.DeleteWithCascade("exam_sessions", objectID)
 	return err
 }
 
@@ -1631,29 +1608,83 @@ func (r *Repository) GetAllExamSessions() ([]ExamSession, error) {
 }
 
 // ExamRegistration methods
+
+// RegisterForExam takes a seat in the exam session and stores the registration.
+// Returns ErrExamSessionClosed once the exam has taken place, ErrExamSessionFull when no seats are
+// left and ErrAlreadyRegistered when the student is already registered.
 func (r *Repository) RegisterForExam(registration *ExamRegistration) error {
+	examSession, err := r.GetExamSessionByID(registration.ExamSessionID.Hex())
+	if err != nil {
+		return err
+	}
+	if !examSessionOpen(examSession, time.Now()) {
+		return ErrExamSessionClosed
+	}
+	seated, err := r.reserveExamSeat(registration.ExamSessionID)
+	if err != nil {
+		return err
+	}
+	if !seated {
+		return ErrExamSessionFull
+	}
 	collection := r.getCollection("exam_registrations")
 	registration.ID = primitive.NewObjectID()
 	registration.RegisteredAt = time.Now()
 	registration.Status = "registered"
-	_, err := collection.InsertOne(context.TODO(), registration)
-	return err
+	_, err = collection.InsertOne(context.TODO(), registration)
+	if err != nil {
+		_ = r.releaseExamSeat(registration.ExamSessionID)
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyRegistered
+		}
+		return err
+	}
+	r.chargeRegistrationFee(registration)
+	return nil
 }
 
-func (r *Repository) DeregisterFromExam(studentID, examSessionID primitive.ObjectID) error {
+// chargeRegistrationFee posts the exam fee for a stored registration. A failed charge is logged
+// rather than undoing the registration; studentska služba can post it manually.
+func (r *Repository) chargeRegistrationFee(registration *ExamRegistration) {
+	examSession, err := r.GetExamSessionByID(registration.ExamSessionID.Hex())
+	if err == nil {
+		err = r.chargeExamFee(registration.Student.ID, examSession)
+	}
+	if err != nil {
+		r.logger.Printf("Failed to charge exam fee for registration %s: %v", registration.ID.Hex(), err)
+	}
+}
+
+// DeregisterFromExam removes the student's registration (or waitlist entry) and hands the freed
+// seat to the next student on the waitlist. Returns the promoted registration, if any, and
+// ErrExamSessionClosed once the exam has taken place.
+func (r *Repository) DeregisterFromExam(studentID, examSessionID primitive.ObjectID) (*ExamRegistration, error) {
 	collection := r.getCollection("exam_registrations")
 	examSession, err := r.GetExamSessionByID(examSessionID.Hex())
 	if err != nil {
-		return fmt.Errorf("you can't unregister from a completed exam")
+		return nil, err
 	}
-	if examSession.Status == "Completed" {
-		return err
+	if !examSessionOpen(examSession, time.Now()) {
+		return nil, ErrExamSessionClosed
 	}
-	_, err = collection.DeleteOne(context.TODO(), bson.M{
+	result, err := collection.DeleteOne(context.TODO(), bson.M{
 		"student._id":     studentID,
 		"exam_session_id": examSessionID,
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		_, err = r.RemoveFromExamWaitlist(studentID, examSessionID)
+		return nil, err
+	}
+	if err := r.releaseExamSeat(examSessionID); err != nil {
+		return nil, err
+	}
+	if _, err := r.refundExamFee(studentID, examSession, time.Now()); err != nil {
+		r.logger.Printf("Failed to refund exam fee of student %s for exam session %s: %v", studentID.Hex(), examSessionID.Hex(), err)
+	}
+	return r.PromoteFromWaitlist(examSessionID)
 }
 
 func (r *Repository) GetExamRegistrationsByStudent(studentID primitive.ObjectID) ([]ExamRegistration, error) {
@@ -1880,12 +1911,102 @@ func (r *Repository) GetProfessorsByMajorId(majorId primitive.ObjectID) ([]Profe
 	return professors, nil
 }
 
-func (r *Repository) RegisterStudentForMajor(id primitive.ObjectID, major_id primitive.ObjectID) error {
-	//collection := r.getCollection("majors")
-	return nil
+// RegisterStudentForMajor assigns the student to the major and syncs Student.Subjects with the
+// curriculum version the student is pinned to (see PinnedCurriculum). It is safe to re-run: subjects added to the curriculum are appended and
+// passed subjects are never wiped. When switching majors, passed subjects that also exist in the
+// new curriculum are carried over.
+func (r *Repository) RegisterStudentForMajor(id primitive.ObjectID, major_id primitive.ObjectID) (*Student, error) {
+	student, err := r.GetStudentByIDObject(id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrStudentNotFound
+		}
+		return nil, err
+	}
+	major, err := r.GetMajorByID(major_id)
+	if err != nil {
+		return nil, err
+	}
+	if major == nil {
+		return nil, ErrMajorNotFound
+	}
+
+	currentYear, err := r.CurrentAcademicYear(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	generation, err := r.StudentGeneration(student.ID, currentYear)
+	if err != nil {
+		return nil, err
+	}
+	sameMajor := student.MajorID == major.ID
+	curriculum, version, err := r.PinnedCurriculum(student, major, sameMajor, generation)
+	if err != nil {
+		return nil, err
+	}
+	// Electives are only carried over once chosen, which happens when the student enrolls
+	student.Subjects = MergeCurriculum(student.Subjects, curriculum.CurriculumFor(student.Subjects), sameMajor)
+	student.MajorID = major.ID
+	student.CurriculumVersion = version
+
+	collection := r.getCollection("student")
+	_, err = collection.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": student.ID},
+		bson.M{"$set": bson.M{"major_id": student.MajorID, "subjects": student.Subjects, "curriculum_version": student.CurriculumVersion}},
+	)
+	if err != nil {
+		r.logger.Println("Error registering student for major:", err)
+		return nil, err
+	}
+	r.logger.Printf("Student %s registered for major %s with %d subjects", student.ID.Hex(), major.ID.Hex(), len(student.Subjects))
+	return student, nil
+}
+
+// MergeCurriculum builds a student's subject list from a major's curriculum, keeping progress
+// (HasPassed and ExamGrade) for subjects the student already has. A subject matches by ID, or by
+// name when switching majors, since equivalent subjects of different majors are separate documents.
+// On a re-run for the same major, passed subjects that were dropped from the curriculum are kept.
+func MergeCurriculum(current []Subject, curriculum []Subject, sameMajor bool) []Subject {
+	byID := make(map[primitive.ObjectID]Subject, len(current))
+	passedByName := make(map[string]Subject)
+	for _, s := range current {
+		byID[s.ID] = s
+		if s.HasPassed {
+			passedByName[strings.ToLower(strings.TrimSpace(s.Name))] = s
+		}
+	}
+
+	merged := make([]Subject, 0, len(curriculum))
+	inCurriculum := make(map[primitive.ObjectID]bool, len(curriculum))
+	for _, subject := range curriculum {
+		inCurriculum[subject.ID] = true
+		subject.HasPassed = false
+		subject.ExamGrade = ExamGrade{}
+		if existing, ok := byID[subject.ID]; ok {
+			subject.HasPassed = existing.HasPassed
+			subject.ExamGrade = existing.ExamGrade
+		} else if !sameMajor {
+			if existing, ok := passedByName[strings.ToLower(strings.TrimSpace(subject.Name))]; ok {
+				subject.HasPassed = true
+				subject.ExamGrade = existing.ExamGrade
+			}
+		}
+		merged = append(merged, subject)
+	}
+
+	if sameMajor {
+		for _, s := range current {
+			if s.HasPassed && !inCurriculum[s.ID] {
+				merged = append(merged, s)
+			}
+		}
+	}
+	return merged
 }
 
-// UpdateMajor updates a major’s details.
+// UpdateMajor updates a major’s details. Students pinned to a published curriculum version
+// (see PublishCurriculumVersion) are not affected by edits to the subjects.
 func (r *Repository) UpdateMajor(id primitive.ObjectID, updatedMajor *Major) error {
 	collection := r.getCollection("majors")
 
@@ -1906,9 +2027,7 @@ func (r *Repository) UpdateMajor(id primitive.ObjectID, updatedMajor *Major) err
 
 // DeleteMajor removes a major by ID.
 func (r *Repository) DeleteMajor(id primitive.ObjectID) error {
-	collection := r.getCollection("majors")
-
-	_, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	_, err := r.DeleteWithCascade("majors", id)
 	if err != nil {
 		r.logger.Println("Error deleting major:", err)
 		return err
@@ -1949,6 +2068,8 @@ func (r *Repository) CreateGraduationRequest(request *GraduationRequest) error {
 	request.ID = primitive.NewObjectID()
 	request.RequestedAt = time.Now()
 	request.Status = "Pending"
+	request.Stage = GraduationRequested
+	request.History = []GraduationEvent{{Stage: GraduationRequested, At: request.RequestedAt}}
 	_, err := collection.InsertOne(context.TODO(), request)
 	return err
 }
@@ -2000,7 +2121,7 @@ func (r *Repository) DeleteGraduationRequest(id primitive.ObjectID) error {
 	return err
 }
 func (r *Repository) GraduateStudent(studentID primitive.ObjectID) error {
-	collection := r.getCollection("students")
+	collection := r.getCollection("student")
 	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": studentID}, bson.M{"$set": bson.M{"graduated": true}})
 	return err
 }
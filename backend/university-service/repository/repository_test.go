@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMergeCurriculum(t *testing.T) {
+	algebra := Subject{ID: primitive.NewObjectID(), Name: "Algebra", Year: 1}
+	programming := Subject{ID: primitive.NewObjectID(), Name: "Programming", Year: 1}
+	databases := Subject{ID: primitive.NewObjectID(), Name: "Databases", Year: 2}
+	// Same subject as algebra, but a separate document of another major
+	otherAlgebra := Subject{ID: primitive.NewObjectID(), Name: " algebra ", Year: 1}
+
+	passed := func(s Subject, grade int) Subject {
+		s.HasPassed = true
+		s.ExamGrade = ExamGrade{Grade: grade, Passed: true}
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		current    []Subject
+		curriculum []Subject
+		sameMajor  bool
+		want       []Subject
+	}{
+		{
+			name:       "new student gets the curriculum without progress",
+			curriculum: []Subject{algebra, passed(programming, 8)},
+			sameMajor:  true,
+			want:       []Subject{algebra, programming},
+		},
+		{
+			name:       "progress is kept for subjects with the same ID",
+			current:    []Subject{passed(algebra, 9), programming},
+			curriculum: []Subject{algebra, programming, databases},
+			sameMajor:  true,
+			want:       []Subject{passed(algebra, 9), programming, databases},
+		},
+		{
+			name:       "passed subjects dropped from the curriculum are kept for the same major",
+			current:    []Subject{passed(algebra, 7), programming},
+			curriculum: []Subject{programming, databases},
+			sameMajor:  true,
+			want:       []Subject{programming, databases, passed(algebra, 7)},
+		},
+		{
+			name:       "switching majors matches passed subjects by name",
+			current:    []Subject{passed(algebra, 10), passed(programming, 6)},
+			curriculum: []Subject{otherAlgebra, databases},
+			sameMajor:  false,
+			want:       []Subject{passed(otherAlgebra, 10), databases},
+		},
+		{
+			name:       "subjects that were not passed are not matched by name",
+			current:    []Subject{algebra},
+			curriculum: []Subject{otherAlgebra},
+			sameMajor:  false,
+			want:       []Subject{otherAlgebra},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MergeCurriculum(tt.current, tt.curriculum, tt.sameMajor))
+		})
+	}
+}
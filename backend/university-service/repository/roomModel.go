@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Working hours used when computing free slots for a room on a given day.
+const (
+	RoomDayStartHour           = 8
+	RoomDayEndHour             = 20
+	DefaultExamDurationMinutes = 120
+)
+
+// Room is a lecture hall or classroom that can be reserved for exams.
+type Room struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`         // e.g. "A1", "NTP-305"
+	Building  string             `bson:"building" json:"building"` // e.g. "Glavna zgrada"
+	Capacity  int                `bson:"capacity" json:"capacity"`
+	Equipment []string           `bson:"equipment,omitempty" json:"equipment,omitempty"` // e.g. "projector", "computers"
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// RoomBooking reserves a room for a time window. ExamSessionID is set when the booking belongs to an exam.
+type RoomBooking struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	RoomID        primitive.ObjectID  `bson:"room_id" json:"room_id"`
+	ExamSessionID *primitive.ObjectID `bson:"exam_session_id,omitempty" json:"exam_session_id,omitempty"`
+	StartTime     time.Time           `bson:"start_time" json:"start_time"` // inclusive
+	EndTime       time.Time           `bson:"end_time" json:"end_time"`     // exclusive
+	Purpose       string              `bson:"purpose" json:"purpose"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// TimeSlot is a free interval inside a room's working day.
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// RoomOccupancy lists a room's bookings and free slots for one day.
+type RoomOccupancy struct {
+	Room      Room          `json:"room"`
+	Date      string        `json:"date"`
+	Bookings  []RoomBooking `json:"bookings"`
+	FreeSlots []TimeSlot    `json:"free_slots"`
+}
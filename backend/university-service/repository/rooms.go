@@ -0,0 +1,357 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRoomNotFound            = errors.New("room not found")
+	ErrRoomAlreadyBooked       = errors.New("room is already booked for the requested time")
+	ErrRoomCapacityTooLow      = errors.New("max_students exceeds room capacity")
+	ErrRoomHasUpcomingBookings = errors.New("room has upcoming bookings and cannot be deleted")
+	ErrRoomInTimetable         = errors.New("room is used in the lecture timetable and cannot be deleted")
+	ErrRoomBusy                = errors.New("the room is being booked by another request, try again")
+)
+
+const (
+	// roomLockLease is how long a booking lock is held at most; a lock left by a crashed request
+	// expires after it.
+	roomLockLease = 10 * time.Second
+	// roomLockWait is how long a booking waits for another booking of the same room to finish.
+	roomLockWait = 5 * time.Second
+)
+
+// RoomBookingConflictError is returned when a booking overlaps an existing one.
+type RoomBookingConflictError struct {
+	Conflict RoomBooking
+}
+
+func (e *RoomBookingConflictError) Error() string {
+	return fmt.Sprintf("%s: %s - %s", ErrRoomAlreadyBooked.Error(),
+		e.Conflict.StartTime.Format(time.DateTime), e.Conflict.EndTime.Format(time.DateTime))
+}
+
+func (e *RoomBookingConflictError) Unwrap() error {
+	return ErrRoomAlreadyBooked
+}
+
+func (r *Repository) CreateRoom(room *Room) error {
+	collection := r.getCollection("rooms")
+	room.ID = primitive.NewObjectID()
+	room.CreatedAt = time.Now()
+	_, err := collection.InsertOne(context.TODO(), room)
+	if err != nil {
+		r.logger.Println("Error inserting room:", err)
+	}
+	return err
+}
+
+// GetRoomByID returns nil, nil when the room does not exist.
+func (r *Repository) GetRoomByID(id primitive.ObjectID) (*Room, error) {
+	collection := r.getCollection("rooms")
+	var room Room
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&room)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *Repository) GetAllRooms() ([]Room, error) {
+	collection := r.getCollection("rooms")
+	opts := options.Find().SetSort(bson.D{{Key: "building", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var rooms []Room
+	err = cursor.All(context.TODO(), &rooms)
+	return rooms, err
+}
+
+func (r *Repository) UpdateRoom(room *Room) error {
+	collection := r.getCollection("rooms")
+	update := bson.M{
+		"$set": bson.M{
+			"name":      room.Name,
+			"building":  room.Building,
+			"capacity":  room.Capacity,
+			"equipment": room.Equipment,
+		},
+	}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": room.ID}, update)
+	if err != nil {
+		r.logger.Println("Error updating room:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRoomNotFound
+	}
+	return nil
+}
+
+// DeleteRoom removes a room. Rooms with bookings that have not ended yet or with timetable slots
+// cannot be deleted.
+func (r *Repository) DeleteRoom(id primitive.ObjectID) error {
+	bookings := r.getCollection("room_bookings")
+	upcoming, err := bookings.CountDocuments(context.TODO(), bson.M{
+		"room_id":  id,
+		"end_time": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if upcoming > 0 {
+		return ErrRoomHasUpcomingBookings
+	}
+	scheduled, err := r.getCollection("timetable_slots").CountDocuments(context.TODO(), bson.M{"room_id": id})
+	if err != nil {
+		return err
+	}
+	if scheduled > 0 {
+		return ErrRoomInTimetable
+	}
+	if _, err := bookings.DeleteMany(context.TODO(), bson.M{"room_id": id}); err != nil {
+		return err
+	}
+	_, err = r.getCollection("rooms").DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
+// findOverlappingBooking returns the first booking for roomID that overlaps [start, end),
+// ignoring bookings that belong to excludeSession (used when an exam is rescheduled).
+func (r *Repository) findOverlappingBooking(roomID primitive.ObjectID, start, end time.Time, excludeSession *primitive.ObjectID) (*RoomBooking, error) {
+	filter := bson.M{
+		"room_id":    roomID,
+		"start_time": bson.M{"$lt": end},
+		"end_time":   bson.M{"$gt": start},
+	}
+	if excludeSession != nil {
+		filter["exam_session_id"] = bson.M{"$ne": *excludeSession}
+	}
+	var booking RoomBooking
+	err := r.getCollection("room_bookings").FindOne(context.TODO(), filter).Decode(&booking)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// lockRoom takes the room's booking lock, so the overlap check and the insert of a booking cannot
+// interleave with another booking of the same room. The lock is a token set on the room document
+// with a conditional update; it is released by the returned function.
+func (r *Repository) lockRoom(roomID primitive.ObjectID) (func(), error) {
+	rooms := r.getCollection("rooms")
+	token := primitive.NewObjectID()
+	deadline := time.Now().Add(roomLockWait)
+	for {
+		now := time.Now()
+		result, err := rooms.UpdateOne(context.TODO(),
+			bson.M{"_id": roomID, "$or": bson.A{
+				bson.M{"booking_lock_until": bson.M{"$exists": false}},
+				bson.M{"booking_lock_until": bson.M{"$lt": now}},
+			}},
+			bson.M{"$set": bson.M{"booking_lock": token, "booking_lock_until": now.Add(roomLockLease)}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 1 {
+			return func() {
+				_, err := rooms.UpdateOne(context.TODO(),
+					bson.M{"_id": roomID, "booking_lock": token},
+					bson.M{"$unset": bson.M{"booking_lock": "", "booking_lock_until": ""}},
+				)
+				if err != nil {
+					r.logger.Printf("Failed to release booking lock of room %s: %v", roomID.Hex(), err)
+				}
+			}, nil
+		}
+		if now.After(deadline) {
+			return nil, ErrRoomBusy
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// ReserveRoomForExamSession books session.RoomID for the exam window and returns the booking. It
+// rejects rooms that are too small or already taken. Earlier bookings of the same session are kept
+// until the caller either confirms the new one with KeepOnlyRoomBooking or drops it with
+// CancelRoomBooking, so a failed reschedule leaves the old booking in place.
+func (r *Repository) ReserveRoomForExamSession(session *ExamSession) (*RoomBooking, error) {
+	if session.RoomID == nil {
+		return nil, nil
+	}
+	room, err := r.GetRoomByID(*session.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+	if session.MaxStudents > room.Capacity {
+		return nil, fmt.Errorf("%w (%d > %d)", ErrRoomCapacityTooLow, session.MaxStudents, room.Capacity)
+	}
+
+	unlock, err := r.lockRoom(room.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	start, end := session.ExamDate, session.EndTime()
+	conflict, err := r.findOverlappingBooking(room.ID, start, end, &session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if conflict != nil {
+		return nil, &RoomBookingConflictError{Conflict: *conflict}
+	}
+
+	sessionID := session.ID
+	booking := RoomBooking{
+		ID:            primitive.NewObjectID(),
+		RoomID:        room.ID,
+		ExamSessionID: &sessionID,
+		StartTime:     start,
+		EndTime:       end,
+		Purpose:       "Exam: " + session.Subject.Name,
+		CreatedAt:     time.Now(),
+	}
+	if _, err := r.getCollection("room_bookings").InsertOne(context.TODO(), booking); err != nil {
+		return nil, err
+	}
+	session.Location = room.Name + ", " + room.Building
+	return &booking, nil
+}
+
+// KeepOnlyRoomBooking removes the exam session's bookings other than the given one, once the
+// session has been saved with its new room or time.
+func (r *Repository) KeepOnlyRoomBooking(examSessionID, bookingID primitive.ObjectID) error {
+	_, err := r.getCollection("room_bookings").DeleteMany(context.TODO(), bson.M{
+		"exam_session_id": examSessionID,
+		"_id":             bson.M{"$ne": bookingID},
+	})
+	return err
+}
+
+// CancelRoomBooking removes a single booking.
+func (r *Repository) CancelRoomBooking(bookingID primitive.ObjectID) error {
+	_, err := r.getCollection("room_bookings").DeleteOne(context.TODO(), bson.M{"_id": bookingID})
+	return err
+}
+
+// ReleaseRoomForExamSession removes the room booking held by an exam session, if any.
+func (r *Repository) ReleaseRoomForExamSession(examSessionID primitive.ObjectID) error {
+	_, err := r.getCollection("room_bookings").DeleteMany(context.TODO(), bson.M{"exam_session_id": examSessionID})
+	return err
+}
+
+// GetRoomBookingsForDay returns all bookings of a room that overlap the given calendar day.
+func (r *Repository) GetRoomBookingsForDay(roomID primitive.ObjectID, day time.Time) ([]RoomBooking, error) {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+	cursor, err := r.getCollection("room_bookings").Find(context.TODO(), bson.M{
+		"room_id":    roomID,
+		"start_time": bson.M{"$lt": dayEnd},
+		"end_time":   bson.M{"$gt": dayStart},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	var bookings []RoomBooking
+	err = cursor.All(context.TODO(), &bookings)
+	return bookings, err
+}
+
+// GetRoomOccupancy returns bookings and free slots (within working hours) of every room,
+// or of a single room when roomID is non-nil, for the given day.
+func (r *Repository) GetRoomOccupancy(day time.Time, roomID *primitive.ObjectID) ([]RoomOccupancy, error) {
+	var rooms []Room
+	if roomID != nil {
+		room, err := r.GetRoomByID(*roomID)
+		if err != nil {
+			return nil, err
+		}
+		if room == nil {
+			return nil, ErrRoomNotFound
+		}
+		rooms = []Room{*room}
+	} else {
+		var err error
+		rooms, err = r.GetAllRooms()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workStart := time.Date(day.Year(), day.Month(), day.Day(), RoomDayStartHour, 0, 0, 0, day.Location())
+	workEnd := time.Date(day.Year(), day.Month(), day.Day(), RoomDayEndHour, 0, 0, 0, day.Location())
+
+	occupancy := make([]RoomOccupancy, 0, len(rooms))
+	for _, room := range rooms {
+		bookings, err := r.GetRoomBookingsForDay(room.ID, day)
+		if err != nil {
+			return nil, err
+		}
+		if bookings == nil {
+			bookings = []RoomBooking{}
+		}
+		occupancy = append(occupancy, RoomOccupancy{
+			Room:      room,
+			Date:      day.Format(time.DateOnly),
+			Bookings:  bookings,
+			FreeSlots: freeSlots(bookings, workStart, workEnd),
+		})
+	}
+	return occupancy, nil
+}
+
+// freeSlots returns the gaps between bookings inside [from, to).
+func freeSlots(bookings []RoomBooking, from, to time.Time) []TimeSlot {
+	sorted := make([]RoomBooking, len(bookings))
+	copy(sorted, bookings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	slots := []TimeSlot{}
+	cursor := from
+	for _, b := range sorted {
+		if b.StartTime.After(cursor) {
+			end := b.StartTime
+			if end.After(to) {
+				end = to
+			}
+			if end.After(cursor) {
+				slots = append(slots, TimeSlot{Start: cursor, End: end})
+			}
+		}
+		if b.EndTime.After(cursor) {
+			cursor = b.EndTime
+		}
+		if !cursor.Before(to) {
+			return slots
+		}
+	}
+	if cursor.Before(to) {
+		slots = append(slots, TimeSlot{Start: cursor, End: to})
+	}
+	return slots
+}
@@ -0,0 +1,360 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrTimetableSlotNotFound = errors.New("timetable slot not found")
+	ErrInvalidTimetableSlot  = errors.New("invalid timetable slot")
+	ErrTimetableConflict     = errors.New("the room or the teacher is already taken at that time")
+)
+
+// TimetableConflictError is returned when a slot overlaps slots using the same room or teacher.
+type TimetableConflictError struct {
+	Conflicts []TimetableConflict
+}
+
+func (e *TimetableConflictError) Error() string {
+	return fmt.Sprintf("%s (%d conflicting slots)", ErrTimetableConflict.Error(), len(e.Conflicts))
+}
+
+func (e *TimetableConflictError) Unwrap() error {
+	return ErrTimetableConflict
+}
+
+// parseClock converts "HH:MM" to minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a time in HH:MM format", ErrInvalidTimetableSlot, s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// buildTimetableSlot validates the request and resolves the subject, room and teacher it refers to.
+func (r *Repository) buildTimetableSlot(req TimetableSlotRequest) (*TimetableSlot, error) {
+	if !req.Type.IsValid() {
+		return nil, fmt.Errorf("%w: type must be %q or %q", ErrInvalidTimetableSlot, ClassLecture, ClassExercise)
+	}
+	if !req.TeacherRole.IsValid() {
+		return nil, fmt.Errorf("%w: teacher_role must be %q or %q", ErrInvalidTimetableSlot, TeacherProfessor, TeacherAssistant)
+	}
+	if req.Day < time.Sunday || req.Day > time.Saturday {
+		return nil, fmt.Errorf("%w: day must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidTimetableSlot)
+	}
+	start, err := parseClock(req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClock(req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, fmt.Errorf("%w: end_time must be after start_time", ErrInvalidTimetableSlot)
+	}
+	if start < RoomDayStartHour*60 || end > RoomDayEndHour*60 {
+		return nil, fmt.Errorf("%w: classes must be held between %02d:00 and %02d:00", ErrInvalidTimetableSlot, RoomDayStartHour, RoomDayEndHour)
+	}
+
+	subject, err := r.GetSubjectByID(req.SubjectID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("%w: subject not found", ErrInvalidTimetableSlot)
+	}
+	room, err := r.GetRoomByID(req.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	slot := &TimetableSlot{
+		SubjectID:    subject.ID,
+		SubjectName:  subject.Name,
+		Type:         req.Type,
+		AcademicYear: req.AcademicYear,
+		Semester:     subject.Semester,
+		Day:          req.Day,
+		StartTime:    formatClock(start),
+		EndTime:      formatClock(end),
+		RoomID:       room.ID,
+		RoomName:     room.Name,
+		TeacherID:    req.TeacherID,
+		TeacherRole:  req.TeacherRole,
+		Group:        req.Group,
+	}
+	switch req.TeacherRole {
+	case TeacherProfessor:
+		professor, err := r.GetProfessorByID(req.TeacherID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("%w: professor not found", ErrInvalidTimetableSlot)
+		}
+		teaches := false
+		for _, id := range subject.ProfessorIDs {
+			teaches = teaches || id == professor.ID
+		}
+		if !teaches {
+			return nil, fmt.Errorf("%w: %s does not teach %s", ErrInvalidTimetableSlot, userFullName(&professor.User), subject.Name)
+		}
+		slot.TeacherName = userFullName(&professor.User)
+	case TeacherAssistant:
+		assistant, err := r.GetAssistantByID(req.TeacherID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("%w: assistant not found", ErrInvalidTimetableSlot)
+		}
+		slot.TeacherName = userFullName(&assistant.User)
+	}
+	return slot, nil
+}
+
+// FindTimetableConflicts returns the slots of the same semester that overlap slot and use its
+// room or its teacher.
+func (r *Repository) FindTimetableConflicts(slot *TimetableSlot) ([]TimetableConflict, error) {
+	// Times are zero-padded, so comparing them as strings compares them in time
+	others, err := r.findTimetableSlots(bson.M{
+		"_id":           bson.M{"$ne": slot.ID},
+		"academic_year": slot.AcademicYear,
+		"semester":      slot.Semester,
+		"day":           slot.Day,
+		"start_time":    bson.M{"$lt": slot.EndTime},
+		"end_time":      bson.M{"$gt": slot.StartTime},
+		"$or":           []bson.M{{"room_id": slot.RoomID}, {"teacher_id": slot.TeacherID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []TimetableConflict{}
+	for _, other := range others {
+		if other.RoomID == slot.RoomID {
+			conflicts = append(conflicts, TimetableConflict{Reason: "room", Slot: other})
+		}
+		if other.TeacherID == slot.TeacherID {
+			conflicts = append(conflicts, TimetableConflict{Reason: "teacher", Slot: other})
+		}
+	}
+	return conflicts, nil
+}
+
+func (r *Repository) CreateTimetableSlot(req TimetableSlotRequest) (*TimetableSlot, error) {
+	slot, err := r.buildTimetableSlot(req)
+	if err != nil {
+		return nil, err
+	}
+	slot.ID = primitive.NewObjectID()
+	conflicts, err := r.FindTimetableConflicts(slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &TimetableConflictError{Conflicts: conflicts}
+	}
+	slot.CreatedAt = time.Now()
+	if _, err := r.getCollection("timetable_slots").InsertOne(context.TODO(), slot); err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// UpdateTimetableSlot replaces a slot, checking the new time against every other slot.
+func (r *Repository) UpdateTimetableSlot(id primitive.ObjectID, req TimetableSlotRequest) (*TimetableSlot, error) {
+	existing, err := r.GetTimetableSlotByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrTimetableSlotNotFound
+	}
+	slot, err := r.buildTimetableSlot(req)
+	if err != nil {
+		return nil, err
+	}
+	slot.ID = id
+	slot.CreatedAt = existing.CreatedAt
+	conflicts, err := r.FindTimetableConflicts(slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &TimetableConflictError{Conflicts: conflicts}
+	}
+	if _, err := r.getCollection("timetable_slots").ReplaceOne(context.TODO(), bson.M{"_id": id}, slot); err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+func (r *Repository) DeleteTimetableSlot(id primitive.ObjectID) error {
+	result, err := r.getCollection("timetable_slots").DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrTimetableSlotNotFound
+	}
+	return nil
+}
+
+// GetTimetableSlotByID returns nil, nil when the slot does not exist.
+func (r *Repository) GetTimetableSlotByID(id primitive.ObjectID) (*TimetableSlot, error) {
+	var slot TimetableSlot
+	err := r.getCollection("timetable_slots").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&slot)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *Repository) findTimetableSlots(filter bson.M) ([]TimetableSlot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "day", Value: 1}, {Key: "start_time", Value: 1}, {Key: "subject_name", Value: 1}})
+	cursor, err := r.getCollection("timetable_slots").Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	slots := []TimetableSlot{}
+	err = cursor.All(context.TODO(), &slots)
+	return slots, err
+}
+
+// GetTimetableSlots returns the slots matching the filter, ordered by day and time.
+func (r *Repository) GetTimetableSlots(f TimetableFilter) ([]TimetableSlot, error) {
+	filter := bson.M{}
+	if f.AcademicYear != 0 {
+		filter["academic_year"] = f.AcademicYear
+	}
+	if f.Semester != 0 {
+		filter["semester"] = f.Semester
+	}
+	if len(f.SubjectIDs) > 0 {
+		filter["subject_id"] = bson.M{"$in": f.SubjectIDs}
+	}
+	if !f.RoomID.IsZero() {
+		filter["room_id"] = f.RoomID
+	}
+	if !f.TeacherID.IsZero() {
+		filter["teacher_id"] = f.TeacherID
+	}
+	return r.findTimetableSlots(filter)
+}
+
+// GetStudentSchedule returns the week containing day for the subjects the student has not passed yet.
+func (r *Repository) GetStudentSchedule(studentID primitive.ObjectID, day time.Time) (*WeeklySchedule, error) {
+	student, err := r.GetStudentByIDObject(studentID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrStudentNotFound
+		}
+		return nil, err
+	}
+	subjectIDs := []primitive.ObjectID{}
+	for _, subject := range student.Subjects {
+		if !subject.HasPassed {
+			subjectIDs = append(subjectIDs, subject.ID)
+		}
+	}
+	return r.weeklySchedule(studentID, "student", day, bson.M{"subject_id": bson.M{"$in": subjectIDs}})
+}
+
+// GetProfessorSchedule returns the week containing day for the subjects listing the professor in
+// ProfessorIDs, together with any other slots the professor teaches.
+func (r *Repository) GetProfessorSchedule(professorID primitive.ObjectID, day time.Time) (*WeeklySchedule, error) {
+	subjects, err := r.GetSubjectsByProfessorId(professorID)
+	if err != nil {
+		return nil, err
+	}
+	subjectIDs := make([]primitive.ObjectID, 0, len(subjects))
+	for _, subject := range subjects {
+		subjectIDs = append(subjectIDs, subject.ID)
+	}
+	return r.weeklySchedule(professorID, "professor", day, bson.M{"$or": []bson.M{
+		{"subject_id": bson.M{"$in": subjectIDs}},
+		{"teacher_id": professorID},
+	}})
+}
+
+// GetAssistantSchedule returns the week containing day for the slots the assistant teaches.
+func (r *Repository) GetAssistantSchedule(assistantID primitive.ObjectID, day time.Time) (*WeeklySchedule, error) {
+	return r.weeklySchedule(assistantID, "assistant", day, bson.M{"teacher_id": assistantID})
+}
+
+// weeklySchedule lays out the slots matching filter on the days of the week containing day.
+// Holidays and non-working days from the academic calendar have no classes, and a slot only
+// appears while its semester is being taught. Without a calendar every slot of the academic
+// year is shown each week.
+func (r *Repository) weeklySchedule(ownerID primitive.ObjectID, ownerType string, day time.Time, filter bson.M) (*WeeklySchedule, error) {
+	monday := startOfDay(day).AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	year, err := r.GetAcademicYearForDate(monday)
+	if err != nil {
+		return nil, err
+	}
+	if year == nil {
+		// The week may start in the summer break and end in the new academic year
+		if year, err = r.GetAcademicYearForDate(monday.AddDate(0, 0, 6)); err != nil {
+			return nil, err
+		}
+	}
+	academicYear := academicYearStart(monday).Year()
+	if year != nil {
+		academicYear = year.Year
+	}
+	filter["academic_year"] = academicYear
+	slots, err := r.findTimetableSlots(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &WeeklySchedule{
+		OwnerID:      ownerID,
+		OwnerType:    ownerType,
+		AcademicYear: formatAcademicYear(academicYear),
+		WeekStart:    monday.Format(time.DateOnly),
+		Days:         make([]ScheduleDay, 0, 7),
+	}
+	for i := 0; i < 7; i++ {
+		date := monday.AddDate(0, 0, i)
+		scheduleDay := ScheduleDay{Date: date.Format(time.DateOnly), Weekday: date.Weekday().String(), Classes: []ScheduledClass{}}
+		if year != nil {
+			if closed, ok := year.ClosedOn(date); ok {
+				scheduleDay.Closed = closed
+				schedule.Days = append(schedule.Days, scheduleDay)
+				continue
+			}
+		}
+		for _, slot := range slots {
+			if slot.Day != date.Weekday() {
+				continue
+			}
+			if year != nil {
+				semester := year.Semester(slot.Semester)
+				if semester == nil || !withinDays(date, semester.StartDate, semester.EndDate) {
+					continue
+				}
+			}
+			start, end := slot.At(date)
+			scheduleDay.Classes = append(scheduleDay.Classes, ScheduledClass{TimetableSlot: slot, Start: start, End: end})
+		}
+		sort.SliceStable(scheduleDay.Classes, func(a, b int) bool {
+			return scheduleDay.Classes[a].Start.Before(scheduleDay.Classes[b].Start)
+		})
+		schedule.Days = append(schedule.Days, scheduleDay)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClassType is the kind of class held in a timetable slot.
+type ClassType string
+
+const (
+	ClassLecture  ClassType = "lecture"  // predavanja
+	ClassExercise ClassType = "exercise" // vežbe
+)
+
+func (t ClassType) IsValid() bool {
+	return t == ClassLecture || t == ClassExercise
+}
+
+// TeacherRole tells whether a slot is taught by a professor or an assistant.
+type TeacherRole string
+
+const (
+	TeacherProfessor TeacherRole = "professor"
+	TeacherAssistant TeacherRole = "assistant"
+)
+
+func (t TeacherRole) IsValid() bool {
+	return t == TeacherProfessor || t == TeacherAssistant
+}
+
+// TimetableSlot is a class that repeats every week of the subject's semester. AcademicYear is the
+// calendar year the academic year starts in, like AcademicYear.Year; Semester is copied from the subject.
+type TimetableSlot struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SubjectID    primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	SubjectName  string             `bson:"subject_name" json:"subject_name"`
+	Type         ClassType          `bson:"type" json:"type"`
+	AcademicYear int                `bson:"academic_year" json:"academic_year"`
+	Semester     int                `bson:"semester" json:"semester"`
+	Day          time.Weekday       `bson:"day" json:"day"`               // 1 = Monday ... 6 = Saturday, 0 = Sunday
+	StartTime    string             `bson:"start_time" json:"start_time"` // "HH:MM", inclusive
+	EndTime      string             `bson:"end_time" json:"end_time"`     // "HH:MM", exclusive
+	RoomID       primitive.ObjectID `bson:"room_id" json:"room_id"`
+	RoomName     string             `bson:"room_name" json:"room_name"`
+	TeacherID    primitive.ObjectID `bson:"teacher_id" json:"teacher_id"`
+	TeacherRole  TeacherRole        `bson:"teacher_role" json:"teacher_role"`
+	TeacherName  string             `bson:"teacher_name" json:"teacher_name"`
+	Group        string             `bson:"group,omitempty" json:"group,omitempty"` // e.g. "G1"; empty for the whole year
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// At returns the start and end of the slot on the given day.
+func (s *TimetableSlot) At(day time.Time) (time.Time, time.Time) {
+	start, _ := parseClock(s.StartTime)
+	end, _ := parseClock(s.EndTime)
+	midnight := startOfDay(day)
+	return midnight.Add(time.Duration(start) * time.Minute), midnight.Add(time.Duration(end) * time.Minute)
+}
+
+// TimetableSlotRequest is the payload for creating or replacing a timetable slot.
+type TimetableSlotRequest struct {
+	SubjectID    primitive.ObjectID `json:"subject_id" binding:"required"`
+	Type         ClassType          `json:"type" binding:"required"`
+	AcademicYear int                `json:"academic_year" binding:"required"`
+	Day          time.Weekday       `json:"day"`
+	StartTime    string             `json:"start_time" binding:"required"`
+	EndTime      string             `json:"end_time" binding:"required"`
+	RoomID       primitive.ObjectID `json:"room_id" binding:"required"`
+	TeacherID    primitive.ObjectID `json:"teacher_id" binding:"required"`
+	TeacherRole  TeacherRole        `json:"teacher_role" binding:"required"`
+	Group        string             `json:"group"`
+}
+
+// TimetableFilter narrows GetTimetableSlots; zero fields are ignored.
+type TimetableFilter struct {
+	AcademicYear int
+	Semester     int
+	SubjectIDs   []primitive.ObjectID
+	RoomID       primitive.ObjectID
+	TeacherID    primitive.ObjectID
+}
+
+// TimetableConflict is an existing slot that takes the same room or teacher at the same time.
+type TimetableConflict struct {
+	Reason string        `json:"reason"` // "room" or "teacher"
+	Slot   TimetableSlot `json:"slot"`
+}
+
+// ScheduledClass is a timetable slot on a concrete date.
+type ScheduledClass struct {
+	TimetableSlot
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ScheduleDay is one day of a weekly schedule. Closed is set on holidays and non-working days,
+// which have no classes.
+type ScheduleDay struct {
+	Date    string           `json:"date"`
+	Weekday string           `json:"weekday"`
+	Closed  *CalendarDay     `json:"closed,omitempty"`
+	Classes []ScheduledClass `json:"classes"`
+}
+
+// WeeklySchedule is the classes of a student or teacher from Monday to Sunday of one week.
+type WeeklySchedule struct {
+	OwnerID      primitive.ObjectID `json:"owner_id"`
+	OwnerType    string             `json:"owner_type"` // "student", "professor" or "assistant"
+	AcademicYear string             `json:"academic_year,omitempty"`
+	WeekStart    string             `json:"week_start"`
+	Days         []ScheduleDay      `json:"days"`
+}
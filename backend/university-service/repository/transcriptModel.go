@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TranscriptEntry is one passed exam on a transcript.
+type TranscriptEntry struct {
+	SubjectID   primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	SubjectName string             `bson:"subject_name" json:"subject_name"`
+	Year        int                `bson:"year" json:"year"`
+	Semester    int                `bson:"semester" json:"semester"`
+	ESPB        int                `bson:"espb" json:"espb"`
+	Grade       int                `bson:"grade" json:"grade"`
+	PassedAt    time.Time          `bson:"passed_at" json:"passed_at"`
+	Professor   string             `bson:"professor,omitempty" json:"professor,omitempty"`
+}
+
+// Transcript (prepis ocena) lists the student's passed exams, built from their exam grades.
+type Transcript struct {
+	StudentID        primitive.ObjectID `bson:"student_id" json:"student_id"`
+	StudentName      string             `bson:"student_name" json:"student_name"`
+	IndexNumber      string             `bson:"index_number,omitempty" json:"index_number,omitempty"`
+	MajorID          primitive.ObjectID `bson:"major_id" json:"major_id"`
+	MajorName        string             `bson:"major_name" json:"major_name"`
+	Year             int                `bson:"year" json:"year"`
+	Entries          []TranscriptEntry  `bson:"entries" json:"entries"`
+	PassedCount      int                `bson:"passed_count" json:"passed_count"`
+	TotalSubjects    int                `bson:"total_subjects" json:"total_subjects"`
+	TotalESPB        int                `bson:"total_espb" json:"total_espb"`
+	GPA              float64            `bson:"gpa" json:"gpa"` // ESPB-weighted
+	IssuedAt         time.Time          `bson:"issued_at" json:"issued_at"`
+	VerificationCode string             `bson:"verification_code" json:"verification_code"`
+}
+
+// IssuedTranscript is the stored copy of a transcript, looked up by its verification code.
+// The same code is reused while the transcript content does not change.
+type IssuedTranscript struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	VerificationCode string             `bson:"verification_code" json:"verification_code"`
+	StudentID        primitive.ObjectID `bson:"student_id" json:"student_id"`
+	Fingerprint      string             `bson:"fingerprint" json:"-"`
+	Transcript       Transcript         `bson:"transcript" json:"transcript"`
+	IssuedAt         time.Time          `bson:"issued_at" json:"issued_at"`
+}
+
+// TranscriptVerification is what the public verify endpoint reveals about an issued transcript.
+type TranscriptVerification struct {
+	Valid            bool              `json:"valid"`
+	VerificationCode string            `json:"verification_code"`
+	StudentName      string            `json:"student_name,omitempty"`
+	IndexNumber      string            `json:"index_number,omitempty"`
+	MajorName        string            `json:"major_name,omitempty"`
+	IssuedAt         time.Time         `json:"issued_at,omitempty"`
+	GPA              float64           `json:"gpa,omitempty"`
+	TotalESPB        int               `json:"total_espb,omitempty"`
+	PassedCount      int               `json:"passed_count,omitempty"`
+	Entries          []TranscriptEntry `json:"entries,omitempty"`
+}
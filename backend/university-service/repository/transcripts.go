@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// verificationAlphabet leaves out characters that are easy to misread (0/O, 1/I/L).
+const verificationAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// BuildTranscript assembles the student's transcript from their exam grades. For each subject the
+// latest passing grade is listed; the GPA is weighted by ESPB.
+func (r *Repository) BuildTranscript(studentID primitive.ObjectID) (*Transcript, error) {
+	student, err := r.GetStudentByIDObject(studentID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrStudentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transcript := &Transcript{
+		StudentID:   student.ID,
+		StudentName: studentFullName(student),
+		IndexNumber: student.IndexNumber,
+		MajorID:     student.MajorID,
+		Year:        student.Year,
+		Entries:     []TranscriptEntry{},
+	}
+
+	subjects := make(map[primitive.ObjectID]Subject)
+	for _, s := range student.Subjects {
+		subjects[s.ID] = s
+	}
+	if !student.MajorID.IsZero() {
+		major, err := r.GetStudentMajor(student)
+		if err != nil {
+			return nil, err
+		}
+		if major != nil {
+			transcript.MajorName = major.Name
+			transcript.TotalSubjects = major.RequiredSubjectCount()
+			for _, s := range major.Subjects {
+				subjects[s.ID] = s
+			}
+		}
+	}
+
+	grades, err := r.GetExamGradesByStudent(studentID)
+	if err != nil {
+		return nil, err
+	}
+	passed := latestPassedGrades(grades)
+	ids := make([]primitive.ObjectID, 0, len(passed))
+	for id := range passed {
+		ids = append(ids, id)
+	}
+	// The subjects collection has the current ESPB, and covers subjects no longer in the curriculum
+	current, err := r.subjectsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	for id, s := range current {
+		subjects[id] = s
+	}
+
+	var gradeValues, credits []int
+	for subjectID, g := range passed {
+		subject := subjects[subjectID]
+		entry := TranscriptEntry{
+			SubjectID:   subjectID,
+			SubjectName: subject.Name,
+			Year:        subject.Year,
+			Semester:    subject.Semester,
+			ESPB:        subject.ESPB,
+			Grade:       g.Grade,
+			PassedAt:    g.GradedAt,
+		}
+		if g.GradedBy.FirstName != nil && g.GradedBy.LastName != nil {
+			entry.Professor = *g.GradedBy.FirstName + " " + *g.GradedBy.LastName
+		}
+		transcript.Entries = append(transcript.Entries, entry)
+		transcript.TotalESPB += subject.ESPB
+		gradeValues = append(gradeValues, g.Grade)
+		credits = append(credits, subject.ESPB)
+	}
+	sort.Slice(transcript.Entries, func(i, j int) bool {
+		a, b := transcript.Entries[i], transcript.Entries[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Semester != b.Semester {
+			return a.Semester < b.Semester
+		}
+		return a.SubjectName < b.SubjectName
+	})
+
+	transcript.PassedCount = len(transcript.Entries)
+	transcript.GPA = weightedGPA(gradeValues, credits)
+	return transcript, nil
+}
+
+// IssueTranscript builds the transcript and gives it a verification code. While the content stays
+// the same the previously issued code is returned, so repeated downloads do not pile up codes.
+func (r *Repository) IssueTranscript(studentID primitive.ObjectID) (*Transcript, error) {
+	transcript, err := r.BuildTranscript(studentID)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := transcriptFingerprint(transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := r.getCollection("transcripts")
+	var existing IssuedTranscript
+	opts := options.FindOne().SetSort(bson.D{{Key: "issued_at", Value: -1}})
+	err = collection.FindOne(context.TODO(), bson.M{"student_id": studentID, "fingerprint": fingerprint}, opts).Decode(&existing)
+	if err == nil {
+		return &existing.Transcript, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+	transcript.IssuedAt = time.Now()
+	transcript.VerificationCode = code
+	issued := IssuedTranscript{
+		ID:               primitive.NewObjectID(),
+		VerificationCode: code,
+		StudentID:        studentID,
+		Fingerprint:      fingerprint,
+		Transcript:       *transcript,
+		IssuedAt:         transcript.IssuedAt,
+	}
+	if _, err := collection.InsertOne(context.TODO(), issued); err != nil {
+		return nil, err
+	}
+	r.logger.Printf("Issued transcript %s for student %s", code, studentID.Hex())
+	return transcript, nil
+}
+
+// VerifyTranscript looks up an issued transcript by its verification code. Returns nil when the code is unknown.
+func (r *Repository) VerifyTranscript(code string) (*IssuedTranscript, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	var issued IssuedTranscript
+	err := r.getCollection("transcripts").FindOne(context.TODO(), bson.M{"verification_code": code}).Decode(&issued)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &issued, nil
+}
+
+func transcriptFingerprint(t *Transcript) (string, error) {
+	content, err := json.Marshal(struct {
+		Name    string
+		Index   string
+		Major   string
+		Entries []TranscriptEntry
+		GPA     float64
+		ESPB    int
+	}{t.StudentName, t.IndexNumber, t.MajorName, t.Entries, t.GPA, t.TotalESPB})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// newVerificationCode returns a random code such as "K7QM-2XHD-R9TB".
+func newVerificationCode() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(verificationAlphabet)))
+	for i := 0; i < 12; i++ {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(verificationAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+func studentFullName(student *Student) string {
+	return userFullName(&student.User)
+}
+
+func userFullName(user *User) string {
+	var parts []string
+	if user.FirstName != nil {
+		parts = append(parts, *user.FirstName)
+	}
+	if user.LastName != nil {
+		parts = append(parts, *user.LastName)
+	}
+	return strings.Join(parts, " ")
+}
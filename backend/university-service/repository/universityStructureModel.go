@@ -23,20 +23,27 @@ type Department struct {
 	StaffIDs []primitive.ObjectID `bson:"staff,omitempty" json:"staff,omitempty"`
 }
 type Major struct {
-	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	Name         string              `bson:"name" json:"name"`
-	Subjects     []Subject           `bson:"subjects,omitempty" json:"subjects,omitempty"`
-	DepartmentID *primitive.ObjectID `bson:"department_id,omitempty" json:"department_id,omitempty"`
-	Duration     int                 `bson:"duration" json:"duration"`
-	Description  string              `bson:"description" json:"description"`
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name               string              `bson:"name" json:"name"`
+	Subjects           []Subject           `bson:"subjects,omitempty" json:"subjects,omitempty"`
+	DepartmentID       *primitive.ObjectID `bson:"department_id,omitempty" json:"department_id,omitempty"`
+	Duration           int                 `bson:"duration" json:"duration"`
+	MinESPBPerYear     int                 `bson:"min_espb_per_year,omitempty" json:"min_espb_per_year,omitempty"`     // ESPB needed per completed year to advance; 0 = ESPBPerYear
+	ElectiveGroups     []ElectiveGroup     `bson:"elective_groups,omitempty" json:"elective_groups,omitempty"`         // subjects of a group are only given to students who choose them
+	IndexPattern       string              `bson:"index_pattern,omitempty" json:"index_pattern,omitempty"`             // how index numbers are formed, e.g. "SW-{seq}/{year}"; empty = DefaultIndexPattern
+	TuitionFee         float64             `bson:"tuition_fee,omitempty" json:"tuition_fee,omitempty"`                 // yearly tuition of self-financed students
+	TuitionInstalments int                 `bson:"tuition_instalments,omitempty" json:"tuition_instalments,omitempty"` // instalments the tuition is split into; 0 = paid at once
+	Description        string              `bson:"description" json:"description"`
 }
 type Subject struct {
-	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-	Name         string               `bson:"name" json:"name" validate:"required"`
-	MajorID      primitive.ObjectID   `bson:"major_id,omitempty" json:"major_id,omitempty"`
-	ProfessorIDs []primitive.ObjectID `bson:"professor_ids,omitempty" json:"professor_ids,omitempty"`
-	Year         int                  `bson:"year" json:"year"`
-	Semester     int                  `bson:"semester" json:"semester"` // 1 = first semester, 2 = second semester of the year
-	HasPassed    bool                 `bson:"has_passed,omitempty" json:"has_passed,omitempty"`
-	ExamGrade    ExamGrade            `bson:"grade,omitempty" json:"grade,omitempty"`
+	ID              primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name            string               `bson:"name" json:"name" validate:"required"`
+	MajorID         primitive.ObjectID   `bson:"major_id,omitempty" json:"major_id,omitempty"`
+	ProfessorIDs    []primitive.ObjectID `bson:"professor_ids,omitempty" json:"professor_ids,omitempty"`
+	Year            int                  `bson:"year" json:"year"`
+	Semester        int                  `bson:"semester" json:"semester"` // 1 = first semester, 2 = second semester of the year
+	ESPB            int                  `bson:"espb" json:"espb"`         // credits earned by passing the subject
+	HasPassed       bool                 `bson:"has_passed,omitempty" json:"has_passed,omitempty"`
+	ExamGrade       ExamGrade            `bson:"grade,omitempty" json:"grade,omitempty"`
+	PrerequisiteIDs []primitive.ObjectID `bson:"prerequisite_ids,omitempty" json:"prerequisite_ids,omitempty"` // subjects that must be passed before registering for the exam
 }
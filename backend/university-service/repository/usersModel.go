@@ -47,16 +47,19 @@ type Assistant struct {
 
 type Student struct {
 	User
-	ID            primitive.ObjectID `bson:"_id" json:"id"`
-	MajorID       primitive.ObjectID `bson:"major_id" json:"major_id,omitempty"`
-	Year          int                `bson:"year" json:"year,omitempty"`
-	HighschoolGPA float64            `bson:"highschool_gpa" json:"highschool_gpa,omitempty"`
-	GPA           float64            `bson:"gpa" json:"gpa"`
-	CVFile        string             `json:"cv_file,omitempty"`
-	CVBase64      string             `json:"cv_base64,omitempty"`
-	Skills        []string           `json:"skills,omitempty"`
-	Subjects      []Subject          `json:"subjects,omitempty"`
-	Graduated     bool               `bson:"graduated" json:"graduated,omitempty"`
+	ID                primitive.ObjectID `bson:"_id" json:"id"`
+	IndexNumber       string             `bson:"index_number,omitempty" json:"index_number,omitempty"`
+	MajorID           primitive.ObjectID `bson:"major_id" json:"major_id,omitempty"`
+	Year              int                `bson:"year" json:"year,omitempty"`
+	HighschoolGPA     float64            `bson:"highschool_gpa" json:"highschool_gpa,omitempty"`
+	GPA               float64            `bson:"gpa" json:"gpa"`
+	ESPB              int                `bson:"esbp" json:"esbp"` // earned credits; same key as the auth and employment Student models
+	CVFile            string             `json:"cv_file,omitempty"`
+	CVBase64          string             `json:"cv_base64,omitempty"`
+	Skills            []string           `json:"skills,omitempty"`
+	Subjects          []Subject          `json:"subjects,omitempty"`
+	Graduated         bool               `bson:"graduated" json:"graduated,omitempty"`
+	CurriculumVersion int                `bson:"curriculum_version,omitempty" json:"curriculum_version,omitempty"` // published curriculum of the major the student follows; 0 = the major's current subjects
 }
 
 type TuitionPayment struct {
@@ -93,11 +96,15 @@ type Internship struct {
 }
 
 type GraduationRequest struct {
-	ID          primitive.ObjectID `bson:"_id" json:"id"`
-	StudentID   primitive.ObjectID `bson:"student_id,omitempty" json:"student_id,omitempty"`
-	RequestedAt time.Time          `bson:"requested_at,omitempty" json:"requested_at,omitempty"`
-	Status      Status             `bson:"status,omitempty" json:"status,omitempty"`
-	Comments    string             `bson:"comments,omitempty" json:"comments,omitempty"`
+	ID          primitive.ObjectID  `bson:"_id" json:"id"`
+	StudentID   primitive.ObjectID  `bson:"student_id,omitempty" json:"student_id,omitempty"`
+	RequestedAt time.Time           `bson:"requested_at,omitempty" json:"requested_at,omitempty"`
+	Status      Status              `bson:"status,omitempty" json:"status,omitempty"`
+	Comments    string              `bson:"comments,omitempty" json:"comments,omitempty"`
+	Stage       GraduationStage     `bson:"stage,omitempty" json:"stage,omitempty"`
+	Thesis      *Thesis             `bson:"thesis,omitempty" json:"thesis,omitempty"`
+	DiplomaID   *primitive.ObjectID `bson:"diploma_id,omitempty" json:"diploma_id,omitempty"`
+	History     []GraduationEvent   `bson:"history,omitempty" json:"history,omitempty"`
 }
 type Notifications []*Notification
 type InternshipApplications []*InternshipApplication
@@ -20,6 +20,7 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		public.GET("/administrators", ctrl.GetAllAdministrators)
 		public.GET("/assistants", ctrl.GetAllAssistants)
 		public.GET("/majors", ctrl.GetAllMajors)
+		public.GET("/transcripts/verify/:code", ctrl.VerifyTranscript)
 
 	}
 
@@ -28,17 +29,33 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 	{
 		//Students
 		protected.POST("/students/create", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateStudent)
+		protected.POST("/imports", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.StartImport)
+		protected.GET("/imports", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.GetImportJobs)
+		protected.GET("/imports/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.GetImportJob)
+		protected.GET("/imports/:id/report", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.GetImportReport)
 		protected.GET("/students/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "STUDENT"}), ctrl.GetStudentByID)
 		protected.PUT("/students/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "STUDENT"}), ctrl.UpdateStudent)
-		protected.PUT("/students/:id/major?:major_id", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.RegisterStudentForMajor)
+		protected.PUT("/students/:id/major/:major_id", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.RegisterStudentForMajor)
 		protected.DELETE("/students/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteStudent)
-		protected.PUT("/students/:id/advance", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "STUDENT"}), ctrl.AdvanceToNextYear)
+		protected.GET("/students/:id/transcript", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetTranscript)
+		protected.PUT("/students/:id/advance", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.AdvanceToNextYear)
+		protected.POST("/students/:id/enrollments", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.EnrollStudent)
+		protected.GET("/students/:id/enrollments", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentEnrollments)
+		protected.PUT("/enrollments/:id/certify", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CertifySemester)
+		protected.POST("/enrollments/:id/tuition", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.ChargeEnrollmentTuition)
 		protected.POST("/students/:id/graduation-request", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.RequestGraduation)
 		protected.GET("/students/:id/graduation-request", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetGraduationRequestByStudentID)
 		protected.GET("/students/:id/graduation-requests", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetGraduationRequestsByStudentID)
 		protected.PUT("/students/:id/graduation-request", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateGraduationRequest)
 		protected.DELETE("/students/:id/graduation-request", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteGraduationRequest)
 		protected.GET("/graduation-requests", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.GetGraduationRequests)
+		protected.GET("/graduation-requests/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetGraduationRequestByID)
+		protected.PUT("/graduation-requests/:id/review", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.ReviewGraduationRequest)
+		protected.PUT("/graduation-requests/:id/thesis", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.AssignThesis)
+		protected.PUT("/graduation-requests/:id/defense", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "PROFESSOR"}), ctrl.ScheduleDefense)
+		protected.PUT("/graduation-requests/:id/defense/grade", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.RecordDefense)
+		protected.POST("/graduation-requests/:id/diploma", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.IssueDiploma)
+		protected.GET("/students/:id/diploma", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentDiploma)
 
 		// Professors
 		protected.POST("/professors/create", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateProfessor)
@@ -53,6 +70,12 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.GET("/subjects/passed/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetPassedSubjectsForStudent)
 		protected.PUT("/subject/:id", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.UpdateSubject)
 		protected.DELETE("/subject/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteSubject)
+		protected.PUT("/subject/:id/prerequisites", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.SetSubjectPrerequisites)
+		protected.GET("/subject/:id/grading-scheme", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetGradingScheme)
+		protected.PUT("/subject/:id/grading-scheme", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.SetGradingScheme)
+		protected.GET("/subject/:id/points", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetSubjectPoints)
+		protected.PUT("/subject/:id/points", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT"}), ctrl.RecordComponentPoints)
+		protected.GET("/subject/:id/points/:studentId", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentPoints)
 
 		// Departments
 		protected.POST("/departments/create", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateDepartment)
@@ -66,6 +89,20 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.POST("/majors", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.CreateMajor)
 		protected.PUT("/majors/:id", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.UpdateMajor)
 		protected.DELETE("/majors/:id", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.DeleteMajor)
+		protected.GET("/majors/:id/eligibility-rules", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.GetEligibilityRules)
+		protected.PUT("/majors/:id/eligibility-rules", middleware.AuthorizeRoles([]string{"ADMIN", "ADMINISTRATOR"}), ctrl.UpdateEligibilityRules)
+		protected.GET("/majors/:id/exam-scheduling-policy", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.GetExamSchedulingPolicy)
+		protected.PUT("/majors/:id/exam-scheduling-policy", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.UpdateExamSchedulingPolicy)
+		protected.GET("/majors/:id/elective-groups", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetElectiveGroups)
+		protected.PUT("/majors/:id/elective-groups", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.SetElectiveGroups)
+		protected.PUT("/majors/:id/index-pattern", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.SetIndexPattern)
+		protected.PUT("/majors/:id/tuition", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.SetMajorTuition)
+		protected.GET("/majors/:id/curriculum-versions", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetCurriculumVersions)
+		protected.POST("/majors/:id/curriculum-versions", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.PublishCurriculumVersion)
+		protected.GET("/majors/:id/curriculum-versions/compare", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.CompareCurriculumVersions)
+		protected.GET("/majors/:id/curriculum-versions/:version", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetCurriculumVersion)
+		protected.GET("/majors/:id/grade-appeal-policy", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.GetGradeAppealPolicy)
+		protected.PUT("/majors/:id/grade-appeal-policy", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "ADMIN", "ADMINISTRATOR"}), ctrl.UpdateGradeAppealPolicy)
 
 		// University
 		protected.POST("/universities/create", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateUniversity)
@@ -73,20 +110,31 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.PUT("/universities/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateUniversity)
 		protected.DELETE("/universities/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteUniversity)
 
+		// Cascading deletes: dry run of what a delete would remove, unlink or be blocked by
+		protected.GET("/delete-preview/:entity/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.PreviewDelete)
+
 		// New exam system routes
 		// ExamSession routes
 		protected.POST("/exam-sessions/create", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.CreateExamSession)
 		protected.GET("/exam-sessions/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamSessionByID)
 		protected.PUT("/exam-sessions/:id", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.UpdateExamSession)
 		protected.DELETE("/exam-sessions/:id", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.DeleteExamSession)
+		protected.GET("/exam-sessions/:id/roster", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamRoster)
+		protected.POST("/exam-sessions/:id/roster/check-in", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.CheckInStudent)
+		protected.PUT("/exam-sessions/:id/roster/absences", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.MarkExamAbsences)
+		protected.POST("/exam-sessions/:id/grades/bulk", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.CreateExamGradesBulk)
+		protected.GET("/exam-sessions/:id/grading-progress", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamGradingProgress)
 		protected.GET("/exam-sessions/professor/:professorId", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamSessionsByProfessor)
 		protected.GET("/exam-sessions/student/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamSessionsByMajor)
 
 		// ExamRegistration routes
+		protected.GET("/exam-registrations/eligibility/:studentId/:examSessionId", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.CheckExamEligibility)
 		protected.POST("/exam-registrations/register", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.RegisterForExam)
 		protected.DELETE("/exam-registrations/deregister/:studentId/:examSessionId", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.DeregisterFromExam)
 		protected.GET("/exam-registrations/student/:studentId", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetExamRegistrationsByStudent)
 		protected.GET("/exam-registrations/exam-session/:examSessionId", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamRegistrationsByExamSession)
+		protected.GET("/exam-registrations/exam-session/:examSessionId/waitlist", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamWaitlist)
+		protected.PUT("/exam-registrations/:id/absent", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.MarkExamRegistrationAbsent)
 
 		// ExamGrade routes
 		protected.POST("/exam-grades/create", middleware.AuthorizeRoles([]string{"PROFESSOR"}), ctrl.CreateExamGrade)
@@ -96,6 +144,11 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.GET("/exam-grades/exam-session/:examSessionId", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamGradesByExamSession)
 		protected.GET("/exam-grades/student/:studentId/exam-session/:examSessionId", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamGradeByStudentAndExam)
 		protected.DELETE("/exam-grades/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA", "PROFESSOR"}), ctrl.DeleteExamGrade)
+		protected.POST("/exam-grades/:id/appeal", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.FileGradeAppeal)
+		protected.GET("/grade-appeals/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetGradeAppealByID)
+		protected.GET("/grade-appeals/student/:studentId", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetGradeAppealsByStudent)
+		protected.GET("/grade-appeals/reviewer/:professorId", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetGradeAppealsForReviewer)
+		protected.PUT("/grade-appeals/:id/decision", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.DecideGradeAppeal)
 
 		// Exam periods (when exams can be scheduled)
 		protected.POST("/exam-periods", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateExamPeriod)
@@ -104,6 +157,27 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.GET("/exam-periods/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetExamPeriodByID)
 		protected.PUT("/exam-periods/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateExamPeriod)
 		protected.DELETE("/exam-periods/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteExamPeriod)
+		protected.POST("/exam-periods/generate", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.GenerateExamPeriods)
+		protected.GET("/exam-period-templates", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.GetExamPeriodTemplates)
+		protected.POST("/exam-period-templates", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateExamPeriodTemplate)
+		protected.PUT("/exam-period-templates/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateExamPeriodTemplate)
+		protected.DELETE("/exam-period-templates/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteExamPeriodTemplate)
+
+		// Academic calendar
+		protected.POST("/academic-years", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateAcademicYear)
+		protected.GET("/academic-years", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetAcademicYears)
+		protected.GET("/academic-years/current", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetCurrentAcademicYear)
+		protected.GET("/academic-years/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetAcademicYearByID)
+		protected.PUT("/academic-years/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateAcademicYear)
+		protected.DELETE("/academic-years/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteAcademicYear)
+
+		// Rooms (lecture halls reserved for exams)
+		protected.POST("/rooms", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateRoom)
+		protected.GET("/rooms", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetAllRooms)
+		protected.GET("/rooms/occupancy", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetRoomOccupancy)
+		protected.GET("/rooms/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetRoomByID)
+		protected.PUT("/rooms/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateRoom)
+		protected.DELETE("/rooms/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteRoom)
 
 		// Admins
 		protected.POST("/administrators/create", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateAdministrator)
@@ -126,6 +200,27 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 		protected.GET("/notifications", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetAllNotificationsHandler)
 		protected.DELETE("/notifications/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.DeleteNotificationHandler)
 
+		// Lecture timetable
+		protected.GET("/timetable", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetTimetableSlots)
+		protected.POST("/timetable", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateTimetableSlot)
+		protected.PUT("/timetable/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.UpdateTimetableSlot)
+		protected.DELETE("/timetable/:id", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.DeleteTimetableSlot)
+		protected.GET("/timetable/student/:studentId", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentSchedule)
+		protected.GET("/timetable/professor/:professorId", middleware.AuthorizeRoles([]string{"PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetProfessorSchedule)
+		protected.GET("/timetable/assistant/:assistantId", middleware.AuthorizeRoles([]string{"ASSISTANT", "PROFESSOR", "STUDENTSKA_SLUZBA"}), ctrl.GetAssistantSchedule)
+
+		// Office hours (konsultacije)
+		protected.GET("/office-hours", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetOfficeHourSlots)
+		protected.POST("/office-hours", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT"}), ctrl.CreateOfficeHourSlot)
+		protected.GET("/office-hours/agenda", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetOfficeHourAgenda)
+		protected.GET("/office-hours/:id", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetOfficeHourSlotByID)
+		protected.PUT("/office-hours/:id", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.UpdateOfficeHourSlot)
+		protected.DELETE("/office-hours/:id", middleware.AuthorizeRoles([]string{"PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.DeleteOfficeHourSlot)
+		protected.GET("/office-hours/:id/availability", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.GetOfficeHourAvailability)
+		protected.POST("/office-hours/:id/bookings", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.BookOfficeHour)
+		protected.PUT("/office-hour-bookings/:id/cancel", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT", "STUDENTSKA_SLUZBA"}), ctrl.CancelOfficeHourBooking)
+		protected.GET("/students/:id/office-hour-bookings", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentOfficeHourBookings)
+
 		// Internship
 		protected.POST("/internship/apply/:id", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.CreateInternshipApplication)
 		protected.GET("/internship_application/:id", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetInternshipApplicationById)
@@ -137,7 +232,10 @@ func RegisterRoutes(router *gin.Engine, ctrl *controllers.Controllers) {
 
 		// Misc
 
-		protected.GET("/lectures", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR"}), ctrl.GetLectures)
+		protected.GET("/lectures", middleware.AuthorizeRoles([]string{"STUDENT", "PROFESSOR", "ASSISTANT"}), ctrl.GetLectures)
 		protected.POST("/tuition/pay", middleware.AuthorizeRoles([]string{"STUDENT"}), ctrl.PayTuition)
+		protected.GET("/students/:id/ledger", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentLedger)
+		protected.GET("/students/:id/balance", middleware.AuthorizeRoles([]string{"STUDENT", "STUDENTSKA_SLUZBA"}), ctrl.GetStudentBalance)
+		protected.POST("/students/:id/ledger", middleware.AuthorizeRoles([]string{"STUDENTSKA_SLUZBA"}), ctrl.CreateLedgerEntry)
 	}
 }